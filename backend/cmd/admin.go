@@ -0,0 +1,183 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"golang.org/x/term"
+)
+
+// runAdmin manages admin accounts
+func runAdmin(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing admin action (expected: create, reset-password, set-role, list)")
+	}
+
+	switch args[0] {
+	case "create":
+		return adminCreate(args[1:])
+	case "reset-password":
+		return adminResetPassword(args[1:])
+	case "set-role":
+		return adminSetRole(args[1:])
+	case "list":
+		return adminList(args[1:])
+	default:
+		return fmt.Errorf("unknown admin action %q (expected: create, reset-password, set-role, list)", args[0])
+	}
+}
+
+// adminCreate creates an admin account
+func adminCreate(args []string) error {
+	flags := flag.NewFlagSet("admin create", flag.ExitOnError)
+	username := flags.String("username", "", "login username (required)")
+	password := flags.String("password", "", "login password (prompted when empty)")
+	email := flags.String("email", "", "email address")
+	fullName := flags.String("full-name", "", "display name")
+	role := flags.String("role", string(models.RoleStaff), "owner or staff; owners may also read the audit log")
+	flags.Parse(args)
+
+	if *username == "" {
+		return fmt.Errorf("--username is required")
+	}
+	if err := promptPasswordIfEmpty(password); err != nil {
+		return err
+	}
+
+	authService, err := newAuthService()
+	if err != nil {
+		return err
+	}
+
+	admin, err := authService.CreateAdmin(cliContext(), *username, *password, *email, *fullName, models.AdminRole(*role))
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Admin account created: %s (id %d, %s)", admin.Username, admin.ID, admin.Role)
+	return nil
+}
+
+// adminResetPassword replaces the password of an admin account
+func adminResetPassword(args []string) error {
+	flags := flag.NewFlagSet("admin reset-password", flag.ExitOnError)
+	username := flags.String("username", "", "login username (required)")
+	password := flags.String("password", "", "new password (prompted when empty)")
+	flags.Parse(args)
+
+	if *username == "" {
+		return fmt.Errorf("--username is required")
+	}
+	if err := promptPasswordIfEmpty(password); err != nil {
+		return err
+	}
+
+	authService, err := newAuthService()
+	if err != nil {
+		return err
+	}
+
+	if err := authService.ResetPassword(cliContext(), *username, *password); err != nil {
+		return err
+	}
+
+	log.Printf("Password reset for %s", *username)
+	return nil
+}
+
+// adminSetRole changes the role of an admin account
+func adminSetRole(args []string) error {
+	flags := flag.NewFlagSet("admin set-role", flag.ExitOnError)
+	username := flags.String("username", "", "login username (required)")
+	role := flags.String("role", "", "owner or staff (required)")
+	flags.Parse(args)
+
+	if *username == "" || *role == "" {
+		return fmt.Errorf("--username and --role are required")
+	}
+
+	authService, err := newAuthService()
+	if err != nil {
+		return err
+	}
+
+	if err := authService.SetRole(cliContext(), *username, models.AdminRole(*role)); err != nil {
+		return err
+	}
+
+	log.Printf("%s is now %s, from their next login", *username, *role)
+	return nil
+}
+
+// adminList prints all admin accounts
+func adminList(args []string) error {
+	flags := flag.NewFlagSet("admin list", flag.ExitOnError)
+	flags.Parse(args)
+
+	authService, err := newAuthService()
+	if err != nil {
+		return err
+	}
+
+	admins, _, err := authService.ListAdmins(context.Background(), -1, -1)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%-5s %-20s %-6s %-30s %s\n", "ID", "USERNAME", "ROLE", "EMAIL", "FULL NAME")
+	for _, admin := range admins {
+		fmt.Printf("%-5d %-20s %-6s %-30s %s\n", admin.ID, admin.Username, admin.Role, admin.Email, admin.FullName)
+	}
+	return nil
+}
+
+// newAuthService wires the auth service for admin commands
+func newAuthService() (*services.AuthService, error) {
+	cfg, db, err := bootstrap()
+	if err != nil {
+		return nil, err
+	}
+	jwtManager := utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.TokenTTL)
+	audit := services.NewAuditService(repositories.NewAuditRepository(db))
+	return services.NewAuthService(repositories.NewAdminRepository(db), jwtManager, repositories.NewTransactor(db), audit), nil
+}
+
+// promptPasswordIfEmpty reads a password from stdin when it was not given as a flag. On a
+// terminal the password is not echoed; piped input is read up to the first newline.
+func promptPasswordIfEmpty(password *string) error {
+	if *password != "" {
+		return nil
+	}
+
+	fmt.Print("Password: ")
+	var line string
+	if fd := int(os.Stdin.Fd()); term.IsTerminal(fd) {
+		input, err := term.ReadPassword(fd)
+		fmt.Println()
+		if err != nil {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+		line = string(input)
+	} else {
+		var err error
+		line, err = bufio.NewReader(os.Stdin).ReadString('\n')
+		if err != nil && line == "" {
+			return fmt.Errorf("failed to read password: %w", err)
+		}
+	}
+
+	*password = strings.TrimSpace(line)
+	if *password == "" {
+		return fmt.Errorf("password must not be empty")
+	}
+	return nil
+}
@@ -1,46 +1,102 @@
 package main
 
 import (
+	"context"
+	"flag"
+	"fmt"
 	"log"
+	"os"
 
 	"github.com/RidwanRamdhani/chronos-laundry/backend/config"
-	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
-	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
-	"github.com/RidwanRamdhani/chronos-laundry/backend/routes"
-	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
-	"github.com/joho/godotenv"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"gorm.io/gorm"
 )
 
+const usage = `Usage: chronos-laundry [--config file.yaml] <command> [arguments]
+
+Commands:
+  serve                         Start the HTTP API server (default)
+  migrate [up|down [n]|status]  Apply, roll back or inspect schema migrations
+  seed prices --file <path>     Insert service prices from a JSON file
+  prices export                 Export the service price catalog as CSV or JSON
+  prices import --file <path>   Replace the service price catalog from a file
+  admin create                  Create an admin account
+  admin reset-password          Reset an admin password
+  admin set-role                Make an admin an owner or staff
+  admin list                    List admin accounts
+  search reindex                Rebuild the transaction search index
+
+Configuration is read from the YAML file given by --config (or CONFIG_FILE),
+then overridden by environment variables and a .env file.
+
+Run "chronos-laundry <command> -h" for the flags of a command.
+`
+
+// configPath is the optional YAML configuration file shared by every command
+var configPath string
+
 func main() {
-	// Load .env file
-	if err := godotenv.Load(); err != nil {
-		log.Println("Warning: .env file not found, using system environment variables")
+	flag.StringVar(&configPath, "config", os.Getenv("CONFIG_FILE"), "path to a YAML configuration file")
+	flag.Usage = func() { fmt.Fprint(os.Stderr, usage) }
+	flag.Parse()
+
+	command := "serve"
+	args := []string{}
+	if flag.NArg() > 0 {
+		command = flag.Arg(0)
+		args = flag.Args()[1:]
 	}
 
-	// Initialize DB connection
-	if err := config.InitDB(); err != nil {
-		log.Fatalf("Database initialization failed: %v", err)
+	var err error
+	switch command {
+	case "serve":
+		err = runServe(args)
+	case "migrate":
+		err = runMigrate(args)
+	case "seed":
+		err = runSeed(args)
+	case "prices":
+		err = runPrices(args)
+	case "admin":
+		err = runAdmin(args)
+	case "search":
+		err = runSearch(args)
+	case "help", "-h", "--help":
+		fmt.Print(usage)
+		return
+	default:
+		fmt.Fprintf(os.Stderr, "unknown command %q\n\n%s", command, usage)
+		os.Exit(2)
 	}
 
-	db := config.GetDB()
+	if err != nil {
+		log.Fatalf("%s: %v", command, err)
+	}
+}
 
-	// Repositories
-	adminRepo := repositories.NewAdminRepository(db)
-	transactionRepo := repositories.NewTransactionRepository(db)
-	historyRepo := repositories.NewTransactionHistoryRepository(db)
-	servicePriceRepo := repositories.NewServicePriceRepository(db)
+// bootstrap loads the configuration and opens the database connection shared by every command
+func bootstrap() (*config.Config, *gorm.DB, error) {
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return nil, nil, err
+	}
 
-	// Services
-	authService := services.NewAuthService(adminRepo)
-	transactionService := services.NewTransactionService(transactionRepo, historyRepo)
-	servicePriceService := services.NewServicePriceService(servicePriceRepo)
+	db, err := openDB(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+	return cfg, db, nil
+}
 
-	// Controllers
-	authController := controllers.NewAuthController(authService)
-	transactionController := controllers.NewTransactionController(transactionService, servicePriceService)
-	servicePriceController := controllers.NewServicePriceController(servicePriceService)
+// openDB initializes the database connection from the configuration
+func openDB(cfg *config.Config) (*gorm.DB, error) {
+	if err := config.InitDB(cfg.Database); err != nil {
+		return nil, fmt.Errorf("database initialization failed: %w", err)
+	}
+	return config.GetDB(), nil
+}
 
-	// Router
-	r := routes.SetupRouter(authController, transactionController, servicePriceController)
-	r.Run(":8080")
+// cliContext is the context of commands that change data, recorded as "cli" in the audit log
+func cliContext() context.Context {
+	return utils.WithActor(context.Background(), utils.Actor{Username: "cli"})
 }
@@ -0,0 +1,134 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+)
+
+// runPrices exports or imports the service price catalog
+func runPrices(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing prices action (expected: export, import)")
+	}
+
+	switch args[0] {
+	case "export":
+		return pricesExport(args[1:])
+	case "import":
+		return pricesImport(args[1:])
+	default:
+		return fmt.Errorf("unknown prices action %q (expected: export, import)", args[0])
+	}
+}
+
+// pricesExport writes the full catalog to a file or stdout
+func pricesExport(args []string) error {
+	flags := flag.NewFlagSet("prices export", flag.ExitOnError)
+	formatName := flags.String("format", "csv", "output format: csv or json")
+	out := flags.String("out", "", "output file (stdout when empty)")
+	flags.Parse(args)
+
+	format, err := services.ParseCatalogFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	servicePriceService, err := newServicePriceService()
+	if err != nil {
+		return err
+	}
+
+	entries, err := servicePriceService.ExportCatalog(context.Background())
+	if err != nil {
+		return err
+	}
+
+	writer := os.Stdout
+	if *out != "" {
+		file, err := os.Create(*out)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *out, err)
+		}
+		defer file.Close()
+		writer = file
+	}
+
+	if err := services.WriteCatalog(writer, format, entries); err != nil {
+		return err
+	}
+	if *out != "" {
+		log.Printf("Exported %d service prices to %s", len(entries), *out)
+	}
+	return nil
+}
+
+// pricesImport replaces the catalog from a file, printing the diff first
+func pricesImport(args []string) error {
+	flags := flag.NewFlagSet("prices import", flag.ExitOnError)
+	file := flags.String("file", "", "CSV or JSON catalog file (required)")
+	formatName := flags.String("format", "", "input format: csv or json (defaults to the file extension)")
+	dryRun := flags.Bool("dry-run", false, "show the diff without applying it")
+	flags.Parse(args)
+
+	if *file == "" {
+		return fmt.Errorf("--file is required")
+	}
+	if *formatName == "" {
+		*formatName = strings.TrimPrefix(filepath.Ext(*file), ".")
+	}
+
+	format, err := services.ParseCatalogFormat(*formatName)
+	if err != nil {
+		return err
+	}
+
+	input, err := os.Open(*file)
+	if err != nil {
+		return fmt.Errorf("failed to open %s: %w", *file, err)
+	}
+	defer input.Close()
+
+	entries, err := services.ReadCatalog(input, format)
+	if err != nil {
+		return err
+	}
+
+	servicePriceService, err := newServicePriceService()
+	if err != nil {
+		return err
+	}
+
+	diff, err := servicePriceService.ImportCatalog(cliContext(), entries, *dryRun)
+	if err != nil {
+		return err
+	}
+
+	encoder := json.NewEncoder(os.Stdout)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(diff); err != nil {
+		return err
+	}
+
+	log.Printf("%d added, %d changed, %d deactivated, %d unchanged (applied: %t)",
+		len(diff.Added), len(diff.Changed), len(diff.Deactivated), diff.Unchanged, diff.Applied)
+	return nil
+}
+
+// newServicePriceService wires the service price service for catalog commands
+func newServicePriceService() (*services.ServicePriceService, error) {
+	_, db, err := bootstrap()
+	if err != nil {
+		return nil, err
+	}
+	audit := services.NewAuditService(repositories.NewAuditRepository(db))
+	return services.NewServicePriceService(repositories.NewServicePriceRepository(db), repositories.NewTransactor(db), nil, audit), nil
+}
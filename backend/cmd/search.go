@@ -0,0 +1,56 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+)
+
+// runSearch maintains the transaction search index
+func runSearch(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing search action (expected: reindex)")
+	}
+
+	switch args[0] {
+	case "reindex":
+		return searchReindex(args[1:])
+	default:
+		return fmt.Errorf("unknown search action %q (expected: reindex)", args[0])
+	}
+}
+
+// searchReindex rebuilds the search terms of every transaction
+func searchReindex(args []string) error {
+	flags := flag.NewFlagSet("search reindex", flag.ExitOnError)
+	flags.Parse(args)
+
+	_, db, err := bootstrap()
+	if err != nil {
+		return err
+	}
+
+	transactionService := services.NewTransactionService(
+		repositories.NewTransactionRepository(db),
+		repositories.NewTransactionHistoryRepository(db),
+		repositories.NewTransactionSearchRepository(db),
+		repositories.NewPaymentRepository(db),
+		repositories.NewShiftRepository(db),
+		repositories.NewServicePriceRepository(db),
+		repositories.NewCustomerRepository(db),
+		repositories.NewTransactor(db),
+		nil,
+		services.NewAuditService(repositories.NewAuditRepository(db)),
+	)
+	indexed, err := transactionService.ReindexTransactions(context.Background())
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Search index rebuilt for %d transactions", indexed)
+	return nil
+}
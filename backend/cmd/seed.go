@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+)
+
+// runSeed inserts initial data from files
+func runSeed(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing seed target (expected: prices)")
+	}
+
+	switch args[0] {
+	case "prices":
+		return seedPrices(args[1:])
+	default:
+		return fmt.Errorf("unknown seed target %q (expected: prices)", args[0])
+	}
+}
+
+// seedPrices inserts service prices from a JSON file, skipping existing ones
+func seedPrices(args []string) error {
+	flags := flag.NewFlagSet("seed prices", flag.ExitOnError)
+	file := flags.String("file", "seeds/service_prices.json", "JSON file with the service prices to insert")
+	flags.Parse(args)
+
+	content, err := os.ReadFile(*file)
+	if err != nil {
+		return fmt.Errorf("failed to read %s: %w", *file, err)
+	}
+
+	var servicePrices []models.ServicePrice
+	if err := json.Unmarshal(content, &servicePrices); err != nil {
+		return fmt.Errorf("failed to parse %s: %w", *file, err)
+	}
+
+	_, db, err := bootstrap()
+	if err != nil {
+		return err
+	}
+
+	audit := services.NewAuditService(repositories.NewAuditRepository(db))
+	servicePriceService := services.NewServicePriceService(repositories.NewServicePriceRepository(db), repositories.NewTransactor(db), nil, audit)
+	created, skipped, err := servicePriceService.SeedServicePrices(cliContext(), servicePrices)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("Service prices seeding completed: %d created, %d already existed", created, skipped)
+	return nil
+}
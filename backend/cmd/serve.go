@@ -0,0 +1,315 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/config"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/docs"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/migrations"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/notification"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/routes"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// runServe starts the HTTP API server and shuts it down gracefully on SIGINT or SIGTERM
+func runServe(args []string) error {
+	flags := flag.NewFlagSet("serve", flag.ExitOnError)
+	flags.Parse(args)
+
+	cfg, err := config.Load(configPath)
+	if err != nil {
+		return err
+	}
+	if err := cfg.Validate(); err != nil {
+		return err
+	}
+
+	logger, err := utils.NewLogger(os.Stdout, cfg.Log.Level, cfg.Log.Format)
+	if err != nil {
+		return err
+	}
+	slog.SetDefault(logger)
+
+	db, err := openDB(cfg)
+	if err != nil {
+		return err
+	}
+	defer config.CloseDB()
+
+	if err := db.Use(metrics.GormPlugin{}); err != nil {
+		return err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return err
+	}
+	metrics.RegisterDBStats(sqlDB)
+
+	migrator, err := migrations.NewMigrator(db)
+	if err != nil {
+		return err
+	}
+	warnPendingMigrations(migrator)
+
+	// Repositories
+	adminRepo := repositories.NewAdminRepository(db)
+	transactionRepo := repositories.NewTransactionRepository(db)
+	historyRepo := repositories.NewTransactionHistoryRepository(db)
+	servicePriceRepo := repositories.NewServicePriceRepository(db)
+	searchRepo := repositories.NewTransactionSearchRepository(db)
+	reportRepo := repositories.NewReportRepository(db)
+	paymentRepo := repositories.NewPaymentRepository(db)
+	shiftRepo := repositories.NewShiftRepository(db)
+	notificationRepo := repositories.NewNotificationRepository(db)
+	webhookRepo := repositories.NewWebhookRepository(db)
+	outboxRepo := repositories.NewOutboxRepository(db)
+	transactor := repositories.NewTransactor(db)
+	auditRepo := repositories.NewAuditRepository(db)
+	customerRepo := repositories.NewCustomerRepository(db)
+
+	// Audit log, written with every change
+	auditService := services.NewAuditService(auditRepo)
+
+	// Customer notifications
+	channels, closeChannels, err := notificationChannels(cfg.Notifications)
+	if err != nil {
+		return err
+	}
+	defer closeChannels()
+	statuses := make([]models.TransactionStatus, len(cfg.Notifications.Statuses))
+	for i, status := range cfg.Notifications.Statuses {
+		statuses[i] = models.TransactionStatus(status)
+	}
+	notificationService := services.NewNotificationService(notificationRepo, channels, services.NotificationSettings{
+		Statuses:     statuses,
+		Language:     cfg.Notifications.Language,
+		LaundryName:  cfg.Notifications.LaundryName,
+		PollInterval: cfg.Notifications.PollInterval,
+		SendTimeout:  cfg.Notifications.SendTimeout,
+		MaxAttempts:  cfg.Notifications.MaxAttempts,
+		RetryBackoff: cfg.Notifications.RetryBackoff,
+	})
+
+	// Partner webhooks
+	webhookService := services.NewWebhookService(webhookRepo, transactor, auditService, services.WebhookSettings{
+		PollInterval: cfg.Webhooks.PollInterval,
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		RetryBackoff: cfg.Webhooks.RetryBackoff,
+		Timeout:      cfg.Webhooks.Timeout,
+	})
+
+	// Domain events, dispatched from the outbox to the subscribers on the bus
+	bus := events.NewBus()
+	eventService := services.NewEventService(outboxRepo, bus, services.EventSettings{
+		PollInterval: cfg.Events.PollInterval,
+		MaxAttempts:  cfg.Events.MaxAttempts,
+		RetryBackoff: cfg.Events.RetryBackoff,
+		Retention:    cfg.Events.Retention,
+	})
+
+	// Services
+	jwtManager := utils.NewJWTManager(cfg.JWT.Secret, cfg.JWT.TokenTTL)
+	authService := services.NewAuthService(adminRepo, jwtManager, transactor, auditService)
+	transactionService := services.NewTransactionService(transactionRepo, historyRepo, searchRepo, paymentRepo, shiftRepo, servicePriceRepo, customerRepo, transactor, eventService, auditService)
+	servicePriceService := services.NewServicePriceService(servicePriceRepo, transactor, eventService, auditService)
+	reportService := services.NewReportService(reportRepo)
+	shiftService := services.NewShiftService(shiftRepo, transactor, auditService)
+	healthService := services.NewHealthService(db, migrator)
+	trashService := services.NewTrashService(transactionRepo, servicePriceRepo, transactor, eventService, auditService, services.TrashSettings{
+		Retention:     cfg.Trash.Retention,
+		PurgeInterval: cfg.Trash.PurgeInterval,
+	})
+
+	// Customer portal, logging customers in with codes sent through the OTP channel
+	otpSender, closeOTPSender, err := notificationChannel(cfg.Customers.OTPChannel, cfg.Notifications)
+	if err != nil {
+		return err
+	}
+	defer closeOTPSender()
+	if cfg.Customers.OTPChannel == notification.ChannelLog {
+		slog.Warn("customer login codes are only logged, set CUSTOMER_OTP_CHANNEL to send them to customers")
+	}
+	customerService := services.NewCustomerService(customerRepo, transactionRepo, transactor, eventService, auditService, jwtManager, otpSender, services.CustomerSettings{
+		OTPTTL:         cfg.Customers.OTPTTL,
+		OTPMaxAttempts: cfg.Customers.OTPMaxAttempts,
+		OTPResend:      cfg.Customers.OTPResend,
+		TokenTTL:       cfg.Customers.TokenTTL,
+		SendTimeout:    cfg.Notifications.SendTimeout,
+		Language:       cfg.Notifications.Language,
+		LaundryName:    cfg.Notifications.LaundryName,
+	})
+
+	// Live streams of the order board and tracking pages
+	streamService := services.NewStreamService(outboxRepo, services.StreamSettings{
+		PollInterval: cfg.Streams.PollInterval,
+		MaxClients:   cfg.Streams.MaxClients,
+		GapTimeout:   cfg.Streams.GapTimeout,
+	})
+	metrics.RegisterOpenStreams(streamService.Clients)
+
+	// Subscribers
+	transactionService.Subscribe(bus)
+	notificationService.Subscribe(bus)
+	webhookService.Subscribe(bus)
+	streamService.Subscribe(bus)
+
+	// Controllers
+	authController := controllers.NewAuthController(authService)
+	transactionController := controllers.NewTransactionController(transactionService, servicePriceService)
+	servicePriceController := controllers.NewServicePriceController(servicePriceService)
+	reportController := controllers.NewReportController(reportService)
+	shiftController := controllers.NewShiftController(shiftService)
+	notificationController := controllers.NewNotificationController(notificationService)
+	webhookController := controllers.NewWebhookController(webhookService)
+	streamController := controllers.NewStreamController(streamService, transactionService, cfg.Streams.Heartbeat)
+	auditController := controllers.NewAuditController(auditService)
+	trashController := controllers.NewTrashController(trashService)
+	customerController := controllers.NewCustomerController(customerService, cfg.Customers.OTPResend)
+	healthController := controllers.NewHealthController(healthService)
+	metricsController := controllers.NewMetricsController(metrics.Default, cfg.Metrics.Token)
+	docsController := controllers.NewDocsController(docs.OpenAPISpec)
+
+	// Router
+	r := routes.SetupRouter(cfg, jwtManager, routes.Controllers{
+		Auth:         authController,
+		Transaction:  transactionController,
+		ServicePrice: servicePriceController,
+		Report:       reportController,
+		Shift:        shiftController,
+		Notification: notificationController,
+		Webhook:      webhookController,
+		Stream:       streamController,
+		Audit:        auditController,
+		Trash:        trashController,
+		Customer:     customerController,
+		Health:       healthController,
+		Metrics:      metricsController,
+		Docs:         docsController,
+	})
+
+	server := &http.Server{
+		Addr:              cfg.Server.Addr(),
+		Handler:           r,
+		ReadHeaderTimeout: cfg.Server.ReadHeaderTimeout,
+		ReadTimeout:       cfg.Server.ReadTimeout,
+		WriteTimeout:      cfg.Server.WriteTimeout,
+		IdleTimeout:       cfg.Server.IdleTimeout,
+	}
+	// Open streams never finish on their own; end them so shutdown does not wait for them
+	server.RegisterOnShutdown(streamService.Shutdown)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Dispatch and prune events, feed the live streams, deliver queued notifications and
+	// webhooks and purge the trash until the server has stopped
+	workerCtx, stopWorkers := context.WithCancel(context.Background())
+	var workers sync.WaitGroup
+	workers.Go(func() { eventService.Run(workerCtx) })
+	workers.Go(func() { eventService.RunPruning(workerCtx) })
+	workers.Go(func() { streamService.Run(workerCtx) })
+	workers.Go(func() { notificationService.Run(workerCtx) })
+	workers.Go(func() { webhookService.Run(workerCtx) })
+	workers.Go(func() { trashService.Run(workerCtx) })
+	defer func() {
+		stopWorkers()
+		workers.Wait()
+	}()
+
+	serverErr := make(chan error, 1)
+	go func() {
+		slog.Info("server listening", slog.String("addr", server.Addr))
+		serverErr <- server.ListenAndServe()
+	}()
+
+	select {
+	case err := <-serverErr:
+		if !errors.Is(err, http.ErrServerClosed) {
+			return err
+		}
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Stop accepting connections and let in-flight requests finish
+	slog.Info("shutting down, waiting for in-flight requests", slog.Duration("timeout", cfg.Server.ShutdownTimeout))
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout)
+	defer cancel()
+
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		return err
+	}
+	slog.Info("server stopped")
+	return nil
+}
+
+// notificationChannels builds the enabled notification channels. The returned function
+// closes the log channel's file, if any.
+func notificationChannels(cfg config.NotificationConfig) ([]notification.Channel, func(), error) {
+	closeFn := func() {}
+
+	var channels []notification.Channel
+	for _, name := range cfg.Channels {
+		channel, closeChannel, err := notificationChannel(name, cfg)
+		if err != nil {
+			closeFn()
+			return nil, nil, err
+		}
+		if name == notification.ChannelLog {
+			closeFn = closeChannel
+		}
+		channels = append(channels, channel)
+	}
+	return channels, closeFn, nil
+}
+
+// notificationChannel builds one channel with the provider settings in cfg. The returned
+// function closes the log channel's file, if any.
+func notificationChannel(name string, cfg config.NotificationConfig) (notification.Channel, func(), error) {
+	client := &http.Client{Timeout: cfg.SendTimeout}
+	switch name {
+	case notification.ChannelLog:
+		if cfg.LogFile == "" {
+			return notification.NewLogChannel(slog.Default()), func() {}, nil
+		}
+		file, err := os.OpenFile(cfg.LogFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o640)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to open notification log file: %w", err)
+		}
+		return notification.NewLogChannel(slog.New(slog.NewJSONHandler(file, nil))), func() { file.Close() }, nil
+	case notification.ChannelWhatsApp:
+		return notification.NewWhatsAppChannel(client, cfg.WhatsApp.APIURL, cfg.WhatsApp.PhoneNumberID, cfg.WhatsApp.AccessToken), func() {}, nil
+	case notification.ChannelSMS:
+		return notification.NewSMSChannel(client, cfg.SMS.URL, cfg.SMS.APIKey, cfg.SMS.Sender), func() {}, nil
+	case notification.ChannelEmail:
+		return notification.NewEmailChannel(cfg.SMTP.Host, cfg.SMTP.Port, cfg.SMTP.Username, cfg.SMTP.Password, cfg.SMTP.From), func() {}, nil
+	}
+	return nil, nil, fmt.Errorf("unknown notification channel %q", name)
+}
+
+// warnPendingMigrations logs a warning when the schema is behind the binary
+func warnPendingMigrations(migrator *migrations.Migrator) {
+	pending, err := migrator.Pending()
+	if err != nil {
+		slog.Warn("failed to check migrations", slog.String("error", err.Error()))
+		return
+	}
+	if len(pending) > 0 {
+		slog.Warn("pending migrations, run `migrate up` before serving traffic", slog.Int("pending", len(pending)))
+	}
+}
@@ -0,0 +1,619 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/goccy/go-yaml"
+	"github.com/joho/godotenv"
+)
+
+// Config holds the application configuration loaded once at startup
+type Config struct {
+	Server   ServerConfig   `yaml:"server"`
+	Database DatabaseConfig `yaml:"database"`
+	JWT      JWTConfig      `yaml:"jwt"`
+	Log      LogConfig      `yaml:"log"`
+	Metrics  MetricsConfig  `yaml:"metrics"`
+
+	Notifications NotificationConfig `yaml:"notifications"`
+	Webhooks      WebhookConfig      `yaml:"webhooks"`
+	Events        EventConfig        `yaml:"events"`
+	Streams       StreamConfig       `yaml:"streams"`
+	Trash         TrashConfig        `yaml:"trash"`
+	Tracking      TrackingConfig     `yaml:"tracking"`
+	Customers     CustomerConfig     `yaml:"customers"`
+}
+
+// ServerConfig configures the HTTP server
+type ServerConfig struct {
+	Port              int           `yaml:"port"`
+	Mode              string        `yaml:"mode"` // gin mode: debug, release or test
+	AllowedOrigins    []string      `yaml:"allowed_origins"`
+	TrustedProxies    []string      `yaml:"trusted_proxies"` // IPs or CIDRs whose X-Forwarded-For is believed, none by default
+	ReadHeaderTimeout time.Duration `yaml:"read_header_timeout"`
+	ReadTimeout       time.Duration `yaml:"read_timeout"`
+	WriteTimeout      time.Duration `yaml:"write_timeout"`
+	IdleTimeout       time.Duration `yaml:"idle_timeout"`
+	ShutdownTimeout   time.Duration `yaml:"shutdown_timeout"` // grace period for in-flight requests
+	RequestTimeout    time.Duration `yaml:"request_timeout"`  // deadline for handlers and their queries, 0 disables it
+	ExportTimeout     time.Duration `yaml:"export_timeout"`   // deadline for streaming exports, replacing the two above, 0 disables it
+}
+
+// DatabaseConfig configures the MySQL connection and its pool
+type DatabaseConfig struct {
+	Host            string        `yaml:"host"`
+	Port            string        `yaml:"port"`
+	User            string        `yaml:"user"`
+	Password        string        `yaml:"password"`
+	Name            string        `yaml:"name"`
+	MaxOpenConns    int           `yaml:"max_open_conns"` // 0 means unlimited
+	MaxIdleConns    int           `yaml:"max_idle_conns"`
+	ConnMaxLifetime time.Duration `yaml:"conn_max_lifetime"` // 0 means connections are reused forever
+	ConnMaxIdleTime time.Duration `yaml:"conn_max_idle_time"`
+	SlowQuery       time.Duration `yaml:"slow_query"` // queries slower than this are logged as warnings
+}
+
+// JWTConfig configures admin token signing
+type JWTConfig struct {
+	Secret   string        `yaml:"secret"`
+	TokenTTL time.Duration `yaml:"token_ttl"`
+}
+
+// LogConfig configures structured logging
+type LogConfig struct {
+	Level  string `yaml:"level"`  // debug, info, warn or error
+	Format string `yaml:"format"` // json or text
+}
+
+// MetricsConfig configures the Prometheus endpoint
+type MetricsConfig struct {
+	Token string `yaml:"token"` // optional bearer token required to scrape /metrics
+}
+
+// NotificationConfig configures customer notifications and their delivery
+type NotificationConfig struct {
+	Channels     []string      `yaml:"channels"`      // log, whatsapp, sms and/or email; none disables notifications
+	Statuses     []string      `yaml:"statuses"`      // statuses that notify the customer: Queued, Ready to pick up, Completed
+	Language     string        `yaml:"language"`      // id or en, for customers without a language of their own
+	LaundryName  string        `yaml:"laundry_name"`  // signs every message
+	PollInterval time.Duration `yaml:"poll_interval"` // how often the outbox is checked for due messages
+	SendTimeout  time.Duration `yaml:"send_timeout"`  // deadline for one delivery attempt
+	MaxAttempts  int           `yaml:"max_attempts"`  // attempts before a message is marked as failed
+	RetryBackoff time.Duration `yaml:"retry_backoff"` // delay before the first retry, doubled on every attempt
+	LogFile      string        `yaml:"log_file"`      // where the log channel writes, the application log when empty
+
+	WhatsApp WhatsAppConfig `yaml:"whatsapp"`
+	SMS      SMSConfig      `yaml:"sms"`
+	SMTP     SMTPConfig     `yaml:"smtp"`
+}
+
+// WhatsAppConfig configures the WhatsApp Business Cloud API channel
+type WhatsAppConfig struct {
+	APIURL        string `yaml:"api_url"`
+	PhoneNumberID string `yaml:"phone_number_id"`
+	AccessToken   string `yaml:"access_token"`
+}
+
+// SMSConfig configures the HTTP SMS gateway channel
+type SMSConfig struct {
+	URL    string `yaml:"url"`
+	APIKey string `yaml:"api_key"`
+	Sender string `yaml:"sender"`
+}
+
+// SMTPConfig configures the email channel
+type SMTPConfig struct {
+	Host     string `yaml:"host"`
+	Port     int    `yaml:"port"`
+	Username string `yaml:"username"`
+	Password string `yaml:"password"`
+	From     string `yaml:"from"`
+}
+
+// WebhookConfig configures the delivery of partner webhooks
+type WebhookConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"` // how often the delivery queue is checked
+	Timeout      time.Duration `yaml:"timeout"`       // deadline for one delivery attempt
+	MaxAttempts  int           `yaml:"max_attempts"`  // attempts before a delivery is marked as failed
+	RetryBackoff time.Duration `yaml:"retry_backoff"` // delay before the first retry, doubled on every attempt
+}
+
+// EventConfig configures the dispatch of domain events from the outbox to their subscribers
+type EventConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"` // how often the outbox is checked for due events
+	MaxAttempts  int           `yaml:"max_attempts"`  // attempts before an event is marked as failed
+	RetryBackoff time.Duration `yaml:"retry_backoff"` // delay before the first retry, doubled on every attempt
+	Retention    time.Duration `yaml:"retention"`     // dispatched and failed events are pruned after this long, never when 0
+}
+
+// StreamConfig configures the live event streams of the order board and the tracking page
+type StreamConfig struct {
+	PollInterval time.Duration `yaml:"poll_interval"` // how often the outbox is checked for events from other instances
+	Heartbeat    time.Duration `yaml:"heartbeat"`     // keep-alive comment interval, below proxy idle timeouts
+	MaxClients   int           `yaml:"max_clients"`   // open streams per instance; further clients get 503
+	GapTimeout   time.Duration `yaml:"gap_timeout"`   // how long events wait behind an uncommitted earlier one, 0 never waits
+}
+
+// TrashConfig configures how long deleted transactions and service prices can be restored
+type TrashConfig struct {
+	Retention     time.Duration `yaml:"retention"`      // purged permanently after this long, never when 0
+	PurgeInterval time.Duration `yaml:"purge_interval"` // how often records past the retention are purged
+}
+
+// TrackingConfig configures the public tracking endpoints
+type TrackingConfig struct {
+	RateLimit  int           `yaml:"rate_limit"`  // requests per client IP and window, unlimited when 0
+	RateWindow time.Duration `yaml:"rate_window"` // window the rate limit is counted over
+}
+
+// CustomerConfig configures the customer portal and its one-time login codes
+type CustomerConfig struct {
+	OTPChannel     string        `yaml:"otp_channel"`      // log, whatsapp or sms, using the provider settings under notifications
+	OTPTTL         time.Duration `yaml:"otp_ttl"`          // how long a login code can be used
+	OTPMaxAttempts int           `yaml:"otp_max_attempts"` // wrong guesses before a login code stops working
+	OTPResend      time.Duration `yaml:"otp_resend"`       // minimum time between two codes for one phone number
+	TokenTTL       time.Duration `yaml:"token_ttl"`        // lifetime of customer tokens
+	RateLimit      int           `yaml:"rate_limit"`       // login requests per client IP and window, unlimited when 0
+	RateWindow     time.Duration `yaml:"rate_window"`      // window the rate limit is counted over
+}
+
+// Default returns the configuration used when nothing overrides it
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Port:              8080,
+			Mode:              "debug",
+			AllowedOrigins:    []string{"http://localhost:5173"},
+			ReadHeaderTimeout: 5 * time.Second,
+			ReadTimeout:       15 * time.Second,
+			WriteTimeout:      30 * time.Second,
+			IdleTimeout:       60 * time.Second,
+			ShutdownTimeout:   20 * time.Second,
+			RequestTimeout:    10 * time.Second,
+			ExportTimeout:     10 * time.Minute,
+		},
+		Database: DatabaseConfig{
+			Host:            "localhost",
+			Port:            "3306",
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+			SlowQuery:       200 * time.Millisecond,
+		},
+		JWT: JWTConfig{
+			TokenTTL: 6 * time.Hour,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "json",
+		},
+		Notifications: NotificationConfig{
+			Statuses:     []string{"Ready to pick up"},
+			Language:     "id",
+			LaundryName:  "Chronos Laundry",
+			PollInterval: 5 * time.Second,
+			SendTimeout:  15 * time.Second,
+			MaxAttempts:  5,
+			RetryBackoff: 30 * time.Second,
+			WhatsApp: WhatsAppConfig{
+				APIURL: "https://graph.facebook.com/v21.0",
+			},
+			SMTP: SMTPConfig{
+				Port: 587,
+			},
+		},
+		Webhooks: WebhookConfig{
+			PollInterval: 5 * time.Second,
+			Timeout:      10 * time.Second,
+			MaxAttempts:  8,
+			RetryBackoff: 30 * time.Second,
+		},
+		Events: EventConfig{
+			PollInterval: 5 * time.Second,
+			MaxAttempts:  10,
+			RetryBackoff: 10 * time.Second,
+			Retention:    30 * 24 * time.Hour,
+		},
+		Streams: StreamConfig{
+			PollInterval: time.Second,
+			Heartbeat:    15 * time.Second,
+			MaxClients:   500,
+			GapTimeout:   10 * time.Second,
+		},
+		Trash: TrashConfig{
+			Retention:     30 * 24 * time.Hour,
+			PurgeInterval: time.Hour,
+		},
+		Tracking: TrackingConfig{
+			RateLimit:  30,
+			RateWindow: time.Minute,
+		},
+		Customers: CustomerConfig{
+			OTPChannel:     "log",
+			OTPTTL:         5 * time.Minute,
+			OTPMaxAttempts: 5,
+			OTPResend:      time.Minute,
+			TokenTTL:       24 * time.Hour,
+			RateLimit:      10,
+			RateWindow:     time.Minute,
+		},
+	}
+}
+
+// Load builds the configuration from defaults, an optional YAML file and the environment.
+// Environment variables (including those from a .env file) take precedence over the YAML file.
+// The result is not validated; call Validate before using it.
+func Load(path string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := yaml.Unmarshal(content, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+		}
+	}
+
+	// A missing .env file is fine, the variables may come from the environment
+	_ = godotenv.Load()
+
+	if err := cfg.applyEnv(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// Validate checks the whole configuration and reports every problem at once
+func (c *Config) Validate() error {
+	var errs []error
+
+	if err := c.Database.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.Server.Port < 1 || c.Server.Port > 65535 {
+		errs = append(errs, fmt.Errorf("server.port (PORT) must be between 1 and 65535, got %d", c.Server.Port))
+	}
+	switch c.Server.Mode {
+	case "debug", "release", "test":
+	default:
+		errs = append(errs, fmt.Errorf("server.mode (GIN_MODE) must be debug, release or test, got %q", c.Server.Mode))
+	}
+	if c.Server.ReadHeaderTimeout < 0 || c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 || c.Server.RequestTimeout < 0 || c.Server.ExportTimeout < 0 {
+		errs = append(errs, errors.New("server timeouts must not be negative"))
+	}
+	if c.Server.WriteTimeout > 0 && c.Server.RequestTimeout >= c.Server.WriteTimeout {
+		errs = append(errs, fmt.Errorf("server.request_timeout (SERVER_REQUEST_TIMEOUT) must be shorter than server.write_timeout (%s) so timed out requests can still be answered", c.Server.WriteTimeout))
+	}
+	if c.Server.ShutdownTimeout <= 0 {
+		errs = append(errs, errors.New("server.shutdown_timeout (SERVER_SHUTDOWN_TIMEOUT) must be a positive duration"))
+	}
+	if len(c.Server.AllowedOrigins) == 0 {
+		errs = append(errs, errors.New("server.allowed_origins (CORS_ALLOWED_ORIGINS) must list at least one origin"))
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		if _, _, err := net.ParseCIDR(proxy); err != nil && net.ParseIP(proxy) == nil {
+			errs = append(errs, fmt.Errorf("server.trusted_proxies contains invalid IP or CIDR %q", proxy))
+		}
+	}
+	for _, origin := range c.Server.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		parsed, err := url.Parse(origin)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			errs = append(errs, fmt.Errorf("server.allowed_origins contains invalid origin %q", origin))
+		}
+	}
+
+	if c.JWT.Secret == "" {
+		errs = append(errs, errors.New("jwt.secret (JWT_SECRET) is required"))
+	}
+	if c.JWT.TokenTTL <= 0 {
+		errs = append(errs, errors.New("jwt.token_ttl (JWT_TOKEN_TTL) must be a positive duration"))
+	}
+
+	switch strings.ToLower(c.Log.Level) {
+	case "debug", "info", "warn", "error":
+	default:
+		errs = append(errs, fmt.Errorf("log.level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Log.Level))
+	}
+	switch strings.ToLower(c.Log.Format) {
+	case "json", "text":
+	default:
+		errs = append(errs, fmt.Errorf("log.format (LOG_FORMAT) must be json or text, got %q", c.Log.Format))
+	}
+
+	if err := c.Notifications.Validate(); err != nil {
+		errs = append(errs, err)
+	}
+	if c.Webhooks.PollInterval <= 0 || c.Webhooks.Timeout <= 0 || c.Webhooks.RetryBackoff <= 0 {
+		errs = append(errs, errors.New("webhook intervals (WEBHOOK_POLL_INTERVAL, WEBHOOK_TIMEOUT, WEBHOOK_RETRY_BACKOFF) must be positive durations"))
+	}
+	if c.Webhooks.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("webhooks.max_attempts (WEBHOOK_MAX_ATTEMPTS) must be at least 1, got %d", c.Webhooks.MaxAttempts))
+	}
+	if c.Events.PollInterval <= 0 || c.Events.RetryBackoff <= 0 {
+		errs = append(errs, errors.New("event intervals (EVENT_POLL_INTERVAL, EVENT_RETRY_BACKOFF) must be positive durations"))
+	}
+	if c.Events.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("events.max_attempts (EVENT_MAX_ATTEMPTS) must be at least 1, got %d", c.Events.MaxAttempts))
+	}
+	if c.Events.Retention < 0 {
+		errs = append(errs, fmt.Errorf("events.retention (EVENT_RETENTION) must not be negative, got %s", c.Events.Retention))
+	}
+	if c.Streams.PollInterval <= 0 || c.Streams.Heartbeat <= 0 {
+		errs = append(errs, errors.New("stream intervals (STREAM_POLL_INTERVAL, STREAM_HEARTBEAT) must be positive durations"))
+	}
+	if c.Streams.MaxClients < 1 {
+		errs = append(errs, fmt.Errorf("streams.max_clients (STREAM_MAX_CLIENTS) must be at least 1, got %d", c.Streams.MaxClients))
+	}
+	if c.Streams.GapTimeout < 0 {
+		errs = append(errs, fmt.Errorf("streams.gap_timeout (STREAM_GAP_TIMEOUT) must not be negative, got %s", c.Streams.GapTimeout))
+	}
+	if c.Trash.Retention < 0 {
+		errs = append(errs, fmt.Errorf("trash.retention (TRASH_RETENTION) must not be negative, got %s", c.Trash.Retention))
+	}
+	if c.Trash.PurgeInterval <= 0 {
+		errs = append(errs, errors.New("trash.purge_interval (TRASH_PURGE_INTERVAL) must be a positive duration"))
+	}
+	if c.Tracking.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("tracking.rate_limit (TRACKING_RATE_LIMIT) must not be negative, got %d", c.Tracking.RateLimit))
+	}
+	if c.Tracking.RateWindow <= 0 {
+		errs = append(errs, errors.New("tracking.rate_window (TRACKING_RATE_WINDOW) must be a positive duration"))
+	}
+	switch c.Customers.OTPChannel {
+	case "log", "whatsapp", "sms":
+		if err := c.Notifications.validateChannel(c.Customers.OTPChannel); err != nil {
+			errs = append(errs, fmt.Errorf("customers.otp_channel (CUSTOMER_OTP_CHANNEL) is %s: %w", c.Customers.OTPChannel, err))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("customers.otp_channel (CUSTOMER_OTP_CHANNEL) must be log, whatsapp or sms, got %q", c.Customers.OTPChannel))
+	}
+	if c.Customers.OTPTTL <= 0 || c.Customers.OTPResend <= 0 || c.Customers.TokenTTL <= 0 || c.Customers.RateWindow <= 0 {
+		errs = append(errs, errors.New("customer durations (CUSTOMER_OTP_TTL, CUSTOMER_OTP_RESEND, CUSTOMER_TOKEN_TTL, CUSTOMER_RATE_WINDOW) must be positive"))
+	}
+	if c.Customers.OTPMaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("customers.otp_max_attempts (CUSTOMER_OTP_MAX_ATTEMPTS) must be at least 1, got %d", c.Customers.OTPMaxAttempts))
+	}
+	if c.Customers.RateLimit < 0 {
+		errs = append(errs, fmt.Errorf("customers.rate_limit (CUSTOMER_RATE_LIMIT) must not be negative, got %d", c.Customers.RateLimit))
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid configuration:\n%w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// Validate checks the settings needed to open a database connection
+func (d DatabaseConfig) Validate() error {
+	// Password can be empty, but other settings must be set
+	var missing []string
+	if d.User == "" {
+		missing = append(missing, "database.user (DB_USER)")
+	}
+	if d.Host == "" {
+		missing = append(missing, "database.host (DB_HOST)")
+	}
+	if d.Port == "" {
+		missing = append(missing, "database.port (DB_PORT)")
+	}
+	if d.Name == "" {
+		missing = append(missing, "database.name (DB_NAME)")
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("database settings not fully set, missing %s", strings.Join(missing, ", "))
+	}
+	if d.MaxOpenConns < 0 || d.MaxIdleConns < 0 {
+		return errors.New("database pool sizes (DB_MAX_OPEN_CONNS, DB_MAX_IDLE_CONNS) must not be negative")
+	}
+	if d.MaxOpenConns > 0 && d.MaxIdleConns > d.MaxOpenConns {
+		return fmt.Errorf("database.max_idle_conns (%d) must not exceed database.max_open_conns (%d)", d.MaxIdleConns, d.MaxOpenConns)
+	}
+	if d.ConnMaxLifetime < 0 || d.ConnMaxIdleTime < 0 {
+		return errors.New("database connection lifetimes must not be negative")
+	}
+	return nil
+}
+
+// Validate checks the notification settings and those of every enabled channel
+func (n NotificationConfig) Validate() error {
+	var errs []error
+	for _, channel := range n.Channels {
+		if err := n.validateChannel(channel); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	for _, status := range n.Statuses {
+		switch status {
+		case "Queued", "Ready to pick up", "Completed":
+		default:
+			errs = append(errs, fmt.Errorf("notifications.statuses (NOTIFY_STATUSES) must contain Queued, Ready to pick up or Completed, got %q", status))
+		}
+	}
+	if n.Language != "id" && n.Language != "en" {
+		errs = append(errs, fmt.Errorf("notifications.language (NOTIFY_LANGUAGE) must be id or en, got %q", n.Language))
+	}
+	if n.PollInterval <= 0 || n.SendTimeout <= 0 || n.RetryBackoff <= 0 {
+		errs = append(errs, errors.New("notification intervals (NOTIFY_POLL_INTERVAL, NOTIFY_SEND_TIMEOUT, NOTIFY_RETRY_BACKOFF) must be positive durations"))
+	}
+	if n.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("notifications.max_attempts (NOTIFY_MAX_ATTEMPTS) must be at least 1, got %d", n.MaxAttempts))
+	}
+	return errors.Join(errs...)
+}
+
+// validateChannel checks the provider settings needed by one channel
+func (n NotificationConfig) validateChannel(channel string) error {
+	var errs []error
+	switch channel {
+	case "log":
+	case "whatsapp":
+		if n.WhatsApp.PhoneNumberID == "" || n.WhatsApp.AccessToken == "" {
+			errs = append(errs, errors.New("notifications.whatsapp.phone_number_id (WHATSAPP_PHONE_NUMBER_ID) and access_token (WHATSAPP_ACCESS_TOKEN) are required by the whatsapp channel"))
+		}
+	case "sms":
+		if parsed, err := url.Parse(n.SMS.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+			errs = append(errs, errors.New("notifications.sms.url (SMS_GATEWAY_URL) must be an http or https URL for the sms channel"))
+		}
+	case "email":
+		if n.SMTP.Host == "" || n.SMTP.From == "" {
+			errs = append(errs, errors.New("notifications.smtp.host (SMTP_HOST) and from (SMTP_FROM) are required by the email channel"))
+		}
+		if n.SMTP.Port < 1 || n.SMTP.Port > 65535 {
+			errs = append(errs, fmt.Errorf("notifications.smtp.port (SMTP_PORT) must be between 1 and 65535, got %d", n.SMTP.Port))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("notifications.channels (NOTIFY_CHANNELS) must contain log, whatsapp, sms or email, got %q", channel))
+	}
+	return errors.Join(errs...)
+}
+
+// Addr returns the listen address for the HTTP server
+func (s ServerConfig) Addr() string {
+	return fmt.Sprintf(":%d", s.Port)
+}
+
+// applyEnv overrides configuration values with environment variables that are set
+func (c *Config) applyEnv() error {
+	setString(&c.Database.Host, "DB_HOST")
+	setString(&c.Database.Port, "DB_PORT")
+	setString(&c.Database.User, "DB_USER")
+	setString(&c.Database.Password, "DB_PASSWORD")
+	setString(&c.Database.Name, "DB_NAME")
+
+	setString(&c.Server.Mode, "GIN_MODE")
+	if value := os.Getenv("CORS_ALLOWED_ORIGINS"); value != "" {
+		c.Server.AllowedOrigins = splitList(value)
+	}
+	if value := os.Getenv("TRUSTED_PROXIES"); value != "" {
+		c.Server.TrustedProxies = splitList(value)
+	}
+	setString(&c.JWT.Secret, "JWT_SECRET")
+	setString(&c.Log.Level, "LOG_LEVEL")
+	setString(&c.Log.Format, "LOG_FORMAT")
+	setString(&c.Metrics.Token, "METRICS_TOKEN")
+
+	if value, ok := os.LookupEnv("NOTIFY_CHANNELS"); ok {
+		c.Notifications.Channels = splitList(value)
+	}
+	if value := os.Getenv("NOTIFY_STATUSES"); value != "" {
+		c.Notifications.Statuses = splitList(value)
+	}
+	setString(&c.Notifications.Language, "NOTIFY_LANGUAGE")
+	setString(&c.Notifications.LaundryName, "NOTIFY_LAUNDRY_NAME")
+	setString(&c.Notifications.LogFile, "NOTIFY_LOG_FILE")
+	setString(&c.Notifications.WhatsApp.APIURL, "WHATSAPP_API_URL")
+	setString(&c.Notifications.WhatsApp.PhoneNumberID, "WHATSAPP_PHONE_NUMBER_ID")
+	setString(&c.Notifications.WhatsApp.AccessToken, "WHATSAPP_ACCESS_TOKEN")
+	setString(&c.Notifications.SMS.URL, "SMS_GATEWAY_URL")
+	setString(&c.Notifications.SMS.APIKey, "SMS_API_KEY")
+	setString(&c.Notifications.SMS.Sender, "SMS_SENDER")
+	setString(&c.Notifications.SMTP.Host, "SMTP_HOST")
+	setString(&c.Notifications.SMTP.Username, "SMTP_USERNAME")
+	setString(&c.Notifications.SMTP.Password, "SMTP_PASSWORD")
+	setString(&c.Notifications.SMTP.From, "SMTP_FROM")
+	setString(&c.Customers.OTPChannel, "CUSTOMER_OTP_CHANNEL")
+
+	return errors.Join(
+		setInt(&c.Server.Port, "PORT"),
+		setDuration(&c.Server.ReadHeaderTimeout, "SERVER_READ_HEADER_TIMEOUT"),
+		setDuration(&c.Server.ReadTimeout, "SERVER_READ_TIMEOUT"),
+		setDuration(&c.Server.WriteTimeout, "SERVER_WRITE_TIMEOUT"),
+		setDuration(&c.Server.IdleTimeout, "SERVER_IDLE_TIMEOUT"),
+		setDuration(&c.Server.ShutdownTimeout, "SERVER_SHUTDOWN_TIMEOUT"),
+		setDuration(&c.Server.RequestTimeout, "SERVER_REQUEST_TIMEOUT"),
+		setDuration(&c.Server.ExportTimeout, "SERVER_EXPORT_TIMEOUT"),
+		setInt(&c.Database.MaxOpenConns, "DB_MAX_OPEN_CONNS"),
+		setInt(&c.Database.MaxIdleConns, "DB_MAX_IDLE_CONNS"),
+		setDuration(&c.Database.ConnMaxLifetime, "DB_CONN_MAX_LIFETIME"),
+		setDuration(&c.Database.ConnMaxIdleTime, "DB_CONN_MAX_IDLE_TIME"),
+		setDuration(&c.Database.SlowQuery, "DB_SLOW_QUERY"),
+		setDuration(&c.JWT.TokenTTL, "JWT_TOKEN_TTL"),
+		setDuration(&c.Notifications.PollInterval, "NOTIFY_POLL_INTERVAL"),
+		setDuration(&c.Notifications.SendTimeout, "NOTIFY_SEND_TIMEOUT"),
+		setInt(&c.Notifications.MaxAttempts, "NOTIFY_MAX_ATTEMPTS"),
+		setDuration(&c.Notifications.RetryBackoff, "NOTIFY_RETRY_BACKOFF"),
+		setInt(&c.Notifications.SMTP.Port, "SMTP_PORT"),
+		setDuration(&c.Webhooks.PollInterval, "WEBHOOK_POLL_INTERVAL"),
+		setDuration(&c.Webhooks.Timeout, "WEBHOOK_TIMEOUT"),
+		setInt(&c.Webhooks.MaxAttempts, "WEBHOOK_MAX_ATTEMPTS"),
+		setDuration(&c.Webhooks.RetryBackoff, "WEBHOOK_RETRY_BACKOFF"),
+		setDuration(&c.Events.PollInterval, "EVENT_POLL_INTERVAL"),
+		setInt(&c.Events.MaxAttempts, "EVENT_MAX_ATTEMPTS"),
+		setDuration(&c.Events.RetryBackoff, "EVENT_RETRY_BACKOFF"),
+		setDuration(&c.Events.Retention, "EVENT_RETENTION"),
+		setDuration(&c.Streams.PollInterval, "STREAM_POLL_INTERVAL"),
+		setDuration(&c.Streams.Heartbeat, "STREAM_HEARTBEAT"),
+		setInt(&c.Streams.MaxClients, "STREAM_MAX_CLIENTS"),
+		setDuration(&c.Streams.GapTimeout, "STREAM_GAP_TIMEOUT"),
+		setDuration(&c.Trash.Retention, "TRASH_RETENTION"),
+		setDuration(&c.Trash.PurgeInterval, "TRASH_PURGE_INTERVAL"),
+		setInt(&c.Tracking.RateLimit, "TRACKING_RATE_LIMIT"),
+		setDuration(&c.Tracking.RateWindow, "TRACKING_RATE_WINDOW"),
+		setDuration(&c.Customers.OTPTTL, "CUSTOMER_OTP_TTL"),
+		setInt(&c.Customers.OTPMaxAttempts, "CUSTOMER_OTP_MAX_ATTEMPTS"),
+		setDuration(&c.Customers.OTPResend, "CUSTOMER_OTP_RESEND"),
+		setDuration(&c.Customers.TokenTTL, "CUSTOMER_TOKEN_TTL"),
+		setInt(&c.Customers.RateLimit, "CUSTOMER_RATE_LIMIT"),
+		setDuration(&c.Customers.RateWindow, "CUSTOMER_RATE_WINDOW"),
+	)
+}
+
+// setString assigns an environment variable to target when it is set
+func setString(target *string, key string) {
+	if value, ok := os.LookupEnv(key); ok {
+		*target = value
+	}
+}
+
+// setInt assigns an integer environment variable to target when it is set
+func setInt(target *int, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := strconv.Atoi(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a number, got %q", key, value)
+	}
+	*target = parsed
+	return nil
+}
+
+// setDuration assigns a duration environment variable to target when it is set
+func setDuration(target *time.Duration, key string) error {
+	value := os.Getenv(key)
+	if value == "" {
+		return nil
+	}
+	parsed, err := time.ParseDuration(value)
+	if err != nil {
+		return fmt.Errorf("%s must be a duration such as 30s or 6h, got %q", key, value)
+	}
+	*target = parsed
+	return nil
+}
+
+// splitList splits a comma-separated list, dropping empty entries
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
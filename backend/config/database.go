@@ -2,9 +2,8 @@ package config
 
 import (
 	"fmt"
-	"os"
+	"log/slog"
 
-	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"gorm.io/driver/mysql"
 	"gorm.io/gorm"
 )
@@ -12,43 +11,36 @@ import (
 var DB *gorm.DB
 
 // InitDB initializes the database connection
-func InitDB() error {
-	dbUser := getEnv("DB_USER")
-	dbPassword := getEnv("DB_PASSWORD")
-	dbHost := getEnv("DB_HOST")
-	dbPort := getEnv("DB_PORT")
-	dbName := getEnv("DB_NAME")
-
-	// Password can be empty, but other variables must be set
-	if dbUser == "" || dbHost == "" || dbPort == "" || dbName == "" {
-		return fmt.Errorf("database environment variables not fully set")
+func InitDB(cfg DatabaseConfig) error {
+	if err := cfg.Validate(); err != nil {
+		return err
 	}
 
 	dsn := fmt.Sprintf(
 		"%s:%s@tcp(%s:%s)/%s?charset=utf8mb4&parseTime=True&loc=Local&allowNativePasswords=true",
-		dbUser, dbPassword, dbHost, dbPort, dbName,
+		cfg.User, cfg.Password, cfg.Host, cfg.Port, cfg.Name,
 	)
 
-	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{})
+	db, err := gorm.Open(mysql.Open(dsn), &gorm.Config{
+		Logger: NewGormLogger(slog.Default(), cfg.SlowQuery),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to connect to database: %w", err)
 	}
 
+	sqlDB, err := db.DB()
+	if err != nil {
+		return fmt.Errorf("failed to access connection pool: %w", err)
+	}
+	sqlDB.SetMaxOpenConns(cfg.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
 	DB = db
 
-	// Auto migrate models
-	return AutoMigrate()
-}
-
-// AutoMigrate runs all database migrations
-func AutoMigrate() error {
-	return DB.AutoMigrate(
-		&models.Admin{},
-		&models.Transaction{},
-		&models.TransactionItem{},
-		&models.TransactionHistory{},
-		&models.ServicePrice{},
-	)
+	// Schema changes are applied explicitly with the migrate command
+	return nil
 }
 
 // GetDB returns the database instance
@@ -56,7 +48,14 @@ func GetDB() *gorm.DB {
 	return DB
 }
 
-// getEnv gets an environment variable
-func getEnv(key string) string {
-	return os.Getenv(key)
+// CloseDB closes the database connection pool
+func CloseDB() error {
+	if DB == nil {
+		return nil
+	}
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
 }
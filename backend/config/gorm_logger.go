@@ -0,0 +1,86 @@
+package config
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// GormLogger forwards GORM logs to slog so queries carry the request ID of their context
+type GormLogger struct {
+	logger    *slog.Logger
+	slowQuery time.Duration
+	level     logger.LogLevel
+}
+
+// NewGormLogger creates a GORM logger that reports errors, slow queries and, at debug level, every query
+func NewGormLogger(l *slog.Logger, slowQuery time.Duration) *GormLogger {
+	return &GormLogger{logger: l, slowQuery: slowQuery, level: logger.Info}
+}
+
+// LogMode returns a copy of the logger with the given GORM log level
+func (l *GormLogger) LogMode(level logger.LogLevel) logger.Interface {
+	copied := *l
+	copied.level = level
+	return &copied
+}
+
+// Info logs an informational GORM message
+func (l *GormLogger) Info(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Info {
+		l.logger.InfoContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Warn logs a GORM warning
+func (l *GormLogger) Warn(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Warn {
+		l.logger.WarnContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Error logs a GORM error
+func (l *GormLogger) Error(ctx context.Context, msg string, args ...interface{}) {
+	if l.level >= logger.Error {
+		l.logger.ErrorContext(ctx, fmt.Sprintf(msg, args...))
+	}
+}
+
+// Trace logs a finished query: failures as errors, slow queries as warnings, everything else at debug
+func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
+	if l.level <= logger.Silent {
+		return
+	}
+
+	elapsed := time.Since(begin)
+	switch {
+	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound) && l.level >= logger.Error:
+		sql, rows := fc()
+		l.logger.ErrorContext(ctx, "query failed",
+			slog.String("error", err.Error()),
+			slog.Duration("elapsed", elapsed),
+			slog.Int64("rows", rows),
+			slog.String("sql", sql),
+		)
+	case l.slowQuery > 0 && elapsed > l.slowQuery && l.level >= logger.Warn:
+		sql, rows := fc()
+		l.logger.WarnContext(ctx, "slow query",
+			slog.Duration("elapsed", elapsed),
+			slog.Duration("threshold", l.slowQuery),
+			slog.Int64("rows", rows),
+			slog.String("sql", sql),
+		)
+	case l.logger.Enabled(ctx, slog.LevelDebug):
+		sql, rows := fc()
+		l.logger.DebugContext(ctx, "query",
+			slog.Duration("elapsed", elapsed),
+			slog.Int64("rows", rows),
+			slog.String("sql", sql),
+		)
+	}
+}
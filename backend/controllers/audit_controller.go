@@ -0,0 +1,129 @@
+package controllers
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// auditFieldName limits the field filter to JSON field names
+var auditFieldName = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// AuditController handles the audit log endpoints
+type AuditController struct {
+	auditService *services.AuditService
+}
+
+// NewAuditController creates a new audit controller
+func NewAuditController(auditService *services.AuditService) *AuditController {
+	return &AuditController{auditService: auditService}
+}
+
+// AuditLogListResponse is one page of audit log entries
+type AuditLogListResponse struct {
+	Data       []models.AuditLog `json:"data"`
+	Total      int64             `json:"total"`
+	Page       int               `json:"page"`
+	Limit      int               `json:"limit"`
+	TotalPages int64             `json:"total_pages"`
+}
+
+// ListAuditLogs searches the audit log, latest first
+func (c *AuditController) ListAuditLogs(ctx *gin.Context) {
+	page := 1
+	limit := 20
+
+	if p := ctx.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	if l := ctx.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	filter := services.AuditFilter{
+		EntityType: ctx.Query("entity_type"),
+		Action:     models.AuditAction(ctx.Query("action")),
+		RequestID:  ctx.Query("request_id"),
+		Query:      strings.TrimSpace(ctx.Query("q")),
+		Limit:      limit,
+		Offset:     (page - 1) * limit,
+	}
+	var fields []utils.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, utils.FieldError{Field: field, Message: message})
+	}
+
+	for _, param := range []struct {
+		name   string
+		target *uint
+	}{{"entity_id", &filter.EntityID}, {"actor_id", &filter.ActorID}} {
+		value := ctx.Query(param.name)
+		if value == "" {
+			continue
+		}
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			invalid(param.name, "must be a positive integer")
+			continue
+		}
+		*param.target = uint(id)
+	}
+
+	if field := ctx.Query("field"); field != "" {
+		if !auditFieldName.MatchString(field) {
+			invalid("field", "must be a field name such as total_price")
+		}
+		filter.Field = field
+	}
+
+	if value := ctx.Query("from"); value != "" {
+		from, _, err := parseDateParam(value, time.Local)
+		if err != nil {
+			invalid("from", "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		} else {
+			filter.From = &from
+		}
+	}
+	if value := ctx.Query("to"); value != "" {
+		to, wholeDay, err := parseDateParam(value, time.Local)
+		if err != nil {
+			invalid("to", "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		} else {
+			// A date includes the whole day, a timestamp is an exclusive bound
+			if wholeDay {
+				to = to.AddDate(0, 0, 1)
+			}
+			filter.To = &to
+		}
+	}
+	if len(fields) > 0 {
+		ctx.Error(&utils.ValidationError{Fields: fields})
+		return
+	}
+
+	entries, total, err := c.auditService.ListAuditLogs(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Audit log retrieved successfully", AuditLogListResponse{
+		Data:       entries,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	})
+}
@@ -3,7 +3,9 @@ package controllers
 import (
 	"net/http"
 
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
@@ -21,24 +23,25 @@ type LoginRequest struct {
 }
 
 type LoginResponse struct {
-	ID       uint   `json:"id"`
-	Username string `json:"username"`
-	Email    string `json:"email"`
-	FullName string `json:"full_name"`
-	Token    string `json:"token"`
+	ID       uint             `json:"id"`
+	Username string           `json:"username"`
+	Email    string           `json:"email"`
+	FullName string           `json:"full_name"`
+	Role     models.AdminRole `json:"role"`
+	Token    string           `json:"token"`
 }
 
 func (c *AuthController) Login(ctx *gin.Context) {
 	var req LoginRequest
 
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		ctx.JSON(http.StatusBadRequest, gin.H{"error": "Invalid request: " + err.Error()})
+		ctx.Error(utils.BindingError(err))
 		return
 	}
 
-	admin, token, err := c.authService.Login(req.Username, req.Password)
+	admin, token, err := c.authService.Login(ctx.Request.Context(), req.Username, req.Password)
 	if err != nil {
-		ctx.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+		ctx.Error(err)
 		return
 	}
 
@@ -47,11 +50,9 @@ func (c *AuthController) Login(ctx *gin.Context) {
 		Username: admin.Username,
 		Email:    admin.Email,
 		FullName: admin.FullName,
+		Role:     admin.Role,
 		Token:    token,
 	}
 
-	ctx.JSON(http.StatusOK, gin.H{
-		"message": "Login berhasil",
-		"data":    response,
-	})
+	utils.SuccessResponse(ctx, http.StatusOK, "Login berhasil", response)
 }
@@ -0,0 +1,202 @@
+package controllers
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+	"gorm.io/datatypes"
+)
+
+// CustomerController handles the customer portal
+type CustomerController struct {
+	customerService *services.CustomerService
+	resendAfter     time.Duration
+}
+
+// NewCustomerController creates a new customer controller. resendAfter is how long customers
+// are told to wait before asking for another login code.
+func NewCustomerController(customerService *services.CustomerService, resendAfter time.Duration) *CustomerController {
+	return &CustomerController{customerService: customerService, resendAfter: resendAfter}
+}
+
+// LoginCodeRequest asks for a login code to be sent to a phone number
+type LoginCodeRequest struct {
+	Phone string `json:"phone" binding:"required,max=20"`
+}
+
+// LoginCodeResponse tells when another login code may be requested
+type LoginCodeResponse struct {
+	ResendAfter int `json:"resend_after"` // seconds
+}
+
+// VerifyLoginCodeRequest logs in with a login code
+type VerifyLoginCodeRequest struct {
+	Phone string `json:"phone" binding:"required,max=20"`
+	Code  string `json:"code" binding:"required,len=6,numeric"`
+}
+
+// CustomerLoginResponse carries the customer token
+type CustomerLoginResponse struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// UpdateAddressRequest is the address a customer saves
+type UpdateAddressRequest struct {
+	Address string `json:"address" binding:"required"`
+}
+
+// CustomerOrderItem is an item of a customer order
+type CustomerOrderItem struct {
+	ServiceType string  `json:"service_type"`
+	ItemName    string  `json:"item_name"`
+	Quantity    int     `json:"quantity"`
+	UnitPrice   float64 `json:"unit_price"`
+	Subtotal    float64 `json:"subtotal"`
+}
+
+// CustomerOrder is an order as its customer sees it, without staff details
+type CustomerOrder struct {
+	TransactionCode string                   `json:"transaction_code"`
+	CustomerName    string                   `json:"customer_name"`
+	CustomerAddress string                   `json:"customer_address"`
+	Status          models.TransactionStatus `json:"status"`
+	TotalPrice      float64                  `json:"total_price"`
+	IsPaid          bool                     `json:"is_paid"`
+	PickupDate      datatypes.Date           `json:"pickup_date"`
+	CompletedAt     *time.Time               `json:"completed_at"`
+	Items           []CustomerOrderItem      `json:"items"`
+	CreatedAt       time.Time                `json:"created_at"`
+	UpdatedAt       time.Time                `json:"updated_at"`
+}
+
+// RequestLoginCode sends a login code to a phone number with orders. The response is the same
+// whether or not the number has orders.
+func (c *CustomerController) RequestLoginCode(ctx *gin.Context) {
+	var req LoginCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	if err := c.customerService.RequestLoginCode(ctx.Request.Context(), req.Phone); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "If the number has orders, a login code has been sent to it", LoginCodeResponse{
+		ResendAfter: int(c.resendAfter.Seconds()),
+	})
+}
+
+// VerifyLoginCode exchanges a login code for a customer token
+func (c *CustomerController) VerifyLoginCode(ctx *gin.Context) {
+	var req VerifyLoginCodeRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	token, expiresAt, err := c.customerService.VerifyLoginCode(ctx.Request.Context(), req.Phone, req.Code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Login successful", CustomerLoginResponse{Token: token, ExpiresAt: expiresAt})
+}
+
+// GetProfile returns the profile of the logged in customer
+func (c *CustomerController) GetProfile(ctx *gin.Context) {
+	profile, err := c.customerService.GetProfile(ctx.Request.Context(), ctx.GetString("customer_phone"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Profile retrieved successfully", profile)
+}
+
+// UpdateAddress saves the address of the logged in customer
+func (c *CustomerController) UpdateAddress(ctx *gin.Context) {
+	var req UpdateAddressRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	profile, err := c.customerService.UpdateAddress(ctx.Request.Context(), ctx.GetString("customer_phone"), req.Address)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Address updated successfully", profile)
+}
+
+// ListOrders lists the orders of the logged in customer, newest first
+func (c *CustomerController) ListOrders(ctx *gin.Context) {
+	orders, err := c.customerService.ListOrders(ctx.Request.Context(), ctx.GetString("customer_phone"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	response := make([]CustomerOrder, len(orders))
+	for i, order := range orders {
+		response[i] = customerOrder(order)
+	}
+	utils.SuccessResponse(ctx, http.StatusOK, "Orders retrieved successfully", response)
+}
+
+// DownloadReceipt downloads the plain text receipt of an order of the logged in customer
+func (c *CustomerController) DownloadReceipt(ctx *gin.Context) {
+	code := ctx.Param("code")
+	if !utils.IsValidTransactionCode(code) {
+		utils.BadRequest(ctx, "Invalid transaction code format")
+		return
+	}
+
+	// The receipt is written at once, so a missing order still gets a regular error response
+	out := &attachmentWriter{
+		ctx:         ctx,
+		contentType: "text/plain; charset=utf-8",
+		fileName:    fmt.Sprintf("receipt-%s.txt", code),
+	}
+	if err := c.customerService.WriteReceipt(ctx.Request.Context(), out, ctx.GetString("customer_phone"), code); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// customerOrder converts a transaction into the order its customer sees
+func customerOrder(transaction models.Transaction) CustomerOrder {
+	items := make([]CustomerOrderItem, len(transaction.Items))
+	for i, item := range transaction.Items {
+		items[i] = CustomerOrderItem{
+			ServiceType: item.ServiceType,
+			ItemName:    item.ItemName,
+			Quantity:    item.Quantity,
+			UnitPrice:   item.UnitPrice,
+			Subtotal:    item.Subtotal,
+		}
+	}
+
+	return CustomerOrder{
+		TransactionCode: transaction.TransactionCode,
+		CustomerName:    transaction.CustomerName,
+		CustomerAddress: transaction.CustomerAddress,
+		Status:          transaction.Status,
+		TotalPrice:      transaction.TotalPrice,
+		IsPaid:          transaction.IsPaid,
+		PickupDate:      transaction.PickupDate,
+		CompletedAt:     transaction.CompletedAt,
+		Items:           items,
+		CreatedAt:       transaction.CreatedAt,
+		UpdatedAt:       transaction.UpdatedAt,
+	}
+}
@@ -0,0 +1,47 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// swaggerUIPage loads Swagger UI from a CDN and points it at the spec served next to it
+const swaggerUIPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>Chronos Laundry API</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js" crossorigin></script>
+  <script>
+    window.onload = () => {
+      window.ui = SwaggerUIBundle({ url: "docs/openapi.yaml", dom_id: "#swagger-ui" });
+    };
+  </script>
+</body>
+</html>
+`
+
+// DocsController serves the OpenAPI specification and Swagger UI
+type DocsController struct {
+	spec []byte
+}
+
+// NewDocsController creates a new docs controller for the given OpenAPI document
+func NewDocsController(spec []byte) *DocsController {
+	return &DocsController{spec: spec}
+}
+
+// SwaggerUI renders the interactive API documentation
+func (c *DocsController) SwaggerUI(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "text/html; charset=utf-8", []byte(swaggerUIPage))
+}
+
+// OpenAPISpec returns the raw OpenAPI document
+func (c *DocsController) OpenAPISpec(ctx *gin.Context) {
+	ctx.Data(http.StatusOK, "application/yaml; charset=utf-8", c.spec)
+}
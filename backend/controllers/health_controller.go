@@ -0,0 +1,42 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthController handles liveness and readiness probes
+type HealthController struct {
+	healthService *services.HealthService
+}
+
+// NewHealthController creates a new health controller
+func NewHealthController(healthService *services.HealthService) *HealthController {
+	return &HealthController{healthService: healthService}
+}
+
+// Healthz reports that the process is up
+func (c *HealthController) Healthz(ctx *gin.Context) {
+	utils.SuccessResponse(ctx, http.StatusOK, "OK", nil)
+}
+
+// Readyz reports whether the service can take traffic (database reachable, schema up to date)
+func (c *HealthController) Readyz(ctx *gin.Context) {
+	report := c.healthService.CheckReadiness(ctx.Request.Context())
+	if !report.Ready {
+		ctx.JSON(http.StatusServiceUnavailable, utils.Response{
+			Success:   false,
+			Message:   "Service Unavailable",
+			Data:      report,
+			Error:     "service is not ready",
+			Code:      utils.CodeUnavailable,
+			RequestID: ctx.GetString("request_id"),
+		})
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Service is ready", report)
+}
@@ -0,0 +1,38 @@
+package controllers
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsController exposes Prometheus metrics
+type MetricsController struct {
+	registry *metrics.Registry
+	token    string
+}
+
+// NewMetricsController creates a metrics controller; a non-empty token must be sent as a bearer token
+func NewMetricsController(registry *metrics.Registry, token string) *MetricsController {
+	return &MetricsController{registry: registry, token: token}
+}
+
+// Metrics writes all metrics in the Prometheus text format
+func (c *MetricsController) Metrics(ctx *gin.Context) {
+	if c.token != "" {
+		expected := "Bearer " + c.token
+		if subtle.ConstantTimeCompare([]byte(ctx.GetHeader("Authorization")), []byte(expected)) != 1 {
+			utils.Unauthorized(ctx, "Invalid metrics token")
+			return
+		}
+	}
+
+	ctx.Header("Content-Type", metrics.ContentType)
+	ctx.Status(http.StatusOK)
+	if err := c.registry.WriteText(ctx.Writer); err != nil {
+		ctx.Error(err)
+	}
+}
@@ -0,0 +1,99 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationController handles the customer notification outbox endpoints
+type NotificationController struct {
+	notificationService *services.NotificationService
+}
+
+// NewNotificationController creates a new notification controller
+func NewNotificationController(notificationService *services.NotificationService) *NotificationController {
+	return &NotificationController{notificationService: notificationService}
+}
+
+// NotificationListResponse is one page of outbox notifications
+type NotificationListResponse struct {
+	Data       []models.Notification `json:"data"`
+	Total      int64                 `json:"total"`
+	Page       int                   `json:"page"`
+	Limit      int                   `json:"limit"`
+	TotalPages int64                 `json:"total_pages"`
+}
+
+// ListNotifications lists outbox notifications, latest first, optionally by status and transaction
+func (c *NotificationController) ListNotifications(ctx *gin.Context) {
+	page := 1
+	limit := 10
+
+	if p := ctx.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	if l := ctx.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	filter := services.NotificationFilter{Limit: limit, Offset: (page - 1) * limit}
+	var fields []utils.FieldError
+	switch status := models.NotificationStatus(ctx.Query("status")); status {
+	case "", models.NotificationPending, models.NotificationSent, models.NotificationFailed:
+		filter.Status = status
+	default:
+		fields = append(fields, utils.FieldError{Field: "status", Message: "must be pending, sent or failed"})
+	}
+	if value := ctx.Query("transaction_id"); value != "" {
+		id, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || id == 0 {
+			fields = append(fields, utils.FieldError{Field: "transaction_id", Message: "must be a positive integer"})
+		}
+		filter.TransactionID = uint(id)
+	}
+	if len(fields) > 0 {
+		ctx.Error(&utils.ValidationError{Fields: fields})
+		return
+	}
+
+	notifications, total, err := c.notificationService.ListNotifications(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Notifications retrieved successfully", NotificationListResponse{
+		Data:       notifications,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	})
+}
+
+// RetryNotification queues a failed notification for delivery again
+func (c *NotificationController) RetryNotification(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid notification ID")
+		return
+	}
+
+	notification, err := c.notificationService.RetryNotification(ctx.Request.Context(), uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Notification queued for retry", notification)
+}
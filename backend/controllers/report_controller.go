@@ -0,0 +1,198 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/export"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportController handles revenue and operations reports
+type ReportController struct {
+	reportService *services.ReportService
+}
+
+// NewReportController creates a new report controller
+func NewReportController(reportService *services.ReportService) *ReportController {
+	return &ReportController{reportService: reportService}
+}
+
+// GetSummary returns the order count, revenue totals and average order value
+func (c *ReportController) GetSummary(ctx *gin.Context) {
+	filter, _, err := parseReportFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	summary, err := c.reportService.GetSummary(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "summary", "Summary retrieved successfully", summary)
+}
+
+// GetRevenueByPeriod returns the revenue per day, week or month
+func (c *ReportController) GetRevenueByPeriod(ctx *gin.Context) {
+	filter, loc, err := parseReportFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rows, err := c.reportService.GetRevenueByPeriod(ctx.Request.Context(), filter, ctx.DefaultQuery("interval", "day"), loc)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "revenue", "Revenue retrieved successfully", rows)
+}
+
+// GetRevenueByService returns the revenue per service type
+func (c *ReportController) GetRevenueByService(ctx *gin.Context) {
+	c.revenueByService(ctx, false)
+}
+
+// GetRevenueByItem returns the revenue per service type and item
+func (c *ReportController) GetRevenueByItem(ctx *gin.Context) {
+	c.revenueByService(ctx, true)
+}
+
+func revenueByServiceName(byItem bool) string {
+	if byItem {
+		return "revenue-by-item"
+	}
+	return "revenue-by-service"
+}
+
+func (c *ReportController) revenueByService(ctx *gin.Context, byItem bool) {
+	filter, _, err := parseReportFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rows, err := c.reportService.GetRevenueByService(ctx.Request.Context(), filter, byItem)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, revenueByServiceName(byItem), "Revenue retrieved successfully", rows)
+}
+
+// GetRevenueByAdmin returns the revenue of the orders each admin took
+func (c *ReportController) GetRevenueByAdmin(ctx *gin.Context) {
+	filter, _, err := parseReportFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	rows, err := c.reportService.GetRevenueByAdmin(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "revenue-by-admin", "Revenue retrieved successfully", rows)
+}
+
+// GetTurnaround returns turnaround time percentiles
+func (c *ReportController) GetTurnaround(ctx *gin.Context) {
+	filter, _, err := parseReportFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	report, err := c.reportService.GetTurnaround(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "turnaround", "Turnaround retrieved successfully", report)
+}
+
+// GetUnpaidAging returns unpaid orders grouped by age
+func (c *ReportController) GetUnpaidAging(ctx *gin.Context) {
+	filter, _, err := parseReportFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	buckets, err := c.reportService.GetUnpaidAging(ctx.Request.Context(), filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	respondReport(ctx, "unpaid-aging", "Unpaid aging retrieved successfully", buckets)
+}
+
+// respondReport sends a report as JSON, or as a CSV or XLSX download named after name
+// when the format query parameter asks for one
+func respondReport(ctx *gin.Context, name, message string, report any) {
+	formatName := ctx.DefaultQuery("format", "json")
+	if formatName == "json" {
+		utils.SuccessResponse(ctx, http.StatusOK, message, report)
+		return
+	}
+	format, err := export.ParseFormat(formatName)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	writeExport(ctx, format, name, func(w export.Writer) error {
+		return services.WriteReport(w, report)
+	})
+}
+
+// parseReportFilter reads the format, tz, from and to query parameters shared by every report.
+// format is json (the default), csv or xlsx.
+// tz is an IANA zone name defaulting to the server zone; plain from and to dates are read in it,
+// and a plain to date includes that whole day.
+func parseReportFilter(ctx *gin.Context) (services.ReportFilter, *time.Location, error) {
+	var filter services.ReportFilter
+	loc := time.Local
+	if name := ctx.Query("tz"); name != "" {
+		parsed, err := time.LoadLocation(name)
+		if err != nil {
+			return filter, nil, utils.NewValidationError("tz", "must be an IANA time zone such as Asia/Jakarta")
+		}
+		loc = parsed
+	}
+
+	var fields []utils.FieldError
+	if format := ctx.DefaultQuery("format", "json"); format != "json" {
+		if _, err := export.ParseFormat(format); err != nil {
+			fields = append(fields, utils.FieldError{Field: "format", Message: "must be json, csv or xlsx"})
+		}
+	}
+	if value := ctx.Query("from"); value != "" {
+		from, _, err := parseDateParam(value, loc)
+		if err != nil {
+			fields = append(fields, utils.FieldError{Field: "from", Message: "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+		} else {
+			filter.From = &from
+		}
+	}
+	if value := ctx.Query("to"); value != "" {
+		to, wholeDay, err := parseDateParam(value, loc)
+		if err != nil {
+			fields = append(fields, utils.FieldError{Field: "to", Message: "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp"})
+		} else {
+			if wholeDay {
+				to = to.AddDate(0, 0, 1)
+			}
+			filter.To = &to
+		}
+	}
+
+	if len(fields) > 0 {
+		return filter, nil, &utils.ValidationError{Fields: fields}
+	}
+	return filter, loc, nil
+}
@@ -1,8 +1,13 @@
 package controllers
 
 import (
+	"fmt"
+	"io"
 	"net/http"
+	"path/filepath"
 	"strconv"
+	"strings"
+	"time"
 
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
@@ -32,7 +37,7 @@ type CreateServicePriceRequest struct {
 func (c *ServicePriceController) CreateServicePrice(ctx *gin.Context) {
 	var req CreateServicePriceRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(ctx, "Invalid request body: "+err.Error())
+		ctx.Error(utils.BindingError(err))
 		return
 	}
 
@@ -44,9 +49,9 @@ func (c *ServicePriceController) CreateServicePrice(ctx *gin.Context) {
 		IsActive:    true,
 	}
 
-	err := c.servicePriceService.CreateServicePrice(servicePrice)
+	err := c.servicePriceService.CreateServicePrice(ctx.Request.Context(), servicePrice)
 	if err != nil {
-		utils.BadRequest(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -61,9 +66,9 @@ func (c *ServicePriceController) GetServicePrice(ctx *gin.Context) {
 		return
 	}
 
-	servicePrice, err := c.servicePriceService.GetServicePrice(uint(id))
+	servicePrice, err := c.servicePriceService.GetServicePrice(ctx.Request.Context(), uint(id))
 	if err != nil {
-		utils.NotFound(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -72,9 +77,9 @@ func (c *ServicePriceController) GetServicePrice(ctx *gin.Context) {
 
 // GetAllServicePrices retrieves all active service prices
 func (c *ServicePriceController) GetAllServicePrices(ctx *gin.Context) {
-	servicePrices, err := c.servicePriceService.GetAllServicePrices()
+	servicePrices, err := c.servicePriceService.GetAllServicePrices(ctx.Request.Context())
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -89,9 +94,9 @@ func (c *ServicePriceController) GetServicePricesByType(ctx *gin.Context) {
 		return
 	}
 
-	servicePrices, err := c.servicePriceService.GetServicePricesByType(serviceType)
+	servicePrices, err := c.servicePriceService.GetServicePricesByType(ctx.Request.Context(), serviceType)
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -100,9 +105,9 @@ func (c *ServicePriceController) GetServicePricesByType(ctx *gin.Context) {
 
 // GetServiceTypes retrieves all unique service types
 func (c *ServicePriceController) GetServiceTypes(ctx *gin.Context) {
-	serviceTypes, err := c.servicePriceService.GetServiceTypes()
+	serviceTypes, err := c.servicePriceService.GetServiceTypes(ctx.Request.Context())
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -128,14 +133,14 @@ func (c *ServicePriceController) UpdateServicePrice(ctx *gin.Context) {
 
 	var req UpdateServicePriceRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(ctx, "Invalid request body: "+err.Error())
+		ctx.Error(utils.BindingError(err))
 		return
 	}
 
 	// Get existing service price
-	servicePrice, err := c.servicePriceService.GetServicePrice(uint(id))
+	servicePrice, err := c.servicePriceService.GetServicePrice(ctx.Request.Context(), uint(id))
 	if err != nil {
-		utils.NotFound(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -156,16 +161,16 @@ func (c *ServicePriceController) UpdateServicePrice(ctx *gin.Context) {
 		servicePrice.IsActive = *req.IsActive
 	}
 
-	err = c.servicePriceService.UpdateServicePrice(servicePrice)
+	err = c.servicePriceService.UpdateServicePrice(ctx.Request.Context(), servicePrice)
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, "Service price updated successfully", servicePrice)
 }
 
-// DeleteServicePrice deletes a service price
+// DeleteServicePrice moves a service price to the trash
 func (c *ServicePriceController) DeleteServicePrice(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
@@ -173,13 +178,19 @@ func (c *ServicePriceController) DeleteServicePrice(ctx *gin.Context) {
 		return
 	}
 
-	err = c.servicePriceService.DeleteServicePrice(uint(id))
+	var req DeleteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	err = c.servicePriceService.DeleteServicePrice(ctx.Request.Context(), uint(id), req.Reason)
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	utils.SuccessResponse(ctx, http.StatusOK, "Service price deleted successfully", nil)
+	utils.SuccessResponse(ctx, http.StatusOK, "Service price moved to the trash", nil)
 }
 
 // DeactivateServicePrice deactivates a service price
@@ -190,9 +201,9 @@ func (c *ServicePriceController) DeactivateServicePrice(ctx *gin.Context) {
 		return
 	}
 
-	err = c.servicePriceService.DeactivateServicePrice(uint(id))
+	err = c.servicePriceService.DeactivateServicePrice(ctx.Request.Context(), uint(id))
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -207,11 +218,91 @@ func (c *ServicePriceController) ActivateServicePrice(ctx *gin.Context) {
 		return
 	}
 
-	err = c.servicePriceService.ActivateServicePrice(uint(id))
+	err = c.servicePriceService.ActivateServicePrice(ctx.Request.Context(), uint(id))
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, "Service price activated successfully", nil)
 }
+
+// ExportServicePrices downloads the full service price catalog as CSV or JSON
+func (c *ServicePriceController) ExportServicePrices(ctx *gin.Context) {
+	format, err := services.ParseCatalogFormat(ctx.DefaultQuery("format", "csv"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	entries, err := c.servicePriceService.ExportCatalog(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == services.CatalogFormatJSON {
+		contentType = "application/json; charset=utf-8"
+	}
+	fileName := fmt.Sprintf("service-prices-%s.%s", time.Now().Format("20060102"), format)
+
+	ctx.Header("Content-Type", contentType)
+	ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	ctx.Status(http.StatusOK)
+	if err := services.WriteCatalog(ctx.Writer, format, entries); err != nil {
+		ctx.Error(err)
+	}
+}
+
+// ImportServicePrices replaces the service price catalog from an uploaded CSV or JSON file.
+// With dry_run=true the difference is returned without being applied.
+func (c *ServicePriceController) ImportServicePrices(ctx *gin.Context) {
+	dryRun, _ := strconv.ParseBool(ctx.DefaultQuery("dry_run", "false"))
+
+	var body io.Reader = ctx.Request.Body
+	formatName := ctx.Query("format")
+
+	if fileHeader, err := ctx.FormFile("file"); err == nil {
+		file, err := fileHeader.Open()
+		if err != nil {
+			utils.BadRequest(ctx, "Failed to read uploaded file")
+			return
+		}
+		defer file.Close()
+		body = file
+		if formatName == "" {
+			formatName = strings.TrimPrefix(filepath.Ext(fileHeader.Filename), ".")
+		}
+	}
+	if formatName == "" {
+		formatName = "csv"
+		if strings.Contains(ctx.ContentType(), "json") {
+			formatName = "json"
+		}
+	}
+
+	format, err := services.ParseCatalogFormat(formatName)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	entries, err := services.ReadCatalog(body, format)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	diff, err := c.servicePriceService.ImportCatalog(ctx.Request.Context(), entries, dryRun)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	message := "Service price catalog imported successfully"
+	if dryRun {
+		message = "Service price catalog import preview"
+	}
+	utils.SuccessResponse(ctx, http.StatusOK, message, diff)
+}
@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ShiftController handles cashier shift endpoints
+type ShiftController struct {
+	shiftService *services.ShiftService
+}
+
+// NewShiftController creates a new shift controller
+func NewShiftController(shiftService *services.ShiftService) *ShiftController {
+	return &ShiftController{shiftService: shiftService}
+}
+
+// OpenShiftRequest represents a shift being opened
+type OpenShiftRequest struct {
+	OpeningCash float64 `json:"opening_cash" binding:"gte=0"`
+	Notes       string  `json:"notes"`
+}
+
+// CloseShiftRequest represents a shift being closed after counting the drawer
+type CloseShiftRequest struct {
+	CountedCash *float64 `json:"counted_cash" binding:"required,gte=0"`
+	Notes       string   `json:"notes"`
+}
+
+// ShiftListResponse is one page of shifts
+type ShiftListResponse struct {
+	Data       []models.Shift `json:"data"`
+	Total      int64          `json:"total"`
+	Page       int            `json:"page"`
+	Limit      int            `json:"limit"`
+	TotalPages int64          `json:"total_pages"`
+}
+
+// OpenShift starts a shift for the current admin
+func (c *ShiftController) OpenShift(ctx *gin.Context) {
+	var req OpenShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	shift, err := c.shiftService.OpenShift(ctx.Request.Context(), currentAdminID(ctx), req.OpeningCash, req.Notes)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Shift opened successfully", shift)
+}
+
+// GetCurrentShift returns the live report of the current admin's open shift
+func (c *ShiftController) GetCurrentShift(ctx *gin.Context) {
+	report, err := c.shiftService.GetCurrentShift(ctx.Request.Context(), currentAdminID(ctx))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Shift retrieved successfully", report)
+}
+
+// CloseCurrentShift closes the current admin's open shift with the counted cash
+func (c *ShiftController) CloseCurrentShift(ctx *gin.Context) {
+	var req CloseShiftRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	report, err := c.shiftService.CloseCurrentShift(ctx.Request.Context(), currentAdminID(ctx), *req.CountedCash, req.Notes)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Shift closed successfully", report)
+}
+
+// GetShift returns the report of a shift, locked once it is closed
+func (c *ShiftController) GetShift(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid shift ID")
+		return
+	}
+
+	report, err := c.shiftService.GetShiftReport(ctx.Request.Context(), uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Shift retrieved successfully", report)
+}
+
+// ListShifts lists shifts, latest first, optionally for one admin and a range of opening dates
+func (c *ShiftController) ListShifts(ctx *gin.Context) {
+	page := 1
+	limit := 10
+
+	if p := ctx.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	if l := ctx.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	filter, _, err := parseReportFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	shiftFilter := services.ShiftFilter{From: filter.From, To: filter.To, Limit: limit, Offset: (page - 1) * limit}
+	if value := ctx.Query("admin_id"); value != "" {
+		adminID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || adminID == 0 {
+			ctx.Error(utils.NewValidationError("admin_id", "must be a positive integer"))
+			return
+		}
+		shiftFilter.AdminID = uint(adminID)
+	}
+
+	shifts, total, err := c.shiftService.ListShifts(ctx.Request.Context(), shiftFilter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Shifts retrieved successfully", ShiftListResponse{
+		Data:       shifts,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	})
+}
@@ -0,0 +1,166 @@
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// streamRetry is the reconnect delay suggested to EventSource clients, in milliseconds
+const streamRetry = 3000
+
+// StreamController handles the Server-Sent Events streams of the order board and tracking pages
+type StreamController struct {
+	streamService      *services.StreamService
+	transactionService *services.TransactionService
+	heartbeat          time.Duration
+}
+
+// NewStreamController creates a new stream controller sending a keep-alive every heartbeat
+func NewStreamController(streamService *services.StreamService, transactionService *services.TransactionService, heartbeat time.Duration) *StreamController {
+	return &StreamController{streamService: streamService, transactionService: transactionService, heartbeat: heartbeat}
+}
+
+// StreamBoard streams transaction.created and transaction.status_changed for the order board
+func (c *StreamController) StreamBoard(ctx *gin.Context) {
+	c.stream(ctx, "")
+}
+
+// StreamTracking streams the status changes of one transaction for its public tracking page
+func (c *StreamController) StreamTracking(ctx *gin.Context) {
+	code := ctx.Param("code")
+	if _, err := c.transactionService.GetTransactionByCode(ctx.Request.Context(), code); err != nil {
+		ctx.Error(err)
+		return
+	}
+	c.stream(ctx, code)
+}
+
+// stream sends the events a reconnecting client missed, then live events until it disconnects.
+// The last seen ID comes from the Last-Event-ID header, which EventSource sends on reconnects,
+// or from the last_event_id query parameter. The stream ends as soon as a write fails, so a
+// client that went away is let go even if its request context is never cancelled.
+func (c *StreamController) stream(ctx *gin.Context, code string) {
+	var lastID uint
+	if value := ctx.GetHeader("Last-Event-ID"); value != "" || ctx.Query("last_event_id") != "" {
+		if value == "" {
+			value = ctx.Query("last_event_id")
+		}
+		parsed, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			ctx.Error(utils.NewValidationError("last_event_id", "must be a non-negative integer"))
+			return
+		}
+		lastID = uint(parsed)
+	}
+
+	// Subscribe before replaying so nothing falls between the replay and the live events
+	sub, err := c.streamService.Open(code)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	defer c.streamService.Close(sub)
+
+	var replayed []services.StreamEvent
+	truncated := false
+	if lastID > 0 {
+		replayed, truncated, err = c.streamService.Replay(ctx.Request.Context(), code, lastID)
+		if err != nil {
+			ctx.Error(err)
+			return
+		}
+	}
+
+	header := ctx.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	header.Set("X-Accel-Buffering", "no") // keep nginx from buffering the stream
+	ctx.Status(http.StatusOK)
+	if _, err := fmt.Fprintf(ctx.Writer, "retry: %d\n\n", streamRetry); err != nil {
+		return
+	}
+
+	sent := lastID
+	if truncated {
+		if err := writeResync(ctx); err != nil {
+			return
+		}
+	}
+	for _, event := range replayed {
+		if err := writeStreamEvent(ctx, event, code); err != nil {
+			return
+		}
+		sent = event.ID
+	}
+	if err := flushStream(ctx); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(c.heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(ctx.Writer, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		case event, ok := <-sub.Events:
+			if !ok {
+				return // the client reconnects and catches up from its last event ID
+			}
+			if event.ID <= sent {
+				continue // already replayed; live events arrive in ID order
+			}
+			if err := writeStreamEvent(ctx, event, code); err != nil {
+				return
+			}
+			sent = event.ID
+		}
+		if err := flushStream(ctx); err != nil {
+			return
+		}
+	}
+}
+
+// writeStreamEvent writes one event in the Server-Sent Events format, with the tracking data
+// on a tracking stream and the board data otherwise
+func writeStreamEvent(ctx *gin.Context, event services.StreamEvent, code string) error {
+	data := event.Board
+	if code != "" {
+		data = event.Tracking
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		ctx.Error(err)
+		return err
+	}
+	_, err = fmt.Fprintf(ctx.Writer, "id: %d\nevent: %s\ndata: %s\n\n", event.ID, event.Name, payload)
+	return err
+}
+
+// writeResync tells the client it missed events that can no longer be sent, so it must reload
+func writeResync(ctx *gin.Context) error {
+	_, err := fmt.Fprint(ctx.Writer, "event: resync\ndata: {}\n\n")
+	return err
+}
+
+// flushStream sends the buffered events to the client and reports a failed write. Gin's own
+// Flush drops the error, so the flush goes to the connection's writer underneath.
+func flushStream(ctx *gin.Context) error {
+	ctx.Writer.WriteHeaderNow()
+	var w http.ResponseWriter = ctx.Writer
+	if unwrapper, ok := w.(interface{ Unwrap() http.ResponseWriter }); ok {
+		w = unwrapper.Unwrap()
+	}
+	return http.NewResponseController(w).Flush()
+}
@@ -0,0 +1,48 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/gin-gonic/gin"
+)
+
+// brokenConn is a response writer whose client has gone away
+type brokenConn struct {
+	header http.Header
+}
+
+func (w *brokenConn) Header() http.Header       { return w.header }
+func (w *brokenConn) WriteHeader(int)           {}
+func (w *brokenConn) Write([]byte) (int, error) { return 0, errors.New("broken pipe") }
+func (w *brokenConn) Flush()                    {}
+func (w *brokenConn) FlushError() error         { return errors.New("broken pipe") }
+
+// TestStreamEndsOnWriteFailure keeps the request context alive, as after a request timeout,
+// while the client is gone: the stream must end and release its subscription
+func TestStreamEndsOnWriteFailure(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	streamService := services.NewStreamService(nil, services.StreamSettings{MaxClients: 1})
+	controller := NewStreamController(streamService, nil, time.Millisecond)
+
+	ctx, _ := gin.CreateTestContext(&brokenConn{header: http.Header{}})
+	ctx.Request = httptest.NewRequest(http.MethodGet, "/transactions/stream", nil)
+
+	done := make(chan struct{})
+	go func() {
+		controller.StreamBoard(ctx)
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("the stream kept running after its writes failed")
+	}
+	if clients := streamService.Clients(); clients != 0 {
+		t.Errorf("Clients() = %d after the stream ended, want 0", clients)
+	}
+}
@@ -1,11 +1,15 @@
 package controllers
 
 import (
+	"errors"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/RidwanRamdhani/chronos-laundry/backend/export"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
@@ -29,12 +33,14 @@ func NewTransactionController(transactionService *services.TransactionService, s
 
 // CreateTransactionRequest represents a create transaction request
 type CreateTransactionRequest struct {
-	CustomerName    string                         `json:"customer_name" binding:"required"`
-	CustomerPhone   string                         `json:"customer_phone" binding:"required"`
-	CustomerAddress string                         `json:"customer_address"`
-	Notes           string                         `json:"notes"`
-	PickupDate      string                         `json:"pickup_date"`
-	Items           []CreateTransactionItemRequest `json:"items" binding:"required,min=1"`
+	CustomerName     string                         `json:"customer_name" binding:"required"`
+	CustomerPhone    string                         `json:"customer_phone" binding:"required"`
+	CustomerAddress  string                         `json:"customer_address"`
+	CustomerEmail    string                         `json:"customer_email" binding:"omitempty,email"`
+	CustomerLanguage string                         `json:"customer_language" binding:"omitempty,oneof=id en"`
+	Notes            string                         `json:"notes"`
+	PickupDate       string                         `json:"pickup_date"`
+	Items            []CreateTransactionItemRequest `json:"items" binding:"required,min=1"`
 }
 
 // CreateTransactionItemRequest represents a transaction item
@@ -45,11 +51,22 @@ type CreateTransactionItemRequest struct {
 	UnitPrice   float64 `json:"unit_price" binding:"required"`
 }
 
+// TransactionCreatedResponse summarizes a newly created transaction
+type TransactionCreatedResponse struct {
+	ID              uint                     `json:"id"`
+	TransactionCode string                   `json:"transaction_code"`
+	CustomerName    string                   `json:"customer_name"`
+	CustomerPhone   string                   `json:"customer_phone"`
+	Status          models.TransactionStatus `json:"status"`
+	TotalPrice      float64                  `json:"total_price"`
+	IsPaid          bool                     `json:"is_paid"`
+}
+
 // CreateTransaction creates a new transaction
 func (c *TransactionController) CreateTransaction(ctx *gin.Context) {
 	var req CreateTransactionRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(ctx, "Invalid request body: "+err.Error())
+		ctx.Error(utils.BindingError(err))
 		return
 	}
 
@@ -58,7 +75,7 @@ func (c *TransactionController) CreateTransaction(ctx *gin.Context) {
 	if req.PickupDate != "" {
 		parsedDate, err := time.Parse("2006-01-02", req.PickupDate)
 		if err != nil {
-			utils.BadRequest(ctx, "Invalid pickup date format, use YYYY-MM-DD")
+			ctx.Error(utils.NewValidationError("pickup_date", "Invalid pickup date format, use YYYY-MM-DD"))
 			return
 		}
 		pickupDate = datatypes.Date(parsedDate)
@@ -70,14 +87,18 @@ func (c *TransactionController) CreateTransaction(ctx *gin.Context) {
 
 	for i, item := range req.Items {
 		// Validate price against database
-		isValid, correctPrice, err := c.servicePriceService.ValidatePrice(item.ServiceType, item.ItemName, item.UnitPrice)
+		isValid, correctPrice, err := c.servicePriceService.ValidatePrice(ctx.Request.Context(), item.ServiceType, item.ItemName, item.UnitPrice)
+		if errors.Is(err, utils.ErrNotFound) {
+			ctx.Error(utils.NewValidationError(fmt.Sprintf("items[%d]", i), err.Error()))
+			return
+		}
 		if err != nil {
-			utils.BadRequest(ctx, "Price validation failed: "+err.Error())
+			ctx.Error(err)
 			return
 		}
 		if !isValid {
-			utils.BadRequest(ctx, fmt.Sprintf("Invalid price for %s - %s. Expected: Rp %.0f, Got: Rp %.0f",
-				item.ServiceType, item.ItemName, correctPrice, item.UnitPrice))
+			ctx.Error(utils.NewValidationError(fmt.Sprintf("items[%d].unit_price", i), fmt.Sprintf("Invalid price for %s - %s. Expected: Rp %.0f, Got: Rp %.0f",
+				item.ServiceType, item.ItemName, correctPrice, item.UnitPrice)))
 			return
 		}
 
@@ -92,38 +113,34 @@ func (c *TransactionController) CreateTransaction(ctx *gin.Context) {
 		calculatedTotalPrice += subtotal
 	}
 
-	// Get admin ID from context (set by auth middleware)
-	adminID := uint(0)
-	if id, exists := ctx.Get("admin_id"); exists {
-		adminID = id.(uint)
-	}
-
 	// Create transaction with calculated total price
 	transaction := &models.Transaction{
-		CustomerName:    req.CustomerName,
-		CustomerPhone:   req.CustomerPhone,
-		CustomerAddress: req.CustomerAddress,
-		Notes:           req.Notes,
-		TotalPrice:      calculatedTotalPrice,
-		PickupDate:      pickupDate,
-		Items:           items,
-		AdminID:         adminID,
-	}
-
-	err := c.transactionService.CreateTransaction(transaction)
+		CustomerName:     req.CustomerName,
+		CustomerPhone:    req.CustomerPhone,
+		CustomerAddress:  req.CustomerAddress,
+		CustomerEmail:    req.CustomerEmail,
+		CustomerLanguage: req.CustomerLanguage,
+		Notes:            req.Notes,
+		TotalPrice:       calculatedTotalPrice,
+		PickupDate:       pickupDate,
+		Items:            items,
+		AdminID:          currentAdminID(ctx),
+	}
+
+	err := c.transactionService.CreateTransaction(ctx.Request.Context(), transaction)
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	utils.SuccessResponse(ctx, http.StatusCreated, "Transaction created successfully", map[string]interface{}{
-		"id":               transaction.ID,
-		"transaction_code": transaction.TransactionCode,
-		"customer_name":    transaction.CustomerName,
-		"customer_phone":   transaction.CustomerPhone,
-		"status":           transaction.Status,
-		"total_price":      transaction.TotalPrice,
-		"is_paid":          transaction.IsPaid,
+	utils.SuccessResponse(ctx, http.StatusCreated, "Transaction created successfully", TransactionCreatedResponse{
+		ID:              transaction.ID,
+		TransactionCode: transaction.TransactionCode,
+		CustomerName:    transaction.CustomerName,
+		CustomerPhone:   transaction.CustomerPhone,
+		Status:          transaction.Status,
+		TotalPrice:      transaction.TotalPrice,
+		IsPaid:          transaction.IsPaid,
 	})
 }
 
@@ -135,16 +152,42 @@ func (c *TransactionController) GetTransaction(ctx *gin.Context) {
 		return
 	}
 
-	transaction, err := c.transactionService.GetTransaction(uint(id))
+	transaction, err := c.transactionService.GetTransaction(ctx.Request.Context(), uint(id))
 	if err != nil {
-		utils.NotFound(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, "Transaction retrieved successfully", transaction)
 }
 
-// TrackTransaction retrieves transaction status by code
+// TrackingResponse is the public view of a transaction: a masked name and the status for anyone
+// with the code, the remaining fields only once the last 4 digits of the phone were given
+type TrackingResponse struct {
+	TransactionCode string                   `json:"transaction_code"`
+	CustomerName    string                   `json:"customer_name"`
+	Status          models.TransactionStatus `json:"status"`
+	PickupDate      datatypes.Date           `json:"pickup_date"`
+	UpdatedAt       time.Time                `json:"updated_at"`
+	Verified        bool                     `json:"verified"`
+
+	TotalPrice    *float64              `json:"total_price,omitempty"`
+	IsPaid        *bool                 `json:"is_paid,omitempty"`
+	ItemsCount    *int                  `json:"items_count,omitempty"`
+	StatusHistory []TrackingStatusEntry `json:"status_history,omitempty"`
+	CreatedAt     *time.Time            `json:"created_at,omitempty"`
+}
+
+// TrackingStatusEntry is a status change on the tracking page, without the admin who made it
+type TrackingStatusEntry struct {
+	PreviousStatus models.TransactionStatus `json:"previous_status"`
+	NewStatus      models.TransactionStatus `json:"new_status"`
+	Reason         string                   `json:"reason"`
+	CreatedAt      time.Time                `json:"created_at"`
+}
+
+// TrackTransaction retrieves transaction status by code. The details need the last 4 digits of
+// the customer's phone in phone_last4.
 func (c *TransactionController) TrackTransaction(ctx *gin.Context) {
 	code := ctx.Param("code")
 	if code == "" {
@@ -152,24 +195,36 @@ func (c *TransactionController) TrackTransaction(ctx *gin.Context) {
 		return
 	}
 
-	transaction, err := c.transactionService.GetTransactionByCode(code)
+	transaction, verified, err := c.transactionService.TrackTransaction(ctx.Request.Context(), code, ctx.Query("phone_last4"))
 	if err != nil {
-		utils.NotFound(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	// Return simplified tracking info (no sensitive data)
-	trackingInfo := map[string]interface{}{
-		"transaction_code": transaction.TransactionCode,
-		"customer_name":    transaction.CustomerName,
-		"status":           transaction.Status,
-		"total_price":      transaction.TotalPrice,
-		"is_paid":          transaction.IsPaid,
-		"pickup_date":      transaction.PickupDate,
-		"items_count":      len(transaction.Items),
-		"status_history":   transaction.StatusHistory,
-		"created_at":       transaction.CreatedAt,
-		"updated_at":       transaction.UpdatedAt,
+	trackingInfo := TrackingResponse{
+		TransactionCode: transaction.TransactionCode,
+		CustomerName:    utils.MaskName(transaction.CustomerName),
+		Status:          transaction.Status,
+		PickupDate:      transaction.PickupDate,
+		UpdatedAt:       transaction.UpdatedAt,
+		Verified:        verified,
+	}
+	if verified {
+		itemsCount := len(transaction.Items)
+		history := make([]TrackingStatusEntry, len(transaction.StatusHistory))
+		for i, entry := range transaction.StatusHistory {
+			history[i] = TrackingStatusEntry{
+				PreviousStatus: entry.PreviousStatus,
+				NewStatus:      entry.NewStatus,
+				Reason:         entry.Reason,
+				CreatedAt:      entry.CreatedAt,
+			}
+		}
+		trackingInfo.TotalPrice = &transaction.TotalPrice
+		trackingInfo.IsPaid = &transaction.IsPaid
+		trackingInfo.ItemsCount = &itemsCount
+		trackingInfo.StatusHistory = history
+		trackingInfo.CreatedAt = &transaction.CreatedAt
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, "Transaction tracking retrieved successfully", trackingInfo)
@@ -177,12 +232,14 @@ func (c *TransactionController) TrackTransaction(ctx *gin.Context) {
 
 // UpdateTransactionRequest represents an update transaction request
 type UpdateTransactionRequest struct {
-	CustomerName    string  `json:"customer_name"`
-	CustomerPhone   string  `json:"customer_phone"`
-	CustomerAddress string  `json:"customer_address"`
-	Notes           string  `json:"notes"`
-	TotalPrice      float64 `json:"total_price"`
-	IsPaid          *bool   `json:"is_paid"` // pointer to distinguish between false and not provided
+	CustomerName     string   `json:"customer_name"`
+	CustomerPhone    string   `json:"customer_phone"`
+	CustomerAddress  string   `json:"customer_address"`
+	CustomerEmail    string   `json:"customer_email" binding:"omitempty,email"`
+	CustomerLanguage string   `json:"customer_language" binding:"omitempty,oneof=id en"`
+	Notes            string   `json:"notes"`
+	TotalPrice       *float64 `json:"total_price"` // rejected, the total is computed from the items
+	IsPaid           *bool    `json:"is_paid"`     // pointer to distinguish between false and not provided
 }
 
 // UpdateTransaction updates a transaction
@@ -195,14 +252,18 @@ func (c *TransactionController) UpdateTransaction(ctx *gin.Context) {
 
 	var req UpdateTransactionRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(ctx, "Invalid request body: "+err.Error())
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+	if req.TotalPrice != nil {
+		ctx.Error(utils.NewValidationError("total_price", "is computed from the items, add, change or remove items instead"))
 		return
 	}
 
 	// Get existing transaction
-	transaction, err := c.transactionService.GetTransaction(uint(id))
+	transaction, err := c.transactionService.GetTransaction(ctx.Request.Context(), uint(id))
 	if err != nil {
-		utils.NotFound(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
@@ -216,31 +277,158 @@ func (c *TransactionController) UpdateTransaction(ctx *gin.Context) {
 	if req.CustomerAddress != "" {
 		transaction.CustomerAddress = req.CustomerAddress
 	}
+	if req.CustomerEmail != "" {
+		transaction.CustomerEmail = req.CustomerEmail
+	}
+	if req.CustomerLanguage != "" {
+		transaction.CustomerLanguage = req.CustomerLanguage
+	}
 	if req.Notes != "" {
 		transaction.Notes = req.Notes
 	}
-	if req.TotalPrice > 0 {
-		transaction.TotalPrice = req.TotalPrice
-	}
 	if req.IsPaid != nil {
 		transaction.IsPaid = *req.IsPaid
 	}
 
-	err = c.transactionService.UpdateTransaction(transaction)
+	err = c.transactionService.UpdateTransaction(ctx.Request.Context(), transaction, currentAdminID(ctx))
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, "Transaction updated successfully", transaction)
 }
 
+// RecordPaymentRequest represents a payment being recorded
+type RecordPaymentRequest struct {
+	Method models.PaymentMethod `json:"method" binding:"required,oneof=cash transfer qris"`
+	PaidAt *time.Time           `json:"paid_at"` // defaults to now, may be back-dated outside closed shifts
+}
+
+// RecordPayment records the payment of a transaction into the admin's open shift
+func (c *TransactionController) RecordPayment(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid transaction ID")
+		return
+	}
+
+	var req RecordPaymentRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	payment, err := c.transactionService.RecordPayment(ctx.Request.Context(), uint(id), currentAdminID(ctx), req.Method, req.PaidAt)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Payment recorded successfully", payment)
+}
+
+// AddItemRequest represents an item added to an existing transaction, priced from the catalog
+type AddItemRequest struct {
+	ServiceType string `json:"service_type" binding:"required"`
+	ItemName    string `json:"item_name" binding:"required"`
+	Quantity    int    `json:"quantity" binding:"required,gt=0"`
+}
+
+// UpdateItemRequest represents the new quantity of a transaction item
+type UpdateItemRequest struct {
+	Quantity int `json:"quantity" binding:"required,gt=0"`
+}
+
+// AddTransactionItem adds an item to a transaction and returns the repriced transaction
+func (c *TransactionController) AddTransactionItem(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid transaction ID")
+		return
+	}
+
+	var req AddItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	transaction, err := c.transactionService.AddTransactionItem(ctx.Request.Context(), uint(id), req.ServiceType, req.ItemName, req.Quantity)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Item added successfully", transaction)
+}
+
+// UpdateTransactionItem changes the quantity of a transaction item and returns the repriced transaction
+func (c *TransactionController) UpdateTransactionItem(ctx *gin.Context) {
+	id, itemID, ok := transactionItemIDs(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateItemRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	transaction, err := c.transactionService.UpdateTransactionItem(ctx.Request.Context(), id, itemID, req.Quantity)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Item updated successfully", transaction)
+}
+
+// RemoveTransactionItem removes an item from a transaction and returns the repriced transaction
+func (c *TransactionController) RemoveTransactionItem(ctx *gin.Context) {
+	id, itemID, ok := transactionItemIDs(ctx)
+	if !ok {
+		return
+	}
+
+	transaction, err := c.transactionService.RemoveTransactionItem(ctx.Request.Context(), id, itemID)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Item removed successfully", transaction)
+}
+
+// transactionItemIDs parses the transaction and item IDs of an item route, answering 400 when
+// either is invalid
+func transactionItemIDs(ctx *gin.Context) (id, itemID uint, ok bool) {
+	parsed, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid transaction ID")
+		return 0, 0, false
+	}
+	parsedItem, err := strconv.ParseUint(ctx.Param("item_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid item ID")
+		return 0, 0, false
+	}
+	return uint(parsed), uint(parsedItem), true
+}
+
 // UpdateStatusRequest represents a status update request
 type UpdateStatusRequest struct {
 	NewStatus string `json:"new_status" binding:"required"`
 	Reason    string `json:"reason"`
 }
 
+// StatusUpdateResponse confirms a status change
+type StatusUpdateResponse struct {
+	ID     uint                     `json:"id"`
+	Status models.TransactionStatus `json:"status"`
+}
+
 // UpdateTransactionStatus updates transaction status
 func (c *TransactionController) UpdateTransactionStatus(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
@@ -251,7 +439,7 @@ func (c *TransactionController) UpdateTransactionStatus(ctx *gin.Context) {
 
 	var req UpdateStatusRequest
 	if err := ctx.ShouldBindJSON(&req); err != nil {
-		utils.BadRequest(ctx, "Invalid request body: "+err.Error())
+		ctx.Error(utils.BindingError(err))
 		return
 	}
 
@@ -263,23 +451,19 @@ func (c *TransactionController) UpdateTransactionStatus(ctx *gin.Context) {
 
 	// Update status
 	newStatus := models.TransactionStatus(req.NewStatus)
-	err = c.transactionService.UpdateTransactionStatus(uint(id), newStatus, adminUsername, req.Reason)
+	err = c.transactionService.UpdateTransactionStatus(ctx.Request.Context(), uint(id), newStatus, adminUsername, req.Reason)
 	if err != nil {
-		if err.Error() == "transaction not found" {
-			utils.NotFound(ctx, err.Error())
-			return
-		}
-		utils.BadRequest(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	utils.SuccessResponse(ctx, http.StatusOK, "Transaction status updated successfully", map[string]interface{}{
-		"id":     id,
-		"status": req.NewStatus,
+	utils.SuccessResponse(ctx, http.StatusOK, "Transaction status updated successfully", StatusUpdateResponse{
+		ID:     uint(id),
+		Status: newStatus,
 	})
 }
 
-// DeleteTransaction deletes a transaction
+// DeleteTransaction moves a transaction to the trash
 func (c *TransactionController) DeleteTransaction(ctx *gin.Context) {
 	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
 	if err != nil {
@@ -287,20 +471,37 @@ func (c *TransactionController) DeleteTransaction(ctx *gin.Context) {
 		return
 	}
 
-	err = c.transactionService.DeleteTransaction(uint(id))
+	var req DeleteRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	err = c.transactionService.DeleteTransaction(ctx.Request.Context(), uint(id), req.Reason)
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	utils.SuccessResponse(ctx, http.StatusOK, "Transaction deleted successfully", nil)
+	utils.SuccessResponse(ctx, http.StatusOK, "Transaction moved to the trash", nil)
 }
 
-// GetAllTransactions retrieves all transactions with pagination and filtering
+// TransactionListResponse is one page of transactions. Page-based listings report the total,
+// cursor-based listings report the cursor of the next page instead.
+type TransactionListResponse struct {
+	Data       []models.Transaction `json:"data"`
+	Total      *int64               `json:"total,omitempty"`
+	Page       int                  `json:"page,omitempty"`
+	Limit      int                  `json:"limit"`
+	TotalPages *int64               `json:"total_pages,omitempty"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// GetAllTransactions retrieves transactions matching the query filters, paged by page number
+// or, when a cursor parameter is present (empty for the first page), by keyset cursor
 func (c *TransactionController) GetAllTransactions(ctx *gin.Context) {
 	page := 1
 	limit := 10
-	status := ""
 
 	if p := ctx.Query("page"); p != "" {
 		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
@@ -314,33 +515,245 @@ func (c *TransactionController) GetAllTransactions(ctx *gin.Context) {
 		}
 	}
 
-	if s := ctx.Query("status"); s != "" {
-		status = s
+	filter, err := parseTransactionFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
 	}
+	filter.Limit = limit
+	filter.Offset = (page - 1) * limit
 
-	offset := (page - 1) * limit
-	transactions, total, err := c.transactionService.GetAllTransactions(limit, offset, status)
+	cursor, keyset := ctx.GetQuery("cursor")
+	result, err := c.transactionService.ListTransactions(ctx.Request.Context(), filter, keyset, cursor)
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
-	utils.SuccessResponse(ctx, http.StatusOK, "Transactions retrieved successfully", map[string]interface{}{
-		"data":        transactions,
-		"total":       total,
-		"page":        page,
-		"limit":       limit,
-		"total_pages": (total + int64(limit) - 1) / int64(limit),
+	response := TransactionListResponse{
+		Data:       result.Transactions,
+		Limit:      limit,
+		NextCursor: result.NextCursor,
+	}
+	if !keyset {
+		totalPages := (*result.Total + int64(limit) - 1) / int64(limit)
+		response.Total = result.Total
+		response.Page = page
+		response.TotalPages = &totalPages
+	}
+	utils.SuccessResponse(ctx, http.StatusOK, "Transactions retrieved successfully", response)
+}
+
+// ExportTransactions streams the transactions matching the listing filters as CSV or XLSX,
+// one row per item
+func (c *TransactionController) ExportTransactions(ctx *gin.Context) {
+	format, err := export.ParseFormat(ctx.DefaultQuery("format", "csv"))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+	filter, err := parseTransactionFilter(ctx)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	writeExport(ctx, format, "transactions", func(w export.Writer) error {
+		return c.transactionService.ExportTransactions(ctx.Request.Context(), filter, w)
 	})
 }
 
+// writeExport sends the file written by write as an attachment named after name and today's date.
+// The attachment headers go out with the first bytes, so errors raised before anything was written
+// still get a regular error response. Later failures can only be logged.
+func writeExport(ctx *gin.Context, format export.Format, name string, write func(w export.Writer) error) {
+	out := &attachmentWriter{
+		ctx:         ctx,
+		contentType: format.ContentType(),
+		fileName:    fmt.Sprintf("%s-%s.%s", name, time.Now().Format("20060102"), format),
+	}
+
+	writer, err := export.NewWriter(out, format, name)
+	if err == nil {
+		err = write(writer)
+	}
+	if err == nil {
+		return
+	}
+	if out.started {
+		slog.ErrorContext(ctx.Request.Context(), "export failed after the response started",
+			slog.String("export", name),
+			slog.String("error", err.Error()),
+		)
+	}
+	ctx.Error(err)
+}
+
+// attachmentWriter sets the download headers of a response when its body starts
+type attachmentWriter struct {
+	ctx         *gin.Context
+	contentType string
+	fileName    string
+	started     bool
+}
+
+func (w *attachmentWriter) Write(p []byte) (int, error) {
+	if !w.started {
+		w.started = true
+		w.ctx.Header("Content-Type", w.contentType)
+		w.ctx.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", w.fileName))
+		w.ctx.Status(http.StatusOK)
+	}
+	return w.ctx.Writer.Write(p)
+}
+
+// parseTransactionFilter reads the listing filters from the query string.
+// Dates accept YYYY-MM-DD (a whole day, in server time) or RFC 3339 timestamps.
+func parseTransactionFilter(ctx *gin.Context) (services.TransactionFilter, error) {
+	filter := services.TransactionFilter{
+		Keyword:     strings.TrimSpace(ctx.Query("q")),
+		DateField:   ctx.DefaultQuery("date_field", "created_at"),
+		ServiceType: ctx.Query("service_type"),
+		SortField:   ctx.DefaultQuery("sort", "created_at"),
+	}
+	var fields []utils.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, utils.FieldError{Field: field, Message: message})
+	}
+
+	// status may be repeated or comma-separated
+	for _, value := range ctx.QueryArray("status") {
+		for _, status := range strings.Split(value, ",") {
+			if status = strings.TrimSpace(status); status != "" {
+				filter.Statuses = append(filter.Statuses, models.TransactionStatus(status))
+			}
+		}
+	}
+
+	if value := ctx.Query("paid"); value != "" {
+		isPaid, err := strconv.ParseBool(value)
+		if err != nil {
+			invalid("paid", "must be true or false")
+		} else {
+			filter.IsPaid = &isPaid
+		}
+	}
+
+	if value := ctx.Query("from"); value != "" {
+		from, _, err := parseDateParam(value, time.Local)
+		if err != nil {
+			invalid("from", "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		} else {
+			filter.From = &from
+		}
+	}
+	if value := ctx.Query("to"); value != "" {
+		to, wholeDay, err := parseDateParam(value, time.Local)
+		if err != nil {
+			invalid("to", "must be a date (YYYY-MM-DD) or an RFC 3339 timestamp")
+		} else {
+			// A date includes the whole day, a timestamp is an exclusive bound
+			if wholeDay {
+				to = to.AddDate(0, 0, 1)
+			}
+			filter.To = &to
+		}
+	}
+
+	if value := ctx.Query("admin_id"); value != "" {
+		adminID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil || adminID == 0 {
+			invalid("admin_id", "must be a positive integer")
+		} else {
+			filter.AdminID = uint(adminID)
+		}
+	}
+
+	for _, param := range []struct {
+		name   string
+		target **float64
+	}{{"min_price", &filter.MinPrice}, {"max_price", &filter.MaxPrice}} {
+		value := ctx.Query(param.name)
+		if value == "" {
+			continue
+		}
+		price, err := strconv.ParseFloat(value, 64)
+		if err != nil || price < 0 {
+			invalid(param.name, "must be a non-negative number")
+			continue
+		}
+		*param.target = &price
+	}
+
+	switch strings.ToLower(ctx.DefaultQuery("order", "desc")) {
+	case "desc":
+		filter.SortDesc = true
+	case "asc":
+	default:
+		invalid("order", "must be asc or desc")
+	}
+
+	if len(fields) > 0 {
+		return filter, &utils.ValidationError{Fields: fields}
+	}
+	return filter, nil
+}
+
+// parseDateParam parses a date or timestamp query parameter, reporting whether it was a plain date.
+// Plain dates are read in loc.
+func parseDateParam(value string, loc *time.Location) (time.Time, bool, error) {
+	if parsed, err := time.ParseInLocation("2006-01-02", value, loc); err == nil {
+		return parsed, true, nil
+	}
+	parsed, err := time.Parse(time.RFC3339, value)
+	return parsed, false, err
+}
+
+// SearchResponse lists the transactions matching a search query, best match first
+type SearchResponse struct {
+	Query   string               `json:"query"`
+	Results []services.SearchHit `json:"results"`
+}
+
+// SearchTransactions finds transactions by customer, phone, address, notes, code or item names
+func (c *TransactionController) SearchTransactions(ctx *gin.Context) {
+	query := strings.TrimSpace(ctx.Query("q"))
+	if query == "" {
+		ctx.Error(utils.NewValidationError("q", "is required"))
+		return
+	}
+
+	limit := 20
+	if l := ctx.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	results, err := c.transactionService.SearchTransactions(ctx.Request.Context(), query, limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Search completed successfully", SearchResponse{Query: query, Results: results})
+}
+
 // GetDashboard returns dashboard statistics
 func (c *TransactionController) GetDashboard(ctx *gin.Context) {
-	stats, err := c.transactionService.GetDashboardStats()
+	stats, err := c.transactionService.GetDashboardStats(ctx.Request.Context())
 	if err != nil {
-		utils.InternalServerError(ctx, err.Error())
+		ctx.Error(err)
 		return
 	}
 
 	utils.SuccessResponse(ctx, http.StatusOK, "Dashboard statistics retrieved successfully", stats)
 }
+
+// currentAdminID returns the ID of the authenticated admin (set by the auth middleware)
+func currentAdminID(ctx *gin.Context) uint {
+	if id, exists := ctx.Get("admin_id"); exists {
+		return id.(uint)
+	}
+	return 0
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// DeleteRequest is the body of a deletion, which moves the record to the trash
+type DeleteRequest struct {
+	Reason string `json:"reason" binding:"required,max=255"`
+}
+
+// TrashController handles the trash bin of deleted transactions and service prices
+type TrashController struct {
+	trashService *services.TrashService
+}
+
+// NewTrashController creates a new trash controller
+func NewTrashController(trashService *services.TrashService) *TrashController {
+	return &TrashController{trashService: trashService}
+}
+
+// TrashedTransactionListResponse is one page of transactions in the trash
+type TrashedTransactionListResponse struct {
+	Data       []services.TrashedTransaction `json:"data"`
+	Total      int64                         `json:"total"`
+	Page       int                           `json:"page"`
+	Limit      int                           `json:"limit"`
+	TotalPages int64                         `json:"total_pages"`
+}
+
+// TrashedServicePriceListResponse is one page of service prices in the trash
+type TrashedServicePriceListResponse struct {
+	Data       []services.TrashedServicePrice `json:"data"`
+	Total      int64                          `json:"total"`
+	Page       int                            `json:"page"`
+	Limit      int                            `json:"limit"`
+	TotalPages int64                          `json:"total_pages"`
+}
+
+// ListTransactions lists the transactions in the trash, most recently deleted first
+func (c *TrashController) ListTransactions(ctx *gin.Context) {
+	page, limit := trashPage(ctx)
+	transactions, total, err := c.trashService.ListTransactions(ctx.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Deleted transactions retrieved successfully", TrashedTransactionListResponse{
+		Data:       transactions,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	})
+}
+
+// RestoreTransaction takes a transaction out of the trash
+func (c *TrashController) RestoreTransaction(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid transaction ID")
+		return
+	}
+
+	transaction, err := c.trashService.RestoreTransaction(ctx.Request.Context(), uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Transaction restored successfully", transaction)
+}
+
+// PurgeTransaction permanently deletes a transaction in the trash
+func (c *TrashController) PurgeTransaction(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid transaction ID")
+		return
+	}
+
+	if err := c.trashService.PurgeTransaction(ctx.Request.Context(), uint(id)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Transaction permanently deleted", nil)
+}
+
+// ListServicePrices lists the service prices in the trash, most recently deleted first
+func (c *TrashController) ListServicePrices(ctx *gin.Context) {
+	page, limit := trashPage(ctx)
+	servicePrices, total, err := c.trashService.ListServicePrices(ctx.Request.Context(), limit, (page-1)*limit)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Deleted service prices retrieved successfully", TrashedServicePriceListResponse{
+		Data:       servicePrices,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	})
+}
+
+// RestoreServicePrice takes a service price out of the trash
+func (c *TrashController) RestoreServicePrice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid service price ID")
+		return
+	}
+
+	servicePrice, err := c.trashService.RestoreServicePrice(ctx.Request.Context(), uint(id))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Service price restored successfully", servicePrice)
+}
+
+// PurgeServicePrice permanently deletes a service price in the trash
+func (c *TrashController) PurgeServicePrice(ctx *gin.Context) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid service price ID")
+		return
+	}
+
+	if err := c.trashService.PurgeServicePrice(ctx.Request.Context(), uint(id)); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Service price permanently deleted", nil)
+}
+
+// trashPage reads the page and limit query parameters of a trash listing
+func trashPage(ctx *gin.Context) (page, limit int) {
+	page, limit = 1, 20
+	if p := ctx.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+	if l := ctx.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+	return page, limit
+}
@@ -0,0 +1,271 @@
+package controllers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/services"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookController handles partner webhook endpoints and their delivery log
+type WebhookController struct {
+	webhookService *services.WebhookService
+}
+
+// NewWebhookController creates a new webhook controller
+func NewWebhookController(webhookService *services.WebhookService) *WebhookController {
+	return &WebhookController{webhookService: webhookService}
+}
+
+// CreateWebhookRequest represents a webhook endpoint being registered
+type CreateWebhookRequest struct {
+	URL         string   `json:"url" binding:"required"`
+	Description string   `json:"description" binding:"max=255"`
+	Events      []string `json:"events" binding:"required,min=1"`
+	IsActive    *bool    `json:"is_active"` // defaults to true
+}
+
+// UpdateWebhookRequest represents changes to a webhook endpoint; omitted fields are left unchanged
+type UpdateWebhookRequest struct {
+	URL         string   `json:"url"`
+	Description *string  `json:"description" binding:"omitempty,max=255"`
+	Events      []string `json:"events"`
+	IsActive    *bool    `json:"is_active"`
+}
+
+// WebhookSecretResponse is an endpoint together with its signing secret
+type WebhookSecretResponse struct {
+	*models.WebhookEndpoint
+	Secret string `json:"secret"`
+}
+
+// WebhookDeliveryListResponse is one page of an endpoint's deliveries
+type WebhookDeliveryListResponse struct {
+	Data       []models.WebhookDelivery `json:"data"`
+	Total      int64                    `json:"total"`
+	Page       int                      `json:"page"`
+	Limit      int                      `json:"limit"`
+	TotalPages int64                    `json:"total_pages"`
+}
+
+// CreateWebhook registers a webhook endpoint and returns its signing secret, shown only this once
+func (c *WebhookController) CreateWebhook(ctx *gin.Context) {
+	var req CreateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	endpoint := &models.WebhookEndpoint{
+		URL:         req.URL,
+		Description: req.Description,
+		Events:      req.Events,
+		IsActive:    req.IsActive == nil || *req.IsActive,
+		CreatedBy:   currentAdminID(ctx),
+	}
+	if err := c.webhookService.CreateEndpoint(ctx.Request.Context(), endpoint); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusCreated, "Webhook created successfully", WebhookSecretResponse{endpoint, endpoint.Secret})
+}
+
+// ListWebhooks lists every webhook endpoint
+func (c *WebhookController) ListWebhooks(ctx *gin.Context) {
+	endpoints, err := c.webhookService.ListEndpoints(ctx.Request.Context())
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Webhooks retrieved successfully", endpoints)
+}
+
+// GetWebhook retrieves a webhook endpoint
+func (c *WebhookController) GetWebhook(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+
+	endpoint, err := c.webhookService.GetEndpoint(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Webhook retrieved successfully", endpoint)
+}
+
+// UpdateWebhook changes the URL, events, description or state of a webhook endpoint
+func (c *WebhookController) UpdateWebhook(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+
+	var req UpdateWebhookRequest
+	if err := ctx.ShouldBindJSON(&req); err != nil {
+		ctx.Error(utils.BindingError(err))
+		return
+	}
+
+	endpoint, err := c.webhookService.GetEndpoint(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	if req.URL != "" {
+		endpoint.URL = req.URL
+	}
+	if req.Description != nil {
+		endpoint.Description = *req.Description
+	}
+	if req.Events != nil {
+		endpoint.Events = req.Events
+	}
+	if req.IsActive != nil {
+		endpoint.IsActive = *req.IsActive
+	}
+
+	if err := c.webhookService.UpdateEndpoint(ctx.Request.Context(), endpoint); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Webhook updated successfully", endpoint)
+}
+
+// RotateWebhookSecret replaces the signing secret of a webhook endpoint and returns the new one
+func (c *WebhookController) RotateWebhookSecret(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+
+	endpoint, err := c.webhookService.RotateSecret(ctx.Request.Context(), id)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Webhook secret rotated successfully", WebhookSecretResponse{endpoint, endpoint.Secret})
+}
+
+// DeleteWebhook deletes a webhook endpoint and its delivery log
+func (c *WebhookController) DeleteWebhook(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+
+	if err := c.webhookService.DeleteEndpoint(ctx.Request.Context(), id); err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Webhook deleted successfully", nil)
+}
+
+// ListWebhookDeliveries lists the deliveries of a webhook endpoint, latest first
+func (c *WebhookController) ListWebhookDeliveries(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+
+	page := 1
+	limit := 20
+
+	if p := ctx.Query("page"); p != "" {
+		if parsed, err := strconv.Atoi(p); err == nil && parsed > 0 {
+			page = parsed
+		}
+	}
+
+	if l := ctx.Query("limit"); l != "" {
+		if parsed, err := strconv.Atoi(l); err == nil && parsed > 0 && parsed <= 100 {
+			limit = parsed
+		}
+	}
+
+	filter := services.WebhookDeliveryFilter{Event: ctx.Query("event"), Limit: limit, Offset: (page - 1) * limit}
+	switch status := models.WebhookDeliveryStatus(ctx.Query("status")); status {
+	case "", models.DeliveryPending, models.DeliveryDelivered, models.DeliveryFailed:
+		filter.Status = status
+	default:
+		ctx.Error(utils.NewValidationError("status", "must be pending, delivered or failed"))
+		return
+	}
+
+	deliveries, total, err := c.webhookService.ListDeliveries(ctx.Request.Context(), id, filter)
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Webhook deliveries retrieved successfully", WebhookDeliveryListResponse{
+		Data:       deliveries,
+		Total:      total,
+		Page:       page,
+		Limit:      limit,
+		TotalPages: (total + int64(limit) - 1) / int64(limit),
+	})
+}
+
+// GetWebhookDelivery retrieves a delivery with its payload and the endpoint's last response
+func (c *WebhookController) GetWebhookDelivery(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseUint(ctx.Param("delivery_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := c.webhookService.GetDelivery(ctx.Request.Context(), id, uint(deliveryID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusOK, "Webhook delivery retrieved successfully", delivery)
+}
+
+// RedeliverWebhook queues the event of a delivery to be sent to the endpoint again
+func (c *WebhookController) RedeliverWebhook(ctx *gin.Context) {
+	id, ok := webhookID(ctx)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseUint(ctx.Param("delivery_id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := c.webhookService.Redeliver(ctx.Request.Context(), id, uint(deliveryID))
+	if err != nil {
+		ctx.Error(err)
+		return
+	}
+
+	utils.SuccessResponse(ctx, http.StatusAccepted, "Webhook redelivery queued", delivery)
+}
+
+// webhookID parses the endpoint ID in the path, answering 400 when it is invalid
+func webhookID(ctx *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(ctx.Param("id"), 10, 32)
+	if err != nil {
+		utils.BadRequest(ctx, "Invalid webhook ID")
+		return 0, false
+	}
+	return uint(id), true
+}
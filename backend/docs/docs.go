@@ -0,0 +1,9 @@
+// Package docs embeds the OpenAPI specification of the HTTP API
+package docs
+
+import _ "embed"
+
+// OpenAPISpec is the OpenAPI 3 document describing every route registered in routes/
+//
+//go:embed openapi.yaml
+var OpenAPISpec []byte
@@ -0,0 +1,81 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime/debug"
+	"slices"
+)
+
+// Handler reacts to an event. Returning an error hands the event to the handler again later,
+// so handlers must tolerate seeing an event more than once.
+type Handler func(ctx context.Context, envelope Envelope) error
+
+// subscription is a handler registered for one event name
+type subscription struct {
+	subscriber string
+	handle     Handler
+}
+
+// Bus hands events to the handlers subscribed to them. Subscriptions must all be made
+// before the first dispatch.
+type Bus struct {
+	subscriptions map[string][]subscription
+}
+
+// NewBus creates a bus without subscriptions
+func NewBus() *Bus {
+	return &Bus{subscriptions: make(map[string][]subscription)}
+}
+
+// Subscribe registers handle for the events named name. subscriber names the handler in the
+// outbox, which records the subscribers that handled each event; it must be unique per event.
+func (b *Bus) Subscribe(subscriber, name string, handle Handler) {
+	for _, sub := range b.subscriptions[name] {
+		if sub.subscriber == subscriber {
+			panic(fmt.Sprintf("events: %s already subscribes to %s", subscriber, name))
+		}
+	}
+	b.subscriptions[name] = append(b.subscriptions[name], subscription{subscriber: subscriber, handle: handle})
+}
+
+// On subscribes a handler to the events of type E
+func On[E Event](b *Bus, subscriber string, handle func(ctx context.Context, envelope Envelope, event E) error) {
+	var zero E
+	b.Subscribe(subscriber, zero.EventName(), func(ctx context.Context, envelope Envelope) error {
+		event, ok := envelope.Event.(E)
+		if !ok {
+			return fmt.Errorf("unexpected %T for %s", envelope.Event, zero.EventName())
+		}
+		return handle(ctx, envelope, event)
+	})
+}
+
+// Dispatch hands the event to every subscriber not in handled and returns the subscribers
+// that have now handled it, including those in handled. The error joins the failures.
+func (b *Bus) Dispatch(ctx context.Context, envelope Envelope, handled []string) ([]string, error) {
+	done := slices.Clone(handled)
+	var errs []error
+	for _, sub := range b.subscriptions[envelope.Event.EventName()] {
+		if slices.Contains(done, sub.subscriber) {
+			continue
+		}
+		if err := call(ctx, sub.handle, envelope); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", sub.subscriber, err))
+			continue
+		}
+		done = append(done, sub.subscriber)
+	}
+	return done, errors.Join(errs...)
+}
+
+// call runs a handler, turning a panic into an error so one handler cannot stop the dispatcher
+func call(ctx context.Context, handle Handler, envelope Envelope) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v\n%s", r, debug.Stack())
+		}
+	}()
+	return handle(ctx, envelope)
+}
@@ -0,0 +1,142 @@
+// Package events defines the domain events of the laundry and the bus that hands them to
+// in-process subscribers
+package events
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+)
+
+// Event names, as stored in the outbox
+const (
+	NameTransactionCreated = "transaction.created"
+	NameStatusChanged      = "transaction.status_changed"
+	NameTransactionUpdated = "transaction.updated"
+	NamePaymentRecorded    = "payment.recorded"
+	NamePriceChanged       = "price.changed"
+)
+
+// Changes reported by PriceChanged
+const (
+	PriceCreated     = "created"
+	PriceUpdated     = "updated"
+	PriceActivated   = "activated"
+	PriceDeactivated = "deactivated"
+	PriceDeleted     = "deleted"
+	PriceRestored    = "restored" // brought back from the trash
+)
+
+// Event is a domain event. Events are stored as JSON, so every field must survive a round trip.
+type Event interface {
+	EventName() string
+}
+
+// TransactionCreated is published when a transaction is created, in the Queued status
+type TransactionCreated struct {
+	Transaction models.Transaction `json:"transaction"`
+}
+
+// StatusChanged is published when a transaction moves to another status
+type StatusChanged struct {
+	Transaction    models.Transaction       `json:"transaction"` // after the change
+	PreviousStatus models.TransactionStatus `json:"previous_status"`
+	ChangedBy      string                   `json:"changed_by"` // admin username
+	Reason         string                   `json:"reason"`
+}
+
+// TransactionUpdated is published when the details of a transaction are edited
+type TransactionUpdated struct {
+	Transaction models.Transaction `json:"transaction"` // after the change
+}
+
+// PaymentRecorded is published when a transaction is paid
+type PaymentRecorded struct {
+	TransactionCode string         `json:"transaction_code"`
+	Payment         models.Payment `json:"payment"`
+}
+
+// PriceChanged is published when a service price is created, edited, activated, deactivated,
+// deleted or restored
+type PriceChanged struct {
+	Change       string               `json:"change"`
+	ServicePrice models.ServicePrice  `json:"service_price"`
+	Previous     *models.ServicePrice `json:"previous,omitempty"` // before an update
+}
+
+// EventName implements Event
+func (TransactionCreated) EventName() string { return NameTransactionCreated }
+
+// EventName implements Event
+func (StatusChanged) EventName() string { return NameStatusChanged }
+
+// EventName implements Event
+func (TransactionUpdated) EventName() string { return NameTransactionUpdated }
+
+// EventName implements Event
+func (PaymentRecorded) EventName() string { return NamePaymentRecorded }
+
+// EventName implements Event
+func (PriceChanged) EventName() string { return NamePriceChanged }
+
+// TransactionCode returns the code of the transaction an event is about, or "" when it is
+// not about one
+func TransactionCode(event Event) string {
+	switch e := event.(type) {
+	case TransactionCreated:
+		return e.Transaction.TransactionCode
+	case StatusChanged:
+		return e.Transaction.TransactionCode
+	case TransactionUpdated:
+		return e.Transaction.TransactionCode
+	case PaymentRecorded:
+		return e.TransactionCode
+	default:
+		return ""
+	}
+}
+
+// decoders restore each event from its JSON
+var decoders = map[string]func([]byte) (Event, error){
+	NameTransactionCreated: decode[TransactionCreated],
+	NameStatusChanged:      decode[StatusChanged],
+	NameTransactionUpdated: decode[TransactionUpdated],
+	NamePaymentRecorded:    decode[PaymentRecorded],
+	NamePriceChanged:       decode[PriceChanged],
+}
+
+// Decode restores an event stored under name
+func Decode(name string, payload []byte) (Event, error) {
+	decoder, ok := decoders[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown event %q", name)
+	}
+	return decoder(payload)
+}
+
+// decode unmarshals an event of type E
+func decode[E Event](payload []byte) (Event, error) {
+	var event E
+	if err := json.Unmarshal(payload, &event); err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", event.EventName(), err)
+	}
+	return event, nil
+}
+
+// Envelope is a published event with its identity
+type Envelope struct {
+	ID         string    // unique, the same on every delivery of the event
+	OccurredAt time.Time // when the change was made
+	Event      Event
+}
+
+// NewID returns a random event identifier
+func NewID() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return "evt_" + hex.EncodeToString(b)
+}
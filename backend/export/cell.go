@@ -0,0 +1,34 @@
+package export
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// deref replaces a pointer cell by the value it points to, and a nil pointer by nil
+func deref(cell any) any {
+	value := reflect.ValueOf(cell)
+	if value.Kind() != reflect.Pointer {
+		return cell
+	}
+	if value.IsNil() {
+		return nil
+	}
+	return value.Elem().Interface()
+}
+
+// fmtValue formats cells of other types, such as named string types, with their default format
+func fmtValue(cell any) string {
+	return fmt.Sprint(cell)
+}
+
+// textValue keeps text that a spreadsheet would evaluate as a formula (starting with =, +, -,
+// @, tab or carriage return) from being evaluated, by prefixing it with an apostrophe. Names,
+// addresses and notes come from customers, so they must never become live formulas.
+func textValue(text string) string {
+	if text != "" && strings.ContainsRune("=+-@\t\r", rune(text[0])) {
+		return "'" + text
+	}
+	return text
+}
@@ -0,0 +1,65 @@
+package export
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// csvWriter writes rows as RFC 4180 CSV, with times in RFC 3339 and dates as YYYY-MM-DD
+type csvWriter struct {
+	writer *csv.Writer
+	record []string
+}
+
+func newCSVWriter(w io.Writer) *csvWriter {
+	return &csvWriter{writer: csv.NewWriter(w)}
+}
+
+func (w *csvWriter) WriteRow(cells ...any) error {
+	w.record = w.record[:0]
+	for _, cell := range cells {
+		w.record = append(w.record, csvValue(deref(cell)))
+	}
+	return w.writer.Write(w.record)
+}
+
+func (w *csvWriter) Close() error {
+	w.writer.Flush()
+	return w.writer.Error()
+}
+
+// csvValue formats one cell
+func csvValue(cell any) string {
+	switch v := cell.(type) {
+	case nil:
+		return ""
+	case string:
+		return textValue(v)
+	case bool:
+		return strconv.FormatBool(v)
+	case int:
+		return strconv.Itoa(v)
+	case int64:
+		return strconv.FormatInt(v, 10)
+	case uint:
+		return strconv.FormatUint(uint64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case time.Time:
+		if v.IsZero() {
+			return ""
+		}
+		return v.Format(time.RFC3339)
+	case datatypes.Date:
+		if time.Time(v).IsZero() {
+			return ""
+		}
+		return time.Time(v).Format(time.DateOnly)
+	default:
+		return textValue(fmtValue(v))
+	}
+}
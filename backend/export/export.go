@@ -0,0 +1,54 @@
+// Package export writes tabular data as CSV or XLSX spreadsheets, one row at a time,
+// so large exports stream to the client without being held in memory.
+package export
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// Format is a supported spreadsheet format
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ParseFormat validates a spreadsheet format name
+func ParseFormat(format string) (Format, error) {
+	switch Format(strings.ToLower(format)) {
+	case FormatCSV:
+		return FormatCSV, nil
+	case FormatXLSX:
+		return FormatXLSX, nil
+	default:
+		return "", utils.NewValidationError("format", fmt.Sprintf("unsupported export format %q, use csv or xlsx", format))
+	}
+}
+
+// ContentType returns the MIME type of files in the format
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Writer writes the rows of one sheet. Cells may be strings, integers, floats, booleans,
+// time.Time, datatypes.Date, pointers to those or nil. Close must be called to finish the file.
+type Writer interface {
+	WriteRow(cells ...any) error
+	Close() error
+}
+
+// NewWriter creates a writer for the format. sheet names the XLSX worksheet.
+func NewWriter(w io.Writer, format Format, sheet string) (Writer, error) {
+	if format == FormatXLSX {
+		return newXLSXWriter(w, sheet)
+	}
+	return newCSVWriter(w), nil
+}
@@ -0,0 +1,82 @@
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestTextValue(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"", ""},
+		{"Ridwan", "Ridwan"},
+		{"Jl. Merdeka 10", "Jl. Merdeka 10"},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+62812", "'+62812"},
+		{"-1+1", "'-1+1"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"\r=1", "'\r=1"},
+		{"a=1", "a=1"},
+	}
+	for _, tt := range tests {
+		if got := textValue(tt.text); got != tt.want {
+			t.Errorf("textValue(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestCSVNeutralizesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatCSV, "sheet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("=1+1", -5, 2.5); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := buf.String(), "'=1+1,-5,2.5\n"; got != want {
+		t.Errorf("csv = %q, want %q", got, want)
+	}
+}
+
+func TestXLSXNeutralizesFormulas(t *testing.T) {
+	var buf bytes.Buffer
+	w, err := NewWriter(&buf, FormatXLSX, "sheet")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := w.WriteRow("=1+1", "@cmd", -5); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	archive, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	file, err := archive.Open("xl/worksheets/sheet1.xml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+	data, err := io.ReadAll(file)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sheet := string(data)
+	for _, want := range []string{`>&#39;=1+1</t>`, `>&#39;@cmd</t>`, `<v>-5</v>`} {
+		if !strings.Contains(sheet, want) {
+			t.Errorf("sheet does not contain %s:\n%s", want, sheet)
+		}
+	}
+}
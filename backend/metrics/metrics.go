@@ -0,0 +1,111 @@
+package metrics
+
+import (
+	"database/sql"
+	"time"
+)
+
+// Default is the registry exposed at /metrics
+var Default = NewRegistry()
+
+// HTTP metrics, recorded by the metrics middleware
+var (
+	HTTPRequestsTotal = Default.NewCounterVec(
+		"http_requests_total",
+		"Total HTTP requests by method, route template and status code.",
+		"method", "route", "status",
+	)
+	HTTPRequestDuration = Default.NewHistogramVec(
+		"http_request_duration_seconds",
+		"HTTP request latency by method and route template.",
+		DefaultBuckets,
+		"method", "route",
+	)
+	DeprecatedRequestsTotal = Default.NewCounterVec(
+		"http_deprecated_requests_total",
+		"Requests served by deprecated route aliases, by route template.",
+		"route",
+	)
+)
+
+// Database metrics, recorded by the GORM plugin
+var (
+	DBQueryDuration = Default.NewHistogramVec(
+		"db_query_duration_seconds",
+		"Database query latency by GORM operation and table.",
+		DefaultBuckets,
+		"operation", "table",
+	)
+	DBQueryErrorsTotal = Default.NewCounterVec(
+		"db_query_errors_total",
+		"Database queries that returned an error, by GORM operation and table.",
+		"operation", "table",
+	)
+)
+
+// Business metrics, recorded by the services
+var (
+	TransactionsCreatedTotal = Default.NewCounterVec(
+		"laundry_transactions_created_total",
+		"Laundry transactions created.",
+	)
+	StatusTransitionsTotal = Default.NewCounterVec(
+		"laundry_status_transitions_total",
+		"Transaction status transitions by previous and new status.",
+		"from", "to",
+	)
+	RevenueRecordedTotal = Default.NewCounterVec(
+		"laundry_revenue_recorded_rupiah_total",
+		"Revenue recorded when transactions are marked as paid, in rupiah.",
+	)
+	NotificationAttemptsTotal = Default.NewCounterVec(
+		"laundry_notification_attempts_total",
+		"Customer notification delivery attempts by channel and result (sent, retry or failed).",
+		"channel", "result",
+	)
+	EventDispatchesTotal = Default.NewCounterVec(
+		"laundry_event_dispatches_total",
+		"Domain event dispatches to subscribers by event and result (dispatched, retry or failed).",
+		"event", "result",
+	)
+	WebhookAttemptsTotal = Default.NewCounterVec(
+		"laundry_webhook_attempts_total",
+		"Webhook delivery attempts by event and result (delivered, retry or failed).",
+		"event", "result",
+	)
+)
+
+// RegisterDBStats exposes connection pool statistics read from db at scrape time
+func RegisterDBStats(db *sql.DB) {
+	stat := func(read func(sql.DBStats) float64) func() float64 {
+		return func() float64 { return read(db.Stats()) }
+	}
+
+	Default.NewGaugeFunc("db_max_open_connections", "Maximum number of open connections to the database.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxOpenConnections) }))
+	Default.NewGaugeFunc("db_open_connections", "Established connections, both in use and idle.",
+		stat(func(s sql.DBStats) float64 { return float64(s.OpenConnections) }))
+	Default.NewGaugeFunc("db_in_use_connections", "Connections currently in use.",
+		stat(func(s sql.DBStats) float64 { return float64(s.InUse) }))
+	Default.NewGaugeFunc("db_idle_connections", "Idle connections.",
+		stat(func(s sql.DBStats) float64 { return float64(s.Idle) }))
+	Default.NewCounterFunc("db_wait_count_total", "Connections waited for because the pool was exhausted.",
+		stat(func(s sql.DBStats) float64 { return float64(s.WaitCount) }))
+	Default.NewCounterFunc("db_wait_duration_seconds_total", "Total time blocked waiting for a new connection.",
+		stat(func(s sql.DBStats) float64 { return s.WaitDuration.Seconds() }))
+	Default.NewCounterFunc("db_max_idle_closed_total", "Connections closed due to max idle connections.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxIdleClosed) }))
+	Default.NewCounterFunc("db_max_lifetime_closed_total", "Connections closed due to max connection lifetime.",
+		stat(func(s sql.DBStats) float64 { return float64(s.MaxLifetimeClosed) }))
+}
+
+// RegisterOpenStreams exposes the number of open live event streams, read from count at scrape time
+func RegisterOpenStreams(count func() int) {
+	Default.NewGaugeFunc("laundry_open_streams", "Live event streams currently open on this instance.",
+		func() float64 { return float64(count()) })
+}
+
+// ObserveDuration records the time elapsed since start in a histogram series
+func ObserveDuration(h *HistogramVec, start time.Time, labelValues ...string) {
+	h.Observe(time.Since(start).Seconds(), labelValues...)
+}
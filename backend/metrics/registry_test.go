@@ -0,0 +1,106 @@
+package metrics
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// scrape renders a registry as a scrape would
+func scrape(t *testing.T, r *Registry) string {
+	t.Helper()
+	var b strings.Builder
+	if err := r.WriteText(&b); err != nil {
+		t.Fatal(err)
+	}
+	return b.String()
+}
+
+func TestCounterText(t *testing.T) {
+	r := NewRegistry()
+	requests := r.NewCounterVec("test_requests_total", "Requests served.\nBy route and \\ status.", "route", "status")
+	requests.Inc("/orders", "200")
+	requests.Add(2, "/orders", "200")
+	requests.Inc(`/track/"x"\y`+"\n", "404")
+	requests.Add(-1, "/orders", "200") // counters never go down
+	r.NewCounterVec("test_restarts_total", "Restarts.")
+
+	want := `# HELP test_requests_total Requests served.\nBy route and \\ status.
+# TYPE test_requests_total counter
+test_requests_total{route="/orders",status="200"} 3
+test_requests_total{route="/track/\"x\"\\y\n",status="404"} 1
+# HELP test_restarts_total Restarts.
+# TYPE test_restarts_total counter
+test_restarts_total 0
+`
+	if got := scrape(t, r); got != want {
+		t.Errorf("scrape =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestHistogramText(t *testing.T) {
+	r := NewRegistry()
+	latency := r.NewHistogramVec("test_duration_seconds", "Latency.", []float64{1, 0.1, 0.5}, "method")
+	latency.Observe(0.05, "GET")
+	latency.Observe(0.1, "GET") // on a bound, counted in that bucket
+	latency.Observe(0.7, "GET")
+	latency.Observe(3, "GET") // above every bound, only in +Inf
+	latency.Observe(0.2, "POST")
+
+	want := `# HELP test_duration_seconds Latency.
+# TYPE test_duration_seconds histogram
+test_duration_seconds_bucket{method="GET",le="0.1"} 2
+test_duration_seconds_bucket{method="GET",le="0.5"} 2
+test_duration_seconds_bucket{method="GET",le="1"} 3
+test_duration_seconds_bucket{method="GET",le="+Inf"} 4
+test_duration_seconds_sum{method="GET"} 3.85
+test_duration_seconds_count{method="GET"} 4
+test_duration_seconds_bucket{method="POST",le="0.1"} 0
+test_duration_seconds_bucket{method="POST",le="0.5"} 1
+test_duration_seconds_bucket{method="POST",le="1"} 1
+test_duration_seconds_bucket{method="POST",le="+Inf"} 1
+test_duration_seconds_sum{method="POST"} 0.2
+test_duration_seconds_count{method="POST"} 1
+`
+	if got := scrape(t, r); got != want {
+		t.Errorf("scrape =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestGaugeFuncText(t *testing.T) {
+	r := NewRegistry()
+	r.NewGaugeFunc("test_open", "Open things.", func() float64 { return 7 })
+	r.NewCounterFunc("test_seen_total", "Seen things.", func() float64 { return 1e21 })
+	r.NewGaugeFunc("test_inf", "Unbounded.", func() float64 { return math.Inf(1) })
+
+	want := `# HELP test_inf Unbounded.
+# TYPE test_inf gauge
+test_inf +Inf
+# HELP test_open Open things.
+# TYPE test_open gauge
+test_open 7
+# HELP test_seen_total Seen things.
+# TYPE test_seen_total counter
+test_seen_total 1e+21
+`
+	if got := scrape(t, r); got != want {
+		t.Errorf("scrape =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestRegistryPanics(t *testing.T) {
+	expectPanic := func(name string, fn func()) {
+		t.Helper()
+		defer func() {
+			if recover() == nil {
+				t.Errorf("%s did not panic", name)
+			}
+		}()
+		fn()
+	}
+
+	r := NewRegistry()
+	counter := r.NewCounterVec("test_total", "Test.", "a")
+	expectPanic("duplicate name", func() { r.NewGaugeFunc("test_total", "Again.", func() float64 { return 0 }) })
+	expectPanic("missing label value", func() { counter.Inc() })
+}
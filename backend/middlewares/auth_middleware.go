@@ -1,13 +1,15 @@
 package middlewares
 
 import (
+	"slices"
 	"strings"
 
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func AuthMiddleware() gin.HandlerFunc {
+func AuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// Allow OPTIONS requests to pass through for CORS preflight
 		if c.Request.Method == "OPTIONS" {
@@ -32,9 +34,10 @@ func AuthMiddleware() gin.HandlerFunc {
 
 		tokenString := parts[1]
 
-		claims, err := utils.VerifyToken(tokenString)
+		claims, err := jwtManager.VerifyToken(tokenString)
 		if err != nil {
-			utils.Unauthorized(c, "Invalid or expired token: "+err.Error())
+			c.Error(err)
+			utils.Unauthorized(c, "Invalid or expired token")
 			c.Abort()
 			return
 		}
@@ -43,6 +46,71 @@ func AuthMiddleware() gin.HandlerFunc {
 		c.Set("admin_username", claims.Username)
 		c.Set("admin_email", claims.Email)
 		c.Set("admin_full_name", claims.FullName)
+		c.Set("admin_role", claims.Role)
+		c.Request = c.Request.WithContext(utils.WithActor(c.Request.Context(), utils.Actor{
+			AdminID:  claims.AdminID,
+			Username: claims.Username,
+			IP:       c.ClientIP(),
+		}))
+
+		c.Next()
+	}
+}
+
+// EventStreamAuthMiddleware authenticates like AuthMiddleware but also accepts the token in the
+// access_token query parameter, because browsers cannot set headers on an EventSource
+func EventStreamAuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+	auth := AuthMiddleware(jwtManager)
+	return func(c *gin.Context) {
+		if token := c.Query("access_token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		auth(c)
+	}
+}
+
+// RequireRole only lets admins with one of the given roles through. It runs after AuthMiddleware;
+// a role change takes effect when the admin next logs in.
+func RequireRole(roles ...models.AdminRole) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !slices.Contains(roles, models.AdminRole(c.GetString("admin_role"))) {
+			utils.Forbidden(c, "Your role does not allow this")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
+// CustomerAuthMiddleware authenticates customers of the portal by their bearer token and sets
+// customer_phone to the phone number it was issued for. Admin tokens are not accepted.
+func CustomerAuthMiddleware(jwtManager *utils.JWTManager) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request.Method == "OPTIONS" {
+			c.Next()
+			return
+		}
+
+		parts := strings.Split(c.GetHeader("Authorization"), " ")
+		if len(parts) != 2 || strings.ToLower(parts[0]) != "bearer" {
+			utils.Unauthorized(c, "Authorization header missing or invalid")
+			c.Abort()
+			return
+		}
+
+		claims, err := jwtManager.VerifyCustomerToken(parts[1])
+		if err != nil {
+			c.Error(err)
+			utils.Unauthorized(c, "Invalid or expired token")
+			c.Abort()
+			return
+		}
+
+		c.Set("customer_phone", claims.Subject)
+		c.Request = c.Request.WithContext(utils.WithActor(c.Request.Context(), utils.Actor{
+			Username: "customer " + claims.Subject,
+			IP:       c.ClientIP(),
+		}))
 
 		c.Next()
 	}
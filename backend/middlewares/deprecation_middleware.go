@@ -0,0 +1,37 @@
+package middlewares
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// Deprecation describes routes that are scheduled for removal
+type Deprecation struct {
+	DeprecatedAt time.Time // when the routes were deprecated
+	Sunset       time.Time // when the routes stop being served
+	Prefix       string    // path prefix of the deprecated routes, e.g. /api
+	Successor    string    // path prefix that replaces Prefix, e.g. /api/v1
+}
+
+// DeprecationMiddleware announces the deprecation on every response using the Deprecation
+// (RFC 9745) and Sunset (RFC 8594) headers, and links to the successor route
+func DeprecationMiddleware(d Deprecation) gin.HandlerFunc {
+	deprecation := fmt.Sprintf("@%d", d.DeprecatedAt.Unix())
+	sunset := d.Sunset.UTC().Format(http.TimeFormat)
+
+	return func(c *gin.Context) {
+		c.Header("Deprecation", deprecation)
+		c.Header("Sunset", sunset)
+		if successor, ok := strings.CutPrefix(c.Request.URL.Path, d.Prefix); ok {
+			c.Header("Link", fmt.Sprintf(`<%s%s>; rel="successor-version"`, d.Successor, successor))
+		}
+		metrics.DeprecatedRequestsTotal.Inc(c.FullPath())
+
+		c.Next()
+	}
+}
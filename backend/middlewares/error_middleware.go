@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ErrorMiddleware turns the last error a handler attached with c.Error into a utils.Response.
+// Domain errors keep their message; anything else is reported as a generic internal error
+// so database and other internal details never reach the client (the logger middleware
+// still records the original error).
+func ErrorMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Next()
+
+		if len(c.Errors) == 0 || c.Writer.Written() {
+			return
+		}
+
+		err := c.Errors.Last().Err
+		status, code, message := translateError(err)
+
+		var details []utils.FieldError
+		var validationErr *utils.ValidationError
+		if errors.As(err, &validationErr) {
+			details = validationErr.Fields
+		}
+
+		utils.ErrorResponseWithCode(c, status, code, http.StatusText(status), message, details)
+	}
+}
+
+// translateError maps an error to its HTTP status, error code and client-facing message
+func translateError(err error) (int, string, string) {
+	switch {
+	case errors.Is(err, utils.ErrValidation):
+		return http.StatusBadRequest, utils.CodeValidation, publicMessage(err, "invalid request")
+	case errors.Is(err, utils.ErrUnauthorized):
+		return http.StatusUnauthorized, utils.CodeUnauthorized, publicMessage(err, "unauthorized")
+	case errors.Is(err, utils.ErrNotFound):
+		return http.StatusNotFound, utils.CodeNotFound, publicMessage(err, "resource not found")
+	case errors.Is(err, utils.ErrConflict):
+		return http.StatusConflict, utils.CodeConflict, publicMessage(err, "resource already exists")
+	case errors.Is(err, utils.ErrInvalidTransition):
+		return http.StatusUnprocessableEntity, utils.CodeInvalidTransition, publicMessage(err, "invalid status transition")
+	case errors.Is(err, utils.ErrRateLimited):
+		return http.StatusTooManyRequests, utils.CodeRateLimited, publicMessage(err, "too many requests")
+	case errors.Is(err, utils.ErrUnavailable):
+		return http.StatusServiceUnavailable, utils.CodeUnavailable, publicMessage(err, "service temporarily unavailable")
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, context.Canceled):
+		return http.StatusGatewayTimeout, utils.CodeTimeout, "request took too long to complete"
+	default:
+		return http.StatusInternalServerError, utils.CodeInternal, "an unexpected error occurred"
+	}
+}
+
+// publicMessage returns the message of the domain or validation error in err's chain without
+// any wrapping context, or fallback when there is none
+func publicMessage(err error, fallback string) string {
+	var domainErr *utils.DomainError
+	if errors.As(err, &domainErr) {
+		return domainErr.Message
+	}
+	var validationErr *utils.ValidationError
+	if errors.As(err, &validationErr) {
+		return validationErr.Error()
+	}
+	return fallback
+}
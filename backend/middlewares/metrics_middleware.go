@@ -0,0 +1,27 @@
+package middlewares
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsMiddleware records request counts and latency per route template
+func MetricsMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+
+		c.Next()
+
+		// Use the route template, not the raw path, to keep label cardinality bounded
+		route := c.FullPath()
+		if route == "" {
+			route = "unmatched"
+		}
+		method := c.Request.Method
+		metrics.HTTPRequestsTotal.Inc(method, route, strconv.Itoa(c.Writer.Status()))
+		metrics.ObserveDuration(metrics.HTTPRequestDuration, start, method, route)
+	}
+}
@@ -0,0 +1,78 @@
+package middlewares
+
+import (
+	"math"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// rateWindow counts the requests of one client in the current window
+type rateWindow struct {
+	start time.Time
+	count int
+}
+
+// rateLimiter counts requests per client in fixed windows. Counts live in memory, so every
+// server instance enforces the limit on its own.
+type rateLimiter struct {
+	limit  int
+	window time.Duration
+
+	mu        sync.Mutex
+	clients   map[string]*rateWindow
+	lastSweep time.Time
+}
+
+// allow counts a request of key at now and reports whether it is within the limit, or else how
+// long until the next window starts
+func (l *rateLimiter) allow(key string, now time.Time) (time.Duration, bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	// Forget clients whose window has passed, at most once per window
+	if now.Sub(l.lastSweep) >= l.window {
+		for client, w := range l.clients {
+			if now.Sub(w.start) >= l.window {
+				delete(l.clients, client)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	w, ok := l.clients[key]
+	if !ok || now.Sub(w.start) >= l.window {
+		l.clients[key] = &rateWindow{start: now, count: 1}
+		return 0, true
+	}
+	if w.count >= l.limit {
+		return w.start.Add(l.window).Sub(now), false
+	}
+	w.count++
+	return 0, true
+}
+
+// RateLimitMiddleware allows every client IP limit requests per window and answers 429 with a
+// Retry-After header beyond that. Routes sharing the returned handler share the limit. A limit
+// of 0 disables it.
+func RateLimitMiddleware(limit int, window time.Duration) gin.HandlerFunc {
+	if limit <= 0 {
+		return func(c *gin.Context) { c.Next() }
+	}
+
+	limiter := &rateLimiter{limit: limit, window: window, clients: make(map[string]*rateWindow)}
+	return func(c *gin.Context) {
+		retryAfter, ok := limiter.allow(c.ClientIP(), time.Now())
+		if !ok {
+			seconds := int(math.Ceil(retryAfter.Seconds()))
+			c.Header("Retry-After", strconv.Itoa(seconds))
+			c.Error(utils.NewError(utils.ErrRateLimited, "too many requests, try again in %d seconds", seconds))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
@@ -0,0 +1,84 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// TimeoutMiddleware bounds the request context with a deadline so database queries started by
+// the handler are cancelled once it passes. A zero timeout leaves requests unbounded.
+func TimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if timeout <= 0 {
+			c.Next()
+			return
+		}
+
+		// Routes with their own deadline still need to know when the client goes away
+		c.Set(connContextKey, c.Request.Context())
+		ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+		defer cancel()
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+
+		if c.GetBool(extendedTimeoutKey) || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			return
+		}
+		slog.WarnContext(ctx, "request deadline exceeded",
+			slog.String("route", c.FullPath()),
+			slog.Duration("timeout", timeout),
+		)
+		if !c.Writer.Written() {
+			utils.GatewayTimeout(c, "Request took too long to complete")
+		}
+	}
+}
+
+const (
+	// extendedTimeoutKey marks requests whose deadline ExtendedTimeoutMiddleware replaced
+	extendedTimeoutKey = "extended_timeout"
+	// connContextKey holds the request context before TimeoutMiddleware bounded it, which is
+	// cancelled only when the client disconnects or the server shuts down
+	connContextKey = "conn_context"
+)
+
+// ExtendedTimeoutMiddleware gives long-running routes such as exports their own deadline in place
+// of the request timeout, and pushes the connection write deadline back to match. A zero timeout
+// leaves them unbounded. The request is still cancelled when the client disconnects, also
+// after the request timeout has passed.
+func ExtendedTimeoutMiddleware(timeout time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parent := c.Request.Context()
+		conn := parent
+		if value, ok := c.Get(connContextKey); ok {
+			conn = value.(context.Context)
+		}
+
+		// Keep the values of the request context but take cancellation from the connection only
+		ctx, cancel := context.WithCancel(context.WithoutCancel(parent))
+		defer cancel()
+		stop := context.AfterFunc(conn, cancel)
+		defer stop()
+
+		var deadline time.Time
+		if timeout > 0 {
+			deadline = time.Now().Add(timeout)
+			var cancelTimeout context.CancelFunc
+			ctx, cancelTimeout = context.WithDeadline(ctx, deadline)
+			defer cancelTimeout()
+		}
+		// Not every writer supports deadlines (e.g. in tests); the server default applies then
+		_ = http.NewResponseController(c.Writer).SetWriteDeadline(deadline)
+
+		c.Set(extendedTimeoutKey, true)
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
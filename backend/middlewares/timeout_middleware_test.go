@@ -0,0 +1,73 @@
+package middlewares
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// TestExtendedTimeoutCancelsAfterRequestDeadline disconnects the client after the request
+// timeout has passed: the extended route must still be cancelled
+func TestExtendedTimeoutCancelsAfterRequestDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	conn, disconnect := context.WithCancel(context.Background())
+	defer disconnect()
+	pastDeadline := make(chan struct{})
+	result := make(chan error, 1)
+
+	r := gin.New()
+	r.Use(TimeoutMiddleware(10 * time.Millisecond))
+	r.GET("/export", ExtendedTimeoutMiddleware(0), func(c *gin.Context) {
+		ctx := c.Request.Context()
+		time.Sleep(30 * time.Millisecond) // the request timeout fires meanwhile
+		if ctx.Err() != nil {
+			result <- errors.New("cancelled by the request timeout")
+			return
+		}
+		close(pastDeadline)
+		select {
+		case <-ctx.Done():
+			result <- nil
+		case <-time.After(time.Second):
+			result <- errors.New("not cancelled when the client disconnected")
+		}
+	})
+
+	go func() {
+		<-pastDeadline
+		disconnect()
+	}()
+	req := httptest.NewRequest(http.MethodGet, "/export", nil).WithContext(conn)
+	r.ServeHTTP(httptest.NewRecorder(), req)
+
+	if err := <-result; err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestExtendedTimeoutDeadline(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	r := gin.New()
+	r.Use(TimeoutMiddleware(time.Hour))
+	r.GET("/export", ExtendedTimeoutMiddleware(10*time.Millisecond), func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			c.Status(http.StatusNoContent)
+		case <-time.After(time.Second):
+			c.Status(http.StatusOK)
+		}
+	})
+
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/export", nil))
+	if w.Code != http.StatusNoContent {
+		t.Errorf("status = %d, want the extended deadline to cancel the request", w.Code)
+	}
+}
@@ -0,0 +1,95 @@
+package migrations
+
+import (
+	"slices"
+	"strings"
+	"testing"
+	"testing/fstest"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{"empty", "", nil},
+		{"comments and blanks only", "-- nothing to do\n\n   \n", nil},
+		{"one statement", "DROP TABLE a;", []string{"DROP TABLE a"}},
+		{
+			"several statements with comments",
+			"-- header\nCREATE TABLE a (\n    id INT -- inline\n);\n\n-- second\nDROP TABLE b;\n",
+			[]string{"CREATE TABLE a (\n    id INT -- inline\n)", "DROP TABLE b"},
+		},
+		{"semicolon inside a line", "UPDATE a SET note = 'x;y' WHERE id = 1;", []string{"UPDATE a SET note = 'x;y' WHERE id = 1"}},
+		{"unterminated last statement", "DROP TABLE a;\nDROP TABLE b", []string{"DROP TABLE a", "DROP TABLE b"}},
+		{"windows line endings", "DROP TABLE a;\r\nDROP TABLE b;\r\n", []string{"DROP TABLE a", "DROP TABLE b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := splitStatements(tt.script); !slices.Equal(got, tt.want) {
+				t.Errorf("splitStatements = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"sql/0002_second.up.sql":      {Data: []byte("UP 2;")},
+		"sql/0002_second.down.sql":    {Data: []byte("DOWN 2;")},
+		"sql/0001_first_one.up.sql":   {Data: []byte("UP 1;")},
+		"sql/0001_first_one.down.sql": {Data: []byte("DOWN 1;")},
+		"sql/README.md":               {Data: []byte("ignored")},
+	}
+	migrations, err := loadMigrations(fsys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []Migration{
+		{Version: 1, Name: "first_one", Up: "UP 1;", Down: "DOWN 1;"},
+		{Version: 2, Name: "second", Up: "UP 2;", Down: "DOWN 2;"},
+	}
+	if !slices.Equal(migrations, want) {
+		t.Errorf("loadMigrations = %+v, want %+v", migrations, want)
+	}
+}
+
+func TestLoadMigrationsErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		files fstest.MapFS
+		want  string
+	}{
+		{"missing down", fstest.MapFS{"sql/0001_a.up.sql": {Data: []byte("UP;")}}, "missing its up or down script"},
+		{"missing up", fstest.MapFS{"sql/0001_a.down.sql": {Data: []byte("DOWN;")}}, "missing its up or down script"},
+		{"no name", fstest.MapFS{"sql/0001.up.sql": {Data: []byte("UP;")}}, "invalid migration file name"},
+		{"bad version", fstest.MapFS{"sql/first_a.up.sql": {Data: []byte("UP;")}}, "invalid migration version"},
+		{"no sql directory", fstest.MapFS{}, "failed to read migrations"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := loadMigrations(tt.files)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("err = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+// TestEmbeddedMigrations checks the migrations shipped in the binary: complete pairs,
+// numbered without gaps, each with at least one statement each way
+func TestEmbeddedMigrations(t *testing.T) {
+	migrations, err := loadMigrations(files)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, migration := range migrations {
+		if migration.Version != int64(i+1) {
+			t.Errorf("migration %04d_%s is numbered out of sequence, want %04d", migration.Version, migration.Name, i+1)
+		}
+		if len(splitStatements(migration.Up)) == 0 || len(splitStatements(migration.Down)) == 0 {
+			t.Errorf("migration %04d_%s has an empty script", migration.Version, migration.Name)
+		}
+	}
+}
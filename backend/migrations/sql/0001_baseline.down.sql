@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS transaction_history;
+DROP TABLE IF EXISTS transaction_items;
+DROP TABLE IF EXISTS transactions;
+DROP TABLE IF EXISTS service_prices;
+DROP TABLE IF EXISTS admins;
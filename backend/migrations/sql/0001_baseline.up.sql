@@ -0,0 +1,85 @@
+-- Baseline schema, equivalent to what GORM AutoMigrate produced for the
+-- models before versioned migrations were introduced. Every statement is
+-- idempotent so databases created by AutoMigrate can adopt it safely.
+
+CREATE TABLE IF NOT EXISTS admins (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    username VARCHAR(255) NOT NULL,
+    password VARCHAR(255) NOT NULL,
+    email VARCHAR(255),
+    full_name VARCHAR(255),
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_admins_username (username),
+    UNIQUE INDEX idx_admins_email (email),
+    INDEX idx_admins_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS transactions (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    transaction_code VARCHAR(50) NOT NULL,
+    customer_name VARCHAR(255) NOT NULL,
+    customer_phone VARCHAR(20),
+    customer_address TEXT,
+    notes TEXT,
+    status VARCHAR(20) DEFAULT 'antrian',
+    total_price DOUBLE,
+    is_paid BOOLEAN DEFAULT false,
+    pickup_date DATE,
+    completed_at DATETIME(3) NULL,
+    admin_id BIGINT UNSIGNED,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_transactions_transaction_code (transaction_code),
+    INDEX idx_transactions_deleted_at (deleted_at),
+    CONSTRAINT fk_transactions_admin FOREIGN KEY (admin_id) REFERENCES admins (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS transaction_items (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    service_type VARCHAR(50) NOT NULL,
+    item_name VARCHAR(100) NOT NULL,
+    quantity BIGINT DEFAULT 1,
+    unit_price DOUBLE,
+    subtotal DOUBLE,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_transaction_items_transaction_id (transaction_id),
+    INDEX idx_transaction_items_deleted_at (deleted_at),
+    CONSTRAINT fk_transactions_items FOREIGN KEY (transaction_id) REFERENCES transactions (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS transaction_history (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    previous_status VARCHAR(20),
+    new_status VARCHAR(20) NOT NULL,
+    changed_by VARCHAR(255),
+    reason TEXT,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_transaction_history_transaction_id (transaction_id),
+    CONSTRAINT fk_transactions_status_history FOREIGN KEY (transaction_id) REFERENCES transactions (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS service_prices (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    service_type VARCHAR(50) NOT NULL,
+    item_name VARCHAR(100) NOT NULL,
+    description VARCHAR(255),
+    price DOUBLE NOT NULL,
+    is_active BOOLEAN DEFAULT true,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    deleted_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_service_item (service_type, item_name),
+    INDEX idx_service_prices_deleted_at (deleted_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
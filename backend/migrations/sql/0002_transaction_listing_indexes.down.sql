@@ -0,0 +1,6 @@
+DROP INDEX idx_transaction_items_service_type ON transaction_items;
+DROP INDEX idx_transactions_total_price_id ON transactions;
+DROP INDEX idx_transactions_completed_at ON transactions;
+DROP INDEX idx_transactions_pickup_date ON transactions;
+DROP INDEX idx_transactions_status ON transactions;
+DROP INDEX idx_transactions_created_at_id ON transactions;
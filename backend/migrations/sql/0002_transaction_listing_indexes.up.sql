@@ -0,0 +1,8 @@
+-- Indexes backing the transaction listing filters, sorts and keyset cursor.
+
+CREATE INDEX idx_transactions_created_at_id ON transactions (created_at, id);
+CREATE INDEX idx_transactions_status ON transactions (status);
+CREATE INDEX idx_transactions_pickup_date ON transactions (pickup_date);
+CREATE INDEX idx_transactions_completed_at ON transactions (completed_at);
+CREATE INDEX idx_transactions_total_price_id ON transactions (total_price, id);
+CREATE INDEX idx_transaction_items_service_type ON transaction_items (service_type, transaction_id);
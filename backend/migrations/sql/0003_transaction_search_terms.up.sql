@@ -0,0 +1,13 @@
+-- Inverted index of the searchable transaction fields, one row per normalized word.
+-- Existing transactions are indexed by running `search reindex` after this migration.
+
+CREATE TABLE IF NOT EXISTS transaction_search_terms (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    field VARCHAR(20) NOT NULL,
+    term VARCHAR(64) NOT NULL,
+    PRIMARY KEY (id),
+    INDEX idx_transaction_search_terms_term (term, transaction_id),
+    INDEX idx_transaction_search_terms_transaction_id (transaction_id),
+    CONSTRAINT fk_transaction_search_terms_transaction FOREIGN KEY (transaction_id) REFERENCES transactions (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin;
@@ -0,0 +1,40 @@
+-- Cashier shifts and the payments recorded during them. open_admin_id is only set while a
+-- shift is open, so its unique index allows a single open shift per admin.
+
+CREATE TABLE IF NOT EXISTS shifts (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    admin_id BIGINT UNSIGNED NOT NULL,
+    opened_at DATETIME(3) NOT NULL,
+    closed_at DATETIME(3) NULL,
+    opening_cash DOUBLE NOT NULL DEFAULT 0,
+    expected_cash DOUBLE NULL,
+    counted_cash DOUBLE NULL,
+    variance DOUBLE NULL,
+    opening_notes TEXT,
+    closing_notes TEXT,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    open_admin_id BIGINT UNSIGNED AS (CASE WHEN closed_at IS NULL THEN admin_id END) STORED,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_shifts_open_admin_id (open_admin_id),
+    INDEX idx_shifts_admin_id_opened_at (admin_id, opened_at),
+    CONSTRAINT fk_shifts_admin FOREIGN KEY (admin_id) REFERENCES admins (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS payments (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    shift_id BIGINT UNSIGNED NULL,
+    admin_id BIGINT UNSIGNED NOT NULL,
+    amount DOUBLE NOT NULL,
+    method VARCHAR(20) NOT NULL,
+    paid_at DATETIME(3) NOT NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_payments_transaction_id (transaction_id),
+    INDEX idx_payments_shift_id_method (shift_id, method),
+    INDEX idx_payments_paid_at (paid_at),
+    CONSTRAINT fk_payments_transaction FOREIGN KEY (transaction_id) REFERENCES transactions (id),
+    CONSTRAINT fk_payments_shift FOREIGN KEY (shift_id) REFERENCES shifts (id),
+    CONSTRAINT fk_payments_admin FOREIGN KEY (admin_id) REFERENCES admins (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS notifications;
+
+ALTER TABLE transactions
+    DROP COLUMN customer_language,
+    DROP COLUMN customer_email;
@@ -0,0 +1,28 @@
+-- Customer contact preferences and the outbox of customer notifications. The worker polls
+-- pending rows by next_attempt_at.
+
+ALTER TABLE transactions
+    ADD COLUMN customer_email VARCHAR(255) NULL AFTER customer_address,
+    ADD COLUMN customer_language VARCHAR(5) NULL AFTER customer_email;
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    transaction_id BIGINT UNSIGNED NOT NULL,
+    event VARCHAR(30) NOT NULL,
+    channel VARCHAR(20) NOT NULL,
+    recipient VARCHAR(255) NOT NULL,
+    language VARCHAR(5) NOT NULL,
+    subject VARCHAR(255) NULL,
+    body TEXT NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(3) NOT NULL,
+    last_error TEXT,
+    sent_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_notifications_status_next_attempt_at (status, next_attempt_at),
+    INDEX idx_notifications_transaction_id (transaction_id),
+    CONSTRAINT fk_notifications_transaction FOREIGN KEY (transaction_id) REFERENCES transactions (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,39 @@
+-- Partner webhook endpoints and the log of their deliveries, which doubles as the delivery
+-- queue polled by next_attempt_at.
+
+CREATE TABLE IF NOT EXISTS webhook_endpoints (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    url VARCHAR(2048) NOT NULL,
+    description VARCHAR(255) NULL,
+    events JSON NOT NULL,
+    secret VARCHAR(100) NOT NULL,
+    is_active TINYINT(1) NOT NULL DEFAULT 1,
+    created_by BIGINT UNSIGNED NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    endpoint_id BIGINT UNSIGNED NOT NULL,
+    event_id VARCHAR(40) NOT NULL,
+    event VARCHAR(50) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(10) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(3) NOT NULL,
+    response_status INT NULL,
+    response_body TEXT,
+    response_time_ms BIGINT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    delivered_at DATETIME(3) NULL,
+    redelivery_of BIGINT UNSIGNED NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_webhook_deliveries_status_next_attempt_at (status, next_attempt_at),
+    INDEX idx_webhook_deliveries_endpoint_id (endpoint_id, id),
+    INDEX idx_webhook_deliveries_event_id (event_id),
+    CONSTRAINT fk_webhook_deliveries_endpoint FOREIGN KEY (endpoint_id) REFERENCES webhook_endpoints (id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,5 @@
+ALTER TABLE transaction_history
+    DROP INDEX idx_transaction_history_event_id,
+    DROP COLUMN event_id;
+
+DROP TABLE IF EXISTS event_outbox;
@@ -0,0 +1,26 @@
+-- Outbox of domain events, written in the same database transaction as the change that
+-- published them. The dispatcher polls pending rows by next_attempt_at. History records carry
+-- the event that wrote them, so dispatching an event twice records it once.
+
+CREATE TABLE IF NOT EXISTS event_outbox (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    event_id VARCHAR(40) NOT NULL,
+    name VARCHAR(50) NOT NULL,
+    payload JSON NOT NULL,
+    status VARCHAR(12) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at DATETIME(3) NOT NULL,
+    handled JSON NOT NULL,
+    last_error TEXT,
+    occurred_at DATETIME(3) NOT NULL,
+    dispatched_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_event_outbox_event_id (event_id),
+    INDEX idx_event_outbox_status_next_attempt_at (status, next_attempt_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE transaction_history
+    ADD COLUMN event_id VARCHAR(40) NULL AFTER reason,
+    ADD UNIQUE INDEX idx_transaction_history_event_id (event_id);
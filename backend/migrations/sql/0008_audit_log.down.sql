@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS audit_logs;
+
+ALTER TABLE admins
+    DROP COLUMN role;
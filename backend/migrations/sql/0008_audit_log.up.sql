@@ -0,0 +1,25 @@
+-- Admin roles and the audit log. Admins that existed before roles keep full access as owners;
+-- new admins are staff unless created as owners.
+
+ALTER TABLE admins
+    ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'staff' AFTER full_name;
+
+UPDATE admins SET role = 'owner';
+
+CREATE TABLE IF NOT EXISTS audit_logs (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    actor_id BIGINT UNSIGNED NULL,
+    actor_name VARCHAR(255) NOT NULL,
+    action VARCHAR(30) NOT NULL,
+    entity_type VARCHAR(30) NOT NULL,
+    entity_id BIGINT UNSIGNED NOT NULL,
+    entity_label VARCHAR(255) NULL,
+    changes JSON NOT NULL,
+    ip VARCHAR(45) NULL,
+    request_id VARCHAR(64) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_audit_logs_created_at (created_at),
+    INDEX idx_audit_logs_entity (entity_type, entity_id, created_at),
+    INDEX idx_audit_logs_actor (actor_id, created_at)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
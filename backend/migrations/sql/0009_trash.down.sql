@@ -0,0 +1,11 @@
+ALTER TABLE transaction_history
+    DROP INDEX idx_transaction_history_deleted_at,
+    DROP COLUMN deleted_at;
+
+ALTER TABLE service_prices
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by;
+
+ALTER TABLE transactions
+    DROP COLUMN delete_reason,
+    DROP COLUMN deleted_by;
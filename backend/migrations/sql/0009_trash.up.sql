@@ -0,0 +1,26 @@
+-- Trash bin: deleted transactions and service prices keep who deleted them and why until they
+-- are restored or purged. Items and history are deleted with their transaction, at the same
+-- time, so a restore brings back exactly what the deletion took away.
+
+ALTER TABLE transactions
+    ADD COLUMN deleted_by VARCHAR(255) NOT NULL DEFAULT '' AFTER deleted_at,
+    ADD COLUMN delete_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER deleted_by;
+
+ALTER TABLE service_prices
+    ADD COLUMN deleted_by VARCHAR(255) NOT NULL DEFAULT '' AFTER deleted_at,
+    ADD COLUMN delete_reason VARCHAR(255) NOT NULL DEFAULT '' AFTER deleted_by;
+
+ALTER TABLE transaction_history
+    ADD COLUMN deleted_at DATETIME(3) NULL AFTER created_at,
+    ADD INDEX idx_transaction_history_deleted_at (deleted_at);
+
+-- Transactions deleted before the trash bin left their items and history behind
+UPDATE transaction_items ti
+    JOIN transactions t ON t.id = ti.transaction_id
+SET ti.deleted_at = t.deleted_at
+WHERE t.deleted_at IS NOT NULL AND ti.deleted_at IS NULL;
+
+UPDATE transaction_history h
+    JOIN transactions t ON t.id = h.transaction_id
+SET h.deleted_at = t.deleted_at
+WHERE t.deleted_at IS NOT NULL;
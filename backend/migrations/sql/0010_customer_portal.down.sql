@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS customer_login_codes;
+
+DROP TABLE IF EXISTS customers;
@@ -0,0 +1,24 @@
+-- Customer portal: profiles saved by customers, keyed by their phone number in international
+-- form, and the one-time codes they log in with. A phone has at most one pending code.
+
+CREATE TABLE IF NOT EXISTS customers (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    phone VARCHAR(20) NOT NULL,
+    address TEXT,
+    created_at DATETIME(3) NULL,
+    updated_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    UNIQUE INDEX idx_customers_phone (phone)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS customer_login_codes (
+    id BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    phone VARCHAR(20) NOT NULL,
+    code_hash VARCHAR(255) NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    expires_at DATETIME(3) NOT NULL,
+    used_at DATETIME(3) NULL,
+    created_at DATETIME(3) NULL,
+    PRIMARY KEY (id),
+    INDEX idx_customer_login_codes_phone (phone)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,3 @@
+ALTER TABLE notifications
+    DROP INDEX idx_notifications_event_id_channel,
+    DROP COLUMN event_id;
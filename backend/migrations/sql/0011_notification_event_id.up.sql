@@ -0,0 +1,6 @@
+-- Notifications carry the event that queued them, so dispatching an event twice queues its
+-- messages once per channel. Notifications queued before this have no event.
+
+ALTER TABLE notifications
+    ADD COLUMN event_id VARCHAR(40) NULL AFTER transaction_id,
+    ADD UNIQUE INDEX idx_notifications_event_id_channel (event_id, channel);
@@ -0,0 +1,3 @@
+ALTER TABLE event_outbox
+    DROP INDEX idx_event_outbox_transaction_code,
+    DROP COLUMN transaction_code;
@@ -0,0 +1,13 @@
+-- Outbox events keep the code of the transaction they are about in their own column, so a
+-- tracking stream replays its transaction's events from an index instead of scanning payloads.
+
+ALTER TABLE event_outbox
+    ADD COLUMN transaction_code VARCHAR(50) NULL AFTER name,
+    ADD INDEX idx_event_outbox_transaction_code (transaction_code, id);
+
+UPDATE event_outbox
+SET transaction_code = COALESCE(
+    JSON_UNQUOTE(JSON_EXTRACT(payload, '$.transaction.transaction_code')),
+    JSON_UNQUOTE(JSON_EXTRACT(payload, '$.transaction_code'))
+)
+WHERE name IN ('transaction.created', 'transaction.status_changed', 'transaction.updated', 'payment.recorded');
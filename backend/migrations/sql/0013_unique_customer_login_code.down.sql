@@ -0,0 +1,3 @@
+ALTER TABLE customer_login_codes
+    DROP INDEX idx_customer_login_codes_phone,
+    ADD INDEX idx_customer_login_codes_phone (phone);
@@ -0,0 +1,9 @@
+-- A phone number has exactly one login code row, replaced in place when a new code is sent,
+-- so concurrent requests cannot leave two valid codes behind.
+
+DELETE older FROM customer_login_codes older
+    JOIN customer_login_codes newer ON newer.phone = older.phone AND newer.id > older.id;
+
+ALTER TABLE customer_login_codes
+    DROP INDEX idx_customer_login_codes_phone,
+    ADD UNIQUE INDEX idx_customer_login_codes_phone (phone);
@@ -6,13 +6,27 @@ import (
 	"gorm.io/gorm"
 )
 
+// AdminRole decides what an admin may do. Owners may do everything staff may, and read the audit log.
+type AdminRole string
+
+const (
+	RoleOwner AdminRole = "owner"
+	RoleStaff AdminRole = "staff"
+)
+
+// IsValid reports whether the role is known
+func (r AdminRole) IsValid() bool {
+	return r == RoleOwner || r == RoleStaff
+}
+
 // Admin represents an admin user in the system
 type Admin struct {
-	ID       uint   `gorm:"primaryKey" json:"id"`
-	Username string `gorm:"type:varchar(255);uniqueIndex;not null" json:"username"`
-	Password string `gorm:"type:varchar(255);not null" json:"password,omitempty"`
-	Email    string `gorm:"type:varchar(255);uniqueIndex" json:"email"`
-	FullName string `gorm:"type:varchar(255)" json:"full_name"`
+	ID       uint      `gorm:"primaryKey" json:"id"`
+	Username string    `gorm:"type:varchar(255);uniqueIndex;not null" json:"username"`
+	Password string    `gorm:"type:varchar(255);not null" json:"password,omitempty"`
+	Email    string    `gorm:"type:varchar(255);uniqueIndex" json:"email"`
+	FullName string    `gorm:"type:varchar(255)" json:"full_name"`
+	Role     AdminRole `gorm:"type:varchar(20);not null" json:"role"`
 
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
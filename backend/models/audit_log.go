@@ -0,0 +1,67 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// AuditAction is what a change did to an entity
+type AuditAction string
+
+const (
+	AuditCreate       AuditAction = "create"
+	AuditUpdate       AuditAction = "update"
+	AuditDelete       AuditAction = "delete"
+	AuditStatusChange AuditAction = "status_change"
+	AuditActivate     AuditAction = "activate"
+	AuditDeactivate   AuditAction = "deactivate"
+	AuditVoid         AuditAction = "void"          // payments undone by marking a transaction unpaid
+	AuditClose        AuditAction = "close"         // a shift closed with its counted cash
+	AuditRotateSecret AuditAction = "rotate_secret" // the secret itself is never recorded
+	AuditRestore      AuditAction = "restore"       // brought back from the trash
+	AuditPurge        AuditAction = "purge"         // removed from the trash for good
+)
+
+// Audited entity types
+const (
+	AuditEntityTransaction  = "transaction"
+	AuditEntityItem         = "transaction_item"
+	AuditEntityPayment      = "payment"
+	AuditEntityServicePrice = "service_price"
+	AuditEntityShift        = "shift"
+	AuditEntityWebhook      = "webhook_endpoint"
+	AuditEntityAdmin        = "admin"
+	AuditEntityCustomer     = "customer"
+)
+
+// FieldChange is the value of a field before and after a change, null when it did not exist
+type FieldChange struct {
+	From any `json:"from"`
+	To   any `json:"to"`
+}
+
+// AuditChanges are the changed fields of an entity by their JSON name
+type AuditChanges map[string]FieldChange
+
+// AuditLog records who changed which entity, how, when and from where. Entries are only
+// ever added, never updated or deleted by the application.
+type AuditLog struct {
+	ID          uint                             `gorm:"primaryKey" json:"id"`
+	ActorID     *uint                            `json:"actor_id"`                                     // admin who made the change, null for the system and the command line
+	ActorName   string                           `gorm:"type:varchar(255);not null" json:"actor_name"` // admin username, "customer <phone>", "cli" or "system"
+	Action      AuditAction                      `gorm:"type:varchar(30);not null" json:"action"`
+	EntityType  string                           `gorm:"type:varchar(30);not null" json:"entity_type"`
+	EntityID    uint                             `gorm:"not null" json:"entity_id"`
+	EntityLabel string                           `gorm:"type:varchar(255)" json:"entity_label"` // e.g. the transaction code, kept after the entity is gone
+	Changes     datatypes.JSONType[AuditChanges] `gorm:"not null" json:"changes"`
+	IP          string                           `gorm:"type:varchar(45)" json:"ip"`
+	RequestID   string                           `gorm:"type:varchar(64)" json:"request_id"`
+
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// TableName specifies the table name for AuditLog model
+func (AuditLog) TableName() string {
+	return "audit_logs"
+}
@@ -0,0 +1,37 @@
+package models
+
+import "time"
+
+// Customer is what a customer saved through the portal. Customers are identified by their
+// phone number in international form (628123456789); their orders are matched by that number.
+type Customer struct {
+	ID      uint   `gorm:"primaryKey" json:"id"`
+	Phone   string `gorm:"type:varchar(20);uniqueIndex;not null" json:"phone"`
+	Address string `gorm:"type:text" json:"address"` // default for new orders without an address
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Customer model
+func (Customer) TableName() string {
+	return "customers"
+}
+
+// CustomerLoginCode is a one-time code sent to a phone number to log in to the portal. Only its
+// hash is stored; it stops working once used, expired or tried too often.
+type CustomerLoginCode struct {
+	ID        uint       `gorm:"primaryKey" json:"id"`
+	Phone     string     `gorm:"type:varchar(20);not null;uniqueIndex" json:"phone"` // one code per phone, replaced in place
+	CodeHash  string     `gorm:"type:varchar(255);not null" json:"-"`
+	Attempts  int        `gorm:"not null;default:0" json:"attempts"` // tries so far, right or wrong
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for CustomerLoginCode model
+func (CustomerLoginCode) TableName() string {
+	return "customer_login_codes"
+}
@@ -0,0 +1,39 @@
+package models
+
+import "time"
+
+// NotificationStatus is the delivery state of an outbox notification
+type NotificationStatus string
+
+const (
+	NotificationPending NotificationStatus = "pending" // waiting for its first or next attempt
+	NotificationSent    NotificationStatus = "sent"
+	NotificationFailed  NotificationStatus = "failed" // gave up after the last attempt or a permanent error
+)
+
+// Notification is a customer message in the outbox. It is rendered when queued, so every
+// attempt sends the same text, and delivered by the notification worker with retries.
+type Notification struct {
+	ID            uint               `gorm:"primaryKey" json:"id"`
+	TransactionID uint               `gorm:"not null;index" json:"transaction_id"`
+	EventID       *string            `gorm:"type:varchar(40);uniqueIndex:idx_notifications_event_id_channel" json:"-"`                // event that queued it, so a redispatch queues no duplicate
+	Event         string             `gorm:"type:varchar(30);not null" json:"event"`                                                  // status that triggered it
+	Channel       string             `gorm:"type:varchar(20);not null;uniqueIndex:idx_notifications_event_id_channel" json:"channel"` // log, whatsapp, sms or email
+	Recipient     string             `gorm:"type:varchar(255);not null" json:"recipient"`
+	Language      string             `gorm:"type:varchar(5);not null" json:"language"`
+	Subject       string             `gorm:"type:varchar(255)" json:"subject"`
+	Body          string             `gorm:"type:text;not null" json:"body"`
+	Status        NotificationStatus `gorm:"type:varchar(10);not null;default:pending" json:"status"`
+	Attempts      int                `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time          `gorm:"not null" json:"next_attempt_at"`
+	LastError     string             `gorm:"type:text" json:"last_error"`
+	SentAt        *time.Time         `json:"sent_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Notification model
+func (Notification) TableName() string {
+	return "notifications"
+}
@@ -0,0 +1,41 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// OutboxStatus is the dispatch state of an outbox event
+type OutboxStatus string
+
+const (
+	OutboxPending    OutboxStatus = "pending" // waiting for its first or next dispatch
+	OutboxDispatched OutboxStatus = "dispatched"
+	OutboxFailed     OutboxStatus = "failed" // gave up after the last attempt
+)
+
+// OutboxEvent is a domain event stored in the same database transaction as the change that
+// published it, then dispatched to the in-process subscribers by the event dispatcher
+type OutboxEvent struct {
+	ID              uint                        `gorm:"primaryKey" json:"id"`
+	EventID         string                      `gorm:"type:varchar(40);not null;uniqueIndex" json:"event_id"`
+	Name            string                      `gorm:"type:varchar(50);not null" json:"name"`
+	TransactionCode *string                     `gorm:"type:varchar(50);index:idx_event_outbox_transaction_code" json:"transaction_code,omitempty"` // transaction the event is about, if any
+	Payload         datatypes.JSON              `gorm:"not null" json:"payload"`
+	Status          OutboxStatus                `gorm:"type:varchar(12);not null" json:"status"`
+	Attempts        int                         `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt   time.Time                   `gorm:"not null" json:"next_attempt_at"`
+	Handled         datatypes.JSONSlice[string] `gorm:"not null" json:"handled"` // subscribers done with it, skipped on retries
+	LastError       string                      `gorm:"type:text" json:"last_error"`
+	OccurredAt      time.Time                   `gorm:"not null" json:"occurred_at"`
+	DispatchedAt    *time.Time                  `json:"dispatched_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "event_outbox"
+}
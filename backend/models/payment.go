@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// PaymentMethod is how a customer paid
+type PaymentMethod string
+
+const (
+	PaymentCash     PaymentMethod = "cash"
+	PaymentTransfer PaymentMethod = "transfer"
+	PaymentQRIS     PaymentMethod = "qris"
+)
+
+// Payment records a transaction being paid. Payments taken during the cashier's open shift
+// belong to it; cash payments make up the shift's expected cash.
+type Payment struct {
+	ID            uint          `gorm:"primaryKey" json:"id"`
+	TransactionID uint          `gorm:"not null;index" json:"transaction_id"`
+	ShiftID       *uint         `gorm:"index" json:"shift_id"`
+	AdminID       uint          `gorm:"not null" json:"admin_id"`
+	Amount        float64       `gorm:"not null" json:"amount"`
+	Method        PaymentMethod `gorm:"type:varchar(20);not null" json:"method"`
+	PaidAt        time.Time     `gorm:"not null;index" json:"paid_at"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName specifies the table name for Payment model
+func (Payment) TableName() string {
+	return "payments"
+}
@@ -15,9 +15,11 @@ type ServicePrice struct {
 	Price       float64 `gorm:"not null" json:"price"`
 	IsActive    bool    `gorm:"default:true" json:"is_active"`
 
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `gorm:"index" json:"-"`
+	DeletedBy    string         `gorm:"type:varchar(255);not null;default:''" json:"-"` // who moved it to the trash
+	DeleteReason string         `gorm:"type:varchar(255);not null;default:''" json:"-"`
 }
 
 // TableName specifies the table name for ServicePrice model
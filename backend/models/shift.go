@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// Shift is a cashier's working period at the cash drawer. Closing it locks the expected,
+// counted and variance amounts; payments can no longer be recorded into it.
+type Shift struct {
+	ID           uint       `gorm:"primaryKey" json:"id"`
+	AdminID      uint       `gorm:"not null;index" json:"admin_id"`
+	Admin        *Admin     `gorm:"foreignKey:AdminID" json:"-"`
+	OpenedAt     time.Time  `gorm:"not null" json:"opened_at"`
+	ClosedAt     *time.Time `json:"closed_at"`
+	OpeningCash  float64    `gorm:"not null;default:0" json:"opening_cash"` // float in the drawer when the shift opened
+	ExpectedCash *float64   `json:"expected_cash"`                          // opening cash plus cash payments, set on close
+	CountedCash  *float64   `json:"counted_cash"`                           // cash counted in the drawer on close
+	Variance     *float64   `json:"variance"`                               // counted minus expected, negative when cash is missing
+	OpeningNotes string     `gorm:"type:text" json:"opening_notes"`
+	ClosingNotes string     `gorm:"type:text" json:"closing_notes"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for Shift model
+func (Shift) TableName() string {
+	return "shifts"
+}
+
+// IsClosed reports whether the shift has been closed
+func (s *Shift) IsClosed() bool {
+	return s.ClosedAt != nil
+}
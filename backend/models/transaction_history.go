@@ -1,6 +1,10 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 // TransactionHistory tracks status changes of a transaction
 type TransactionHistory struct {
@@ -10,8 +14,10 @@ type TransactionHistory struct {
 	NewStatus      TransactionStatus `gorm:"type:varchar(20);not null" json:"new_status"`
 	ChangedBy      string            `gorm:"type:varchar(255)" json:"changed_by"` // admin username
 	Reason         string            `gorm:"type:text" json:"reason"`
+	EventID        *string           `gorm:"type:varchar(40);uniqueIndex" json:"-"` // event that recorded it, so a redispatch adds no duplicate
 
-	CreatedAt time.Time `json:"created_at"`
+	CreatedAt time.Time      `json:"created_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"` // set with the deletion of its transaction
 }
 
 // TableName specifies the table name for TransactionHistory model
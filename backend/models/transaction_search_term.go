@@ -0,0 +1,24 @@
+package models
+
+// Searchable transaction fields, recorded on each search term
+const (
+	SearchFieldTransactionCode = "transaction_code"
+	SearchFieldCustomerName    = "customer_name"
+	SearchFieldCustomerPhone   = "customer_phone"
+	SearchFieldCustomerAddress = "customer_address"
+	SearchFieldNotes           = "notes"
+	SearchFieldItemName        = "item_name"
+)
+
+// TransactionSearchTerm is one normalized word of a searchable transaction field
+type TransactionSearchTerm struct {
+	ID            uint   `gorm:"primaryKey" json:"id"`
+	TransactionID uint   `gorm:"not null;index" json:"transaction_id"`
+	Field         string `gorm:"type:varchar(20);not null" json:"field"`
+	Term          string `gorm:"type:varchar(64);not null;index" json:"term"`
+}
+
+// TableName specifies the table name for TransactionSearchTerm model
+func (TransactionSearchTerm) TableName() string {
+	return "transaction_search_terms"
+}
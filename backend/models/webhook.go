@@ -0,0 +1,63 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// WebhookEndpoint is a partner URL subscribed to events
+type WebhookEndpoint struct {
+	ID          uint                        `gorm:"primaryKey" json:"id"`
+	URL         string                      `gorm:"type:varchar(2048);not null" json:"url"`
+	Description string                      `gorm:"type:varchar(255)" json:"description"`
+	Events      datatypes.JSONSlice[string] `gorm:"type:json;not null" json:"events"`
+	Secret      string                      `gorm:"type:varchar(100);not null" json:"-"` // signs the deliveries, only shown when created or rotated
+	IsActive    bool                        `gorm:"not null" json:"is_active"`
+	CreatedBy   uint                        `json:"created_by"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for WebhookEndpoint model
+func (WebhookEndpoint) TableName() string {
+	return "webhook_endpoints"
+}
+
+// WebhookDeliveryStatus is the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one event sent to one endpoint, with the outcome of its last attempt.
+// Redeliveries are new rows carrying the same event ID and payload.
+type WebhookDelivery struct {
+	ID             uint                  `gorm:"primaryKey" json:"id"`
+	EndpointID     uint                  `gorm:"not null;index" json:"endpoint_id"`
+	Endpoint       *WebhookEndpoint      `gorm:"foreignKey:EndpointID" json:"-"`
+	EventID        string                `gorm:"type:varchar(40);not null;index" json:"event_id"`
+	Event          string                `gorm:"type:varchar(50);not null" json:"event"`
+	Payload        datatypes.JSON        `gorm:"type:json;not null" json:"payload"`
+	Status         WebhookDeliveryStatus `gorm:"type:varchar(10);not null;default:pending" json:"status"`
+	Attempts       int                   `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  time.Time             `gorm:"not null" json:"next_attempt_at"`
+	ResponseStatus *int                  `json:"response_status"`
+	ResponseBody   string                `gorm:"type:text" json:"response_body"`
+	ResponseTimeMs int64                 `json:"response_time_ms"`
+	LastError      string                `gorm:"type:text" json:"last_error"`
+	DeliveredAt    *time.Time            `json:"delivered_at"`
+	RedeliveryOf   *uint                 `json:"redelivery_of"` // delivery this one was manually redelivered from
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// TableName specifies the table name for WebhookDelivery model
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
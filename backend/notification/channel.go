@@ -0,0 +1,91 @@
+// Package notification renders customer messages and delivers them through pluggable channels
+package notification
+
+import (
+	"context"
+	"errors"
+	"strings"
+)
+
+// Channel names, as used in the configuration and the outbox
+const (
+	ChannelLog      = "log"
+	ChannelWhatsApp = "whatsapp"
+	ChannelSMS      = "sms"
+	ChannelEmail    = "email"
+)
+
+// Contact holds the ways a customer can be reached
+type Contact struct {
+	Name  string
+	Phone string
+	Email string
+}
+
+// Message is a rendered notification addressed to one recipient
+type Message struct {
+	To      string
+	Subject string // only used by channels that support it
+	Body    string
+}
+
+// Channel delivers messages through one provider
+type Channel interface {
+	// Name identifies the channel in the outbox
+	Name() string
+	// Recipient returns the address of the contact on this channel, or "" when it has none
+	Recipient(contact Contact) string
+	// Send delivers the message. Errors wrapped with Permanent are not retried.
+	Send(ctx context.Context, msg Message) error
+}
+
+// permanentError marks a delivery failure that retrying cannot fix
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as a failure that retrying cannot fix, such as a rejected recipient
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// InternationalPhone returns an Indonesian phone number in international form without the
+// plus sign (628123456789), or "" when phone does not look like a phone number
+func InternationalPhone(phone string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(phone) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		case r == '+' && i == 0:
+		default:
+			return ""
+		}
+	}
+
+	digits := b.String()
+	switch {
+	case strings.HasPrefix(digits, "0"):
+		digits = "62" + digits[1:]
+	case strings.HasPrefix(digits, "8"):
+		digits = "62" + digits
+	}
+	if len(digits) < 9 {
+		return ""
+	}
+	return digits
+}
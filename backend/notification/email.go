@@ -0,0 +1,130 @@
+package notification
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"mime/quotedprintable"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"time"
+)
+
+// EmailChannel sends plain text emails through an SMTP server. Port 465 uses implicit TLS,
+// other ports upgrade with STARTTLS when the server offers it.
+type EmailChannel struct {
+	host     string
+	port     int
+	username string
+	password string
+	from     string
+}
+
+// NewEmailChannel creates a channel sending as from, which may include a display name
+func NewEmailChannel(host string, port int, username, password, from string) *EmailChannel {
+	return &EmailChannel{host: host, port: port, username: username, password: password, from: from}
+}
+
+// Name identifies the channel in the outbox
+func (c *EmailChannel) Name() string { return ChannelEmail }
+
+// Recipient returns the email address of the contact when it is valid
+func (c *EmailChannel) Recipient(contact Contact) string {
+	if contact.Email == "" {
+		return ""
+	}
+	address, err := mail.ParseAddress(contact.Email)
+	if err != nil {
+		return ""
+	}
+	return address.Address
+}
+
+// Send delivers the message as a plain text email
+func (c *EmailChannel) Send(ctx context.Context, msg Message) error {
+	from, err := mail.ParseAddress(c.from)
+	if err != nil {
+		return Permanent(fmt.Errorf("invalid sender address: %w", err))
+	}
+
+	addr := net.JoinHostPort(c.host, strconv.Itoa(c.port))
+	tlsConfig := &tls.Config{ServerName: c.host}
+	var conn net.Conn
+	if c.port == 465 {
+		conn, err = (&tls.Dialer{Config: tlsConfig}).DialContext(ctx, "tcp", addr)
+	} else {
+		conn, err = (&net.Dialer{}).DialContext(ctx, "tcp", addr)
+	}
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, c.host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if c.port != 465 {
+		if ok, _ := client.Extension("STARTTLS"); ok {
+			if err := client.StartTLS(tlsConfig); err != nil {
+				return err
+			}
+		}
+	}
+	if c.username != "" {
+		if err := client.Auth(smtp.PlainAuth("", c.username, c.password, c.host)); err != nil {
+			return err
+		}
+	}
+	if err := client.Mail(from.Address); err != nil {
+		return err
+	}
+	if err := client.Rcpt(msg.To); err != nil {
+		// 5xx replies reject the recipient for good
+		var reply *textproto.Error
+		if errors.As(err, &reply) && reply.Code >= 500 {
+			return Permanent(err)
+		}
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(c.compose(from, msg)); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// compose builds the email with UTF-8 headers and a quoted-printable body
+func (c *EmailChannel) compose(from *mail.Address, msg Message) []byte {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", from.String())
+	fmt.Fprintf(&buf, "To: %s\r\n", msg.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", msg.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: quoted-printable\r\n\r\n")
+
+	body := quotedprintable.NewWriter(&buf)
+	body.Write([]byte(msg.Body))
+	body.Close()
+	return buf.Bytes()
+}
@@ -0,0 +1,37 @@
+package notification
+
+import (
+	"context"
+	"log/slog"
+)
+
+// LogChannel writes messages to a logger instead of sending them, for development and testing
+type LogChannel struct {
+	logger *slog.Logger
+}
+
+// NewLogChannel creates a channel that logs every message to logger
+func NewLogChannel(logger *slog.Logger) *LogChannel {
+	return &LogChannel{logger: logger}
+}
+
+// Name identifies the channel in the outbox
+func (c *LogChannel) Name() string { return ChannelLog }
+
+// Recipient returns the phone number of the contact, or its email address when it has none
+func (c *LogChannel) Recipient(contact Contact) string {
+	if phone := InternationalPhone(contact.Phone); phone != "" {
+		return phone
+	}
+	return contact.Email
+}
+
+// Send logs the message
+func (c *LogChannel) Send(ctx context.Context, msg Message) error {
+	c.logger.InfoContext(ctx, "notification",
+		slog.String("to", msg.To),
+		slog.String("subject", msg.Subject),
+		slog.String("body", msg.Body),
+	)
+	return nil
+}
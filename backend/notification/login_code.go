@@ -0,0 +1,29 @@
+package notification
+
+import (
+	"fmt"
+	"time"
+)
+
+// loginCodeSources are the subject and body of the login code message per language, filled
+// with the laundry name, the code and its validity in minutes
+var loginCodeSources = map[string][2]string{
+	LanguageIndonesian: {
+		"Kode masuk %[1]s",
+		"Kode masuk %[1]s Anda: %[2]s. Berlaku %[3]d menit. Jangan berikan kode ini kepada siapa pun, termasuk petugas kami.",
+	},
+	LanguageEnglish: {
+		"%[1]s login code",
+		"Your %[1]s login code is %[2]s. It is valid for %[3]d minutes. Never share this code with anyone, including our staff.",
+	},
+}
+
+// RenderLoginCode renders the message carrying a customer portal login code valid for ttl
+func RenderLoginCode(lang, laundryName, code string, ttl time.Duration) (subject, body string, err error) {
+	source, ok := loginCodeSources[lang]
+	if !ok {
+		return "", "", fmt.Errorf("no %s template for login codes", lang)
+	}
+	minutes := max(int(ttl.Round(time.Minute)/time.Minute), 1)
+	return fmt.Sprintf(source[0], laundryName), fmt.Sprintf(source[1], laundryName, code, minutes), nil
+}
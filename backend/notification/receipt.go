@@ -0,0 +1,96 @@
+package notification
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+)
+
+// receiptWidth is the number of characters per receipt line, as on a 58 mm thermal printer
+const receiptWidth = 32
+
+// receiptLabels are the fixed texts of a receipt per language
+var receiptLabels = map[string]map[string]string{
+	LanguageIndonesian: {
+		"title":    "STRUK PESANAN",
+		"code":     "Kode",
+		"date":     "Tanggal",
+		"customer": "Pelanggan",
+		"pickup":   "Estimasi ambil",
+		"total":    "Total",
+		"paid":     "LUNAS",
+		"unpaid":   "BELUM LUNAS",
+		"thanks":   "Terima kasih!",
+	},
+	LanguageEnglish: {
+		"title":    "ORDER RECEIPT",
+		"code":     "Code",
+		"date":     "Date",
+		"customer": "Customer",
+		"pickup":   "Pick-up",
+		"total":    "Total",
+		"paid":     "PAID",
+		"unpaid":   "UNPAID",
+		"thanks":   "Thank you!",
+	},
+}
+
+// WriteReceipt writes a plain text receipt of a transaction and its items in lang
+func WriteReceipt(w io.Writer, lang, laundryName string, transaction *models.Transaction) error {
+	labels, ok := receiptLabels[lang]
+	if !ok {
+		return fmt.Errorf("no %s template for receipts", lang)
+	}
+	date := dateFunc(lang)
+	rule := strings.Repeat("-", receiptWidth)
+
+	var b strings.Builder
+	b.WriteString(receiptCenter(laundryName))
+	b.WriteString(receiptCenter(labels["title"]))
+	b.WriteString(rule + "\n")
+	b.WriteString(receiptRow(labels["code"], transaction.TransactionCode))
+	b.WriteString(receiptRow(labels["date"], date(transaction.CreatedAt)+" "+transaction.CreatedAt.Format("15:04")))
+	b.WriteString(receiptRow(labels["customer"], transaction.CustomerName))
+	if pickup := time.Time(transaction.PickupDate); !pickup.IsZero() {
+		b.WriteString(receiptRow(labels["pickup"], date(pickup)))
+	}
+	b.WriteString(rule + "\n")
+	for _, item := range transaction.Items {
+		b.WriteString(item.ServiceType + " - " + item.ItemName + "\n")
+		b.WriteString(receiptRow(fmt.Sprintf("  %d x %s", item.Quantity, Rupiah(item.UnitPrice)), Rupiah(item.Subtotal)))
+	}
+	b.WriteString(rule + "\n")
+	b.WriteString(receiptRow(labels["total"], Rupiah(transaction.TotalPrice)))
+	if transaction.IsPaid {
+		b.WriteString(receiptRow("", labels["paid"]))
+		for _, payment := range transaction.Payments {
+			b.WriteString(receiptRow("  "+string(payment.Method), date(payment.PaidAt)))
+		}
+	} else {
+		b.WriteString(receiptRow("", labels["unpaid"]))
+	}
+	b.WriteString(rule + "\n")
+	b.WriteString(receiptCenter(labels["thanks"]))
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// receiptRow writes left and right aligned to both edges, on two lines when they do not fit
+func receiptRow(left, right string) string {
+	gap := receiptWidth - utf8.RuneCountInString(left) - utf8.RuneCountInString(right)
+	if gap < 1 {
+		return left + "\n" + strings.Repeat(" ", max(receiptWidth-utf8.RuneCountInString(right), 0)) + right + "\n"
+	}
+	return left + strings.Repeat(" ", gap) + right + "\n"
+}
+
+// receiptCenter centers text on its own line
+func receiptCenter(text string) string {
+	padding := max((receiptWidth-utf8.RuneCountInString(text))/2, 0)
+	return strings.Repeat(" ", padding) + text + "\n"
+}
@@ -0,0 +1,45 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+)
+
+// SMSChannel sends text messages through an HTTP SMS gateway. It posts
+// {"to", "from", "message"} as JSON with the API key as a bearer token.
+type SMSChannel struct {
+	client *http.Client
+	url    string
+	apiKey string
+	sender string
+}
+
+// NewSMSChannel creates a channel posting to the gateway at url
+func NewSMSChannel(client *http.Client, url, apiKey, sender string) *SMSChannel {
+	return &SMSChannel{client: client, url: url, apiKey: apiKey, sender: sender}
+}
+
+// Name identifies the channel in the outbox
+func (c *SMSChannel) Name() string { return ChannelSMS }
+
+// Recipient returns the phone number of the contact in international form with a leading plus
+func (c *SMSChannel) Recipient(contact Contact) string {
+	if phone := InternationalPhone(contact.Phone); phone != "" {
+		return "+" + phone
+	}
+	return ""
+}
+
+// Send delivers the message body as an SMS
+func (c *SMSChannel) Send(ctx context.Context, msg Message) error {
+	payload := map[string]string{
+		"to":      msg.To,
+		"from":    c.sender,
+		"message": msg.Body,
+	}
+	header := http.Header{}
+	if c.apiKey != "" {
+		header.Set("Authorization", "Bearer "+c.apiKey)
+	}
+	return postJSON(ctx, c.client, c.url, header, payload)
+}
@@ -0,0 +1,159 @@
+package notification
+
+import (
+	"fmt"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+)
+
+// Supported message languages
+const (
+	LanguageIndonesian = "id"
+	LanguageEnglish    = "en"
+)
+
+// TemplateData is what message templates can refer to
+type TemplateData struct {
+	LaundryName     string
+	CustomerName    string
+	TransactionCode string
+	Status          models.TransactionStatus
+	TotalPrice      float64
+	IsPaid          bool
+	PickupDate      time.Time // zero when no pick-up date was set
+}
+
+// messageTemplate is the subject and body of one message in one language
+type messageTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+// templateSources lists the messages per language and the status that triggers them
+var templateSources = map[string]map[models.TransactionStatus][2]string{
+	LanguageIndonesian: {
+		models.StatusQueued: {
+			"Pesanan {{.TransactionCode}} diterima",
+			"Halo {{.CustomerName}}, cucian Anda dengan kode {{.TransactionCode}} sudah kami terima. " +
+				"Total {{rupiah .TotalPrice}}{{if .IsPaid}} (lunas){{end}}." +
+				"{{if not .PickupDate.IsZero}} Perkiraan siap diambil {{date .PickupDate}}.{{end}}\n\n{{.LaundryName}}",
+		},
+		models.StatusReadytoPickup: {
+			"Cucian {{.TransactionCode}} siap diambil",
+			"Halo {{.CustomerName}}, cucian Anda dengan kode {{.TransactionCode}} sudah siap diambil." +
+				"{{if not .IsPaid}} Total yang perlu dibayar {{rupiah .TotalPrice}}.{{end}} Terima kasih!\n\n{{.LaundryName}}",
+		},
+		models.StatusCompleted: {
+			"Pesanan {{.TransactionCode}} selesai",
+			"Halo {{.CustomerName}}, pesanan {{.TransactionCode}} telah selesai. " +
+				"Terima kasih telah mempercayakan cucian Anda kepada kami.\n\n{{.LaundryName}}",
+		},
+	},
+	LanguageEnglish: {
+		models.StatusQueued: {
+			"Order {{.TransactionCode}} received",
+			"Hi {{.CustomerName}}, we have received your laundry with code {{.TransactionCode}}. " +
+				"Total {{rupiah .TotalPrice}}{{if .IsPaid}} (paid){{end}}." +
+				"{{if not .PickupDate.IsZero}} Expected ready for pick-up on {{date .PickupDate}}.{{end}}\n\n{{.LaundryName}}",
+		},
+		models.StatusReadytoPickup: {
+			"Laundry {{.TransactionCode}} is ready for pick-up",
+			"Hi {{.CustomerName}}, your laundry with code {{.TransactionCode}} is ready for pick-up." +
+				"{{if not .IsPaid}} Amount due {{rupiah .TotalPrice}}.{{end}} Thank you!\n\n{{.LaundryName}}",
+		},
+		models.StatusCompleted: {
+			"Order {{.TransactionCode}} completed",
+			"Hi {{.CustomerName}}, order {{.TransactionCode}} is completed. " +
+				"Thank you for trusting us with your laundry.\n\n{{.LaundryName}}",
+		},
+	},
+}
+
+// templates holds the parsed templateSources
+var templates = parseTemplates()
+
+// indonesianMonths are the month names used in Indonesian dates
+var indonesianMonths = [...]string{
+	"Januari", "Februari", "Maret", "April", "Mei", "Juni",
+	"Juli", "Agustus", "September", "Oktober", "November", "Desember",
+}
+
+// parseTemplates parses every template with the functions of its language
+func parseTemplates() map[string]map[models.TransactionStatus]messageTemplate {
+	parsed := make(map[string]map[models.TransactionStatus]messageTemplate, len(templateSources))
+	for lang, sources := range templateSources {
+		funcs := template.FuncMap{"rupiah": Rupiah, "date": dateFunc(lang)}
+		parsed[lang] = make(map[models.TransactionStatus]messageTemplate, len(sources))
+		for status, source := range sources {
+			name := lang + "/" + string(status)
+			parsed[lang][status] = messageTemplate{
+				subject: template.Must(template.New(name + "/subject").Funcs(funcs).Parse(source[0])),
+				body:    template.Must(template.New(name + "/body").Funcs(funcs).Parse(source[1])),
+			}
+		}
+	}
+	return parsed
+}
+
+// IsLanguage reports whether messages can be rendered in lang
+func IsLanguage(lang string) bool {
+	_, ok := templates[lang]
+	return ok
+}
+
+// HasTemplate reports whether customers are sent a message when a transaction reaches status
+func HasTemplate(status models.TransactionStatus) bool {
+	_, ok := templates[LanguageIndonesian][status]
+	return ok
+}
+
+// Render renders the subject and body of the message for status in lang
+func Render(lang string, status models.TransactionStatus, data TemplateData) (subject, body string, err error) {
+	tmpl, ok := templates[lang][status]
+	if !ok {
+		return "", "", fmt.Errorf("no %s template for status %q", lang, status)
+	}
+
+	var b strings.Builder
+	if err := tmpl.subject.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	subject = b.String()
+
+	b.Reset()
+	if err := tmpl.body.Execute(&b, data); err != nil {
+		return "", "", err
+	}
+	return subject, strings.TrimSpace(b.String()), nil
+}
+
+// Rupiah formats an amount as rupiah with dots between thousands, such as Rp 25.000
+func Rupiah(amount float64) string {
+	digits := fmt.Sprintf("%.0f", amount)
+	sign := ""
+	if strings.HasPrefix(digits, "-") {
+		sign, digits = "-", digits[1:]
+	}
+
+	var b strings.Builder
+	for i, r := range digits {
+		if i > 0 && (len(digits)-i)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(r)
+	}
+	return sign + "Rp " + b.String()
+}
+
+// dateFunc returns the template function formatting dates in lang
+func dateFunc(lang string) func(time.Time) string {
+	if lang == LanguageIndonesian {
+		return func(t time.Time) string {
+			return fmt.Sprintf("%d %s %d", t.Day(), indonesianMonths[t.Month()-1], t.Year())
+		}
+	}
+	return func(t time.Time) string { return t.Format("2 January 2006") }
+}
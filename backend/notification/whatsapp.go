@@ -0,0 +1,52 @@
+package notification
+
+import (
+	"context"
+	"net/http"
+	"strings"
+)
+
+// DefaultWhatsAppAPIURL is the WhatsApp Business Cloud API base URL
+const DefaultWhatsAppAPIURL = "https://graph.facebook.com/v21.0"
+
+// WhatsAppChannel sends text messages through the WhatsApp Business Cloud API
+type WhatsAppChannel struct {
+	client        *http.Client
+	apiURL        string
+	phoneNumberID string
+	accessToken   string
+}
+
+// NewWhatsAppChannel creates a channel sending from the business phone number phoneNumberID
+func NewWhatsAppChannel(client *http.Client, apiURL, phoneNumberID, accessToken string) *WhatsAppChannel {
+	if apiURL == "" {
+		apiURL = DefaultWhatsAppAPIURL
+	}
+	return &WhatsAppChannel{
+		client:        client,
+		apiURL:        strings.TrimRight(apiURL, "/"),
+		phoneNumberID: phoneNumberID,
+		accessToken:   accessToken,
+	}
+}
+
+// Name identifies the channel in the outbox
+func (c *WhatsAppChannel) Name() string { return ChannelWhatsApp }
+
+// Recipient returns the phone number of the contact in international form
+func (c *WhatsAppChannel) Recipient(contact Contact) string {
+	return InternationalPhone(contact.Phone)
+}
+
+// Send delivers the message body as a WhatsApp text message
+func (c *WhatsAppChannel) Send(ctx context.Context, msg Message) error {
+	payload := map[string]any{
+		"messaging_product": "whatsapp",
+		"recipient_type":    "individual",
+		"to":                msg.To,
+		"type":              "text",
+		"text":              map[string]any{"body": msg.Body},
+	}
+	header := http.Header{"Authorization": {"Bearer " + c.accessToken}}
+	return postJSON(ctx, c.client, c.apiURL+"/"+c.phoneNumberID+"/messages", header, payload)
+}
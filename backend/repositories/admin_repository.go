@@ -1,6 +1,8 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"gorm.io/gorm"
 )
@@ -15,10 +17,15 @@ func NewAdminRepository(db *gorm.DB) *AdminRepository {
 	return &AdminRepository{db: db}
 }
 
+// CreateAdmin creates a new admin
+func (r *AdminRepository) CreateAdmin(ctx context.Context, admin *models.Admin) error {
+	return conn(ctx, r.db).Create(admin).Error
+}
+
 // GetAdminByID retrieves an admin by ID
-func (r *AdminRepository) GetAdminByID(id uint) (*models.Admin, error) {
+func (r *AdminRepository) GetAdminByID(ctx context.Context, id uint) (*models.Admin, error) {
 	var admin models.Admin
-	err := r.db.Where("id = ?", id).First(&admin).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&admin).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -26,9 +33,9 @@ func (r *AdminRepository) GetAdminByID(id uint) (*models.Admin, error) {
 }
 
 // GetAdminByUsername retrieves an admin by username
-func (r *AdminRepository) GetAdminByUsername(username string) (*models.Admin, error) {
+func (r *AdminRepository) GetAdminByUsername(ctx context.Context, username string) (*models.Admin, error) {
 	var admin models.Admin
-	err := r.db.Where("username = ?", username).First(&admin).Error
+	err := conn(ctx, r.db).Where("username = ?", username).First(&admin).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -36,23 +43,23 @@ func (r *AdminRepository) GetAdminByUsername(username string) (*models.Admin, er
 }
 
 // UpdateAdmin updates an admin
-func (r *AdminRepository) UpdateAdmin(admin *models.Admin) error {
-	return r.db.Save(admin).Error
+func (r *AdminRepository) UpdateAdmin(ctx context.Context, admin *models.Admin) error {
+	return conn(ctx, r.db).Save(admin).Error
 }
 
 // DeleteAdmin deletes an admin
-func (r *AdminRepository) DeleteAdmin(id uint) error {
-	return r.db.Delete(&models.Admin{}, id).Error
+func (r *AdminRepository) DeleteAdmin(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&models.Admin{}, id).Error
 }
 
 // GetAllAdmins retrieves all admins
-func (r *AdminRepository) GetAllAdmins(limit, offset int) ([]models.Admin, int64, error) {
+func (r *AdminRepository) GetAllAdmins(ctx context.Context, limit, offset int) ([]models.Admin, int64, error) {
 	var admins []models.Admin
 	var total int64
-	err := r.db.Model(&models.Admin{}).Count(&total).Error
+	err := conn(ctx, r.db).Model(&models.Admin{}).Count(&total).Error
 	if err != nil {
 		return nil, 0, err
 	}
-	err = r.db.Limit(limit).Offset(offset).Find(&admins).Error
+	err = conn(ctx, r.db).Limit(limit).Offset(offset).Find(&admins).Error
 	return admins, total, err
 }
@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+)
+
+// AuditFilter narrows a search of the audit log
+type AuditFilter struct {
+	EntityType string
+	EntityID   uint
+	ActorID    uint
+	Action     models.AuditAction
+	Field      string // only entries that changed this field
+	RequestID  string
+	Query      string     // prefix of the entity label or the actor name
+	From       *time.Time // at or after
+	To         *time.Time // before
+	Limit      int
+	Offset     int
+}
+
+// AuditRepository handles the audit log
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+// NewAuditRepository creates a new audit repository
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// CreateEntries adds entries to the audit log, inside the database transaction in ctx if there is one
+func (r *AuditRepository) CreateEntries(ctx context.Context, entries []models.AuditLog) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&entries).Error
+}
+
+// ListEntries retrieves the entries matching the filter, latest first, and their total count
+func (r *AuditRepository) ListEntries(ctx context.Context, filter AuditFilter) ([]models.AuditLog, int64, error) {
+	query := conn(ctx, r.db).Model(&models.AuditLog{})
+	if filter.EntityType != "" {
+		query = query.Where("entity_type = ?", filter.EntityType)
+	}
+	if filter.EntityID != 0 {
+		query = query.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.ActorID != 0 {
+		query = query.Where("actor_id = ?", filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.Field != "" {
+		query = query.Where("JSON_CONTAINS_PATH(changes, 'one', ?)", `$."`+filter.Field+`"`)
+	}
+	if filter.RequestID != "" {
+		query = query.Where("request_id = ?", filter.RequestID)
+	}
+	if filter.Query != "" {
+		prefix := escapeLike(filter.Query) + "%"
+		query = query.Where("entity_label LIKE ? OR actor_name LIKE ?", prefix, prefix)
+	}
+	if filter.From != nil {
+		query = query.Where("created_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("created_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var entries []models.AuditLog
+	err := query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&entries).Error
+	return entries, total, err
+}
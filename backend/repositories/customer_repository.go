@@ -0,0 +1,96 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CustomerRepository handles customer profiles and their login codes
+type CustomerRepository struct {
+	db *gorm.DB
+}
+
+// NewCustomerRepository creates a new customer repository
+func NewCustomerRepository(db *gorm.DB) *CustomerRepository {
+	return &CustomerRepository{db: db}
+}
+
+// GetCustomerByPhone retrieves the profile of a customer by phone number in international form
+func (r *CustomerRepository) GetCustomerByPhone(ctx context.Context, phone string) (*models.Customer, error) {
+	var customer models.Customer
+	err := conn(ctx, r.db).Where("phone = ?", phone).First(&customer).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &customer, err
+}
+
+// SaveCustomer creates or updates the profile of a customer
+func (r *CustomerRepository) SaveCustomer(ctx context.Context, customer *models.Customer) error {
+	return conn(ctx, r.db).Save(customer).Error
+}
+
+// ReplaceLoginCode stores a new login code for a phone number in place of its previous one,
+// which stops working. code is reloaded, so its ID is that of the stored row.
+func (r *CustomerRepository) ReplaceLoginCode(ctx context.Context, code *models.CustomerLoginCode) error {
+	if code.CreatedAt.IsZero() {
+		code.CreatedAt = time.Now()
+	}
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.OnConflict{
+			Columns: []clause.Column{{Name: "phone"}},
+			DoUpdates: clause.Assignments(map[string]any{
+				"code_hash":  code.CodeHash,
+				"attempts":   0,
+				"expires_at": code.ExpiresAt,
+				"used_at":    nil,
+				"created_at": code.CreatedAt,
+			}),
+		}).Create(code).Error
+		if err != nil {
+			return err
+		}
+		// On an update MySQL does not report the ID of the existing row
+		var stored models.CustomerLoginCode
+		if err := tx.Where("phone = ?", code.Phone).First(&stored).Error; err != nil {
+			return err
+		}
+		*code = stored
+		return nil
+	})
+}
+
+// GetLoginCode retrieves the current login code of a phone number
+func (r *CustomerRepository) GetLoginCode(ctx context.Context, phone string) (*models.CustomerLoginCode, error) {
+	var code models.CustomerLoginCode
+	err := conn(ctx, r.db).Where("phone = ?", phone).Order("id DESC").First(&code).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &code, err
+}
+
+// DeleteLoginCode deletes a login code
+func (r *CustomerRepository) DeleteLoginCode(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Delete(&models.CustomerLoginCode{}, id).Error
+}
+
+// CountLoginCodeAttempt counts an attempt to use a login code, reporting false when it had
+// maxAttempts already. Counting before checking keeps concurrent guesses within the limit.
+func (r *CustomerRepository) CountLoginCodeAttempt(ctx context.Context, id uint, maxAttempts int) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.CustomerLoginCode{}).Where("id = ? AND attempts < ?", id, maxAttempts).
+		Update("attempts", gorm.Expr("attempts + 1"))
+	return result.RowsAffected == 1, result.Error
+}
+
+// UseLoginCode marks a login code as used, reporting false when it already was, so a code
+// only ever logs in once
+func (r *CustomerRepository) UseLoginCode(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.CustomerLoginCode{}).Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", now)
+	return result.RowsAffected == 1, result.Error
+}
@@ -0,0 +1,47 @@
+package repositories
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// TestLoginCodeConditions checks the conditions that make the database enforce the login code
+// rules, so concurrent requests cannot get around them
+func TestLoginCodeConditions(t *testing.T) {
+	ctx := context.Background()
+	tests := []struct {
+		name string
+		run  func(*CustomerRepository) error
+		want string
+	}{
+		{
+			name: "attempt counted below the limit only",
+			run: func(r *CustomerRepository) error {
+				_, err := r.CountLoginCodeAttempt(ctx, 3, 5)
+				return err
+			},
+			want: "UPDATE `customer_login_codes` SET `attempts`=attempts + 1 WHERE id = ? AND attempts < ?",
+		},
+		{
+			name: "code used once only",
+			run: func(r *CustomerRepository) error {
+				_, err := r.UseLoginCode(ctx, 3, time.Now())
+				return err
+			},
+			want: "UPDATE `customer_login_codes` SET `used_at`=? WHERE id = ? AND used_at IS NULL",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			db := dryRunDB(t)
+			statements := captureSQL(t, db)
+			if err := tt.run(NewCustomerRepository(db)); err != nil {
+				t.Fatal(err)
+			}
+			if len(*statements) != 1 || (*statements)[0] != tt.want {
+				t.Errorf("got %q, want %q", *statements, tt.want)
+			}
+		})
+	}
+}
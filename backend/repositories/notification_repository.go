@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// NotificationFilter narrows a listing of the notification outbox
+type NotificationFilter struct {
+	Status        models.NotificationStatus
+	TransactionID uint
+	Limit         int
+	Offset        int
+}
+
+// NotificationRepository handles the notification outbox
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+// NewNotificationRepository creates a new notification repository
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// CreateNotifications queues notifications in the outbox. A notification for an event and
+// channel that already has one is ignored.
+func (r *NotificationRepository) CreateNotifications(ctx context.Context, notifications []models.Notification) error {
+	if len(notifications) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(&notifications).Error
+}
+
+// ClaimDue returns up to limit pending notifications due at now and hides them from other
+// claims for lease, counting the attempt. Rows claimed by another worker are skipped.
+func (r *NotificationRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.Notification, error) {
+	var claimed []models.Notification
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.NotificationPending, now).
+			Order("next_attempt_at ASC").Limit(limit).Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(claimed))
+		for i := range claimed {
+			ids[i] = claimed[i].ID
+			claimed[i].Attempts++
+		}
+		return tx.Model(&models.Notification{}).Where("id IN ?", ids).Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	return claimed, err
+}
+
+// MarkSent records the delivery of a notification
+func (r *NotificationRepository) MarkSent(ctx context.Context, id uint, sentAt time.Time) error {
+	return conn(ctx, r.db).Model(&models.Notification{}).Where("id = ?", id).Updates(map[string]any{
+		"status":     models.NotificationSent,
+		"sent_at":    sentAt,
+		"last_error": "",
+	}).Error
+}
+
+// MarkRetry records a failed attempt and schedules the next one
+func (r *NotificationRepository) MarkRetry(ctx context.Context, id uint, nextAttemptAt time.Time, lastError string) error {
+	return conn(ctx, r.db).Model(&models.Notification{}).Where("id = ?", id).Updates(map[string]any{
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	}).Error
+}
+
+// MarkFailed records the last failed attempt of a notification
+func (r *NotificationRepository) MarkFailed(ctx context.Context, id uint, lastError string) error {
+	return conn(ctx, r.db).Model(&models.Notification{}).Where("id = ?", id).Updates(map[string]any{
+		"status":     models.NotificationFailed,
+		"last_error": lastError,
+	}).Error
+}
+
+// Requeue makes a failed notification pending again with fresh attempts.
+// It reports false when the notification does not exist or has not failed.
+func (r *NotificationRepository) Requeue(ctx context.Context, id uint, now time.Time) (bool, error) {
+	result := conn(ctx, r.db).Model(&models.Notification{}).
+		Where("id = ? AND status = ?", id, models.NotificationFailed).
+		Updates(map[string]any{
+			"status":          models.NotificationPending,
+			"attempts":        0,
+			"next_attempt_at": now,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// GetNotificationByID retrieves a notification by ID
+func (r *NotificationRepository) GetNotificationByID(ctx context.Context, id uint) (*models.Notification, error) {
+	var notification models.Notification
+	err := conn(ctx, r.db).Where("id = ?", id).First(&notification).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &notification, nil
+}
+
+// ListNotifications retrieves the notifications matching the filter, latest first, and their total count
+func (r *NotificationRepository) ListNotifications(ctx context.Context, filter NotificationFilter) ([]models.Notification, int64, error) {
+	query := conn(ctx, r.db).Model(&models.Notification{})
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.TransactionID != 0 {
+		query = query.Where("transaction_id = ?", filter.TransactionID)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var notifications []models.Notification
+	err := query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&notifications).Error
+	return notifications, total, err
+}
@@ -0,0 +1,127 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// OutboxFilter selects outbox events in ID order
+type OutboxFilter struct {
+	AfterID         uint
+	UpToID          uint     // no upper bound when 0
+	Names           []string // all events when empty
+	TransactionCode string   // only events whose transaction has this code
+	Limit           int
+}
+
+// OutboxRepository handles the outbox of domain events
+type OutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewOutboxRepository creates a new outbox repository
+func NewOutboxRepository(db *gorm.DB) *OutboxRepository {
+	return &OutboxRepository{db: db}
+}
+
+// Append stores events in the outbox, inside the database transaction in ctx if there is one
+func (r *OutboxRepository) Append(ctx context.Context, events []models.OutboxEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Create(&events).Error
+}
+
+// ClaimDue returns up to limit pending events due at now, oldest first, and hides them from
+// other claims for lease, counting the attempt. Rows claimed by another dispatcher are skipped.
+func (r *OutboxRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.OutboxEvent, error) {
+	var claimed []models.OutboxEvent
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.OutboxPending, now).
+			Order("next_attempt_at ASC, id ASC").Limit(limit).Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(claimed))
+		for i := range claimed {
+			ids[i] = claimed[i].ID
+			claimed[i].Attempts++
+		}
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	return claimed, err
+}
+
+// MarkDispatched records that every subscriber has handled an event
+func (r *OutboxRepository) MarkDispatched(ctx context.Context, id uint, handled []string, dispatchedAt time.Time) error {
+	return conn(ctx, r.db).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"status":        models.OutboxDispatched,
+		"handled":       datatypes.JSONSlice[string](handled),
+		"dispatched_at": dispatchedAt,
+		"last_error":    "",
+	}).Error
+}
+
+// MarkRetry records a partly failed dispatch and schedules the next attempt
+func (r *OutboxRepository) MarkRetry(ctx context.Context, id uint, handled []string, nextAttemptAt time.Time, lastError string) error {
+	return conn(ctx, r.db).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"handled":         datatypes.JSONSlice[string](handled),
+		"next_attempt_at": nextAttemptAt,
+		"last_error":      lastError,
+	}).Error
+}
+
+// MarkFailed records the last failed dispatch of an event
+func (r *OutboxRepository) MarkFailed(ctx context.Context, id uint, handled []string, lastError string) error {
+	return conn(ctx, r.db).Model(&models.OutboxEvent{}).Where("id = ?", id).Updates(map[string]any{
+		"status":     models.OutboxFailed,
+		"handled":    datatypes.JSONSlice[string](handled),
+		"last_error": lastError,
+	}).Error
+}
+
+// DeleteFinished deletes up to limit dispatched or failed events that occurred before cutoff
+// and returns how many were deleted
+func (r *OutboxRepository) DeleteFinished(ctx context.Context, cutoff time.Time, limit int) (int64, error) {
+	result := conn(ctx, r.db).
+		Where("status IN ? AND occurred_at < ?", []models.OutboxStatus{models.OutboxDispatched, models.OutboxFailed}, cutoff).
+		Limit(limit).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// LatestID returns the ID of the latest outbox event, or 0 when there is none
+func (r *OutboxRepository) LatestID(ctx context.Context) (uint, error) {
+	var id uint
+	err := conn(ctx, r.db).Model(&models.OutboxEvent{}).Select("COALESCE(MAX(id), 0)").Scan(&id).Error
+	return id, err
+}
+
+// ListEvents retrieves the events matching the filter, whatever their dispatch state, in ID order
+func (r *OutboxRepository) ListEvents(ctx context.Context, filter OutboxFilter) ([]models.OutboxEvent, error) {
+	query := conn(ctx, r.db).Where("id > ?", filter.AfterID)
+	if filter.UpToID > 0 {
+		query = query.Where("id <= ?", filter.UpToID)
+	}
+	if len(filter.Names) > 0 {
+		query = query.Where("name IN ?", filter.Names)
+	}
+	if filter.TransactionCode != "" {
+		query = query.Where("transaction_code = ?", filter.TransactionCode)
+	}
+
+	var events []models.OutboxEvent
+	err := query.Order("id ASC").Limit(filter.Limit).Find(&events).Error
+	return events, err
+}
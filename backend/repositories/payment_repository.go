@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrAlreadyPaid is returned when recording a payment for a transaction that is already paid
+var ErrAlreadyPaid = errors.New("transaction is already paid")
+
+// PaymentRepository handles payment database operations
+type PaymentRepository struct {
+	db *gorm.DB
+}
+
+// NewPaymentRepository creates a new payment repository
+func NewPaymentRepository(db *gorm.DB) *PaymentRepository {
+	return &PaymentRepository{db: db}
+}
+
+// RecordPayment stores a payment and marks its transaction as paid, atomically. It fails with
+// ErrAlreadyPaid when the transaction was paid meanwhile and with ErrShiftClosed when the
+// payment's shift was closed meanwhile.
+func (r *PaymentRepository) RecordPayment(ctx context.Context, payment *models.Payment) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if payment.ShiftID != nil {
+			// Share the row lock CloseShift takes, so a shift never closes under a new payment
+			var shift models.Shift
+			if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).Where("id = ?", *payment.ShiftID).First(&shift).Error; err != nil {
+				return err
+			}
+			if shift.IsClosed() {
+				return ErrShiftClosed
+			}
+		}
+
+		result := tx.Model(&models.Transaction{}).
+			Where("id = ? AND is_paid = ?", payment.TransactionID, false).
+			Update("is_paid", true)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return ErrAlreadyPaid
+		}
+		return tx.Create(payment).Error
+	})
+}
+
+// GetPaymentsByTransactionID retrieves the payments of a transaction
+func (r *PaymentRepository) GetPaymentsByTransactionID(ctx context.Context, transactionID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+	err := conn(ctx, r.db).Where("transaction_id = ?", transactionID).
+		Order("paid_at ASC").Find(&payments).Error
+	return payments, err
+}
+
+// VoidPayments deletes the payments of a transaction and marks it unpaid, atomically.
+// It fails with ErrShiftClosed, changing nothing, when one of them belongs to a closed shift.
+func (r *PaymentRepository) VoidPayments(ctx context.Context, transactionID uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// Lock the shifts of the payments so none of them closes while they are voided
+		var shifts []models.Shift
+		if err := tx.Clauses(clause.Locking{Strength: "SHARE"}).
+			Where("id IN (?)", tx.Model(&models.Payment{}).Select("shift_id").Where("transaction_id = ?", transactionID)).
+			Find(&shifts).Error; err != nil {
+			return err
+		}
+		for _, shift := range shifts {
+			if shift.IsClosed() {
+				return ErrShiftClosed
+			}
+		}
+
+		if err := tx.Where("transaction_id = ?", transactionID).Delete(&models.Payment{}).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.Transaction{}).Where("id = ?", transactionID).Update("is_paid", false).Error
+	})
+}
@@ -0,0 +1,180 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+)
+
+// Report periods revenue can be grouped by
+const (
+	ReportIntervalDay   = "day"
+	ReportIntervalWeek  = "week"
+	ReportIntervalMonth = "month"
+)
+
+// ReportFilter selects the transactions a report covers, by creation time
+type ReportFilter struct {
+	From *time.Time // inclusive
+	To   *time.Time // exclusive
+}
+
+// apply restricts query to the live transactions, aliased t, created within the range
+func (f ReportFilter) apply(query *gorm.DB) *gorm.DB {
+	query = query.Where("t.deleted_at IS NULL")
+	if f.From != nil {
+		query = query.Where("t.created_at >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where("t.created_at < ?", *f.To)
+	}
+	return query
+}
+
+// RevenueTotals sums the orders of a group of transactions.
+// Gross counts every order, paid only the orders marked as paid.
+type RevenueTotals struct {
+	Orders int64   `json:"orders"`
+	Gross  float64 `json:"gross"`
+	Paid   float64 `json:"paid"`
+}
+
+// PeriodRevenue is the revenue of one day, week or month
+type PeriodRevenue struct {
+	Period string `json:"period"` // first day of the period, YYYY-MM-DD
+	RevenueTotals
+}
+
+// ServiceRevenue is the revenue of one service type, or of one item of it
+type ServiceRevenue struct {
+	ServiceType string  `json:"service_type"`
+	ItemName    string  `json:"item_name,omitempty"`
+	Quantity    int64   `json:"quantity"`
+	Orders      int64   `json:"orders"`
+	Gross       float64 `json:"gross"`
+	Paid        float64 `json:"paid"`
+}
+
+// AdminRevenue is the revenue of the orders taken by one admin
+type AdminRevenue struct {
+	AdminID  uint   `json:"admin_id"`
+	Username string `json:"username"`
+	RevenueTotals
+}
+
+// TurnaroundTimes are the milestones of one transaction, nil when not reached
+type TurnaroundTimes struct {
+	TransactionID uint
+	CreatedAt     time.Time
+	ReadyAt       *time.Time
+	PickedUpAt    *time.Time
+}
+
+// UnpaidAge sums the unpaid orders that are a given number of days old
+type UnpaidAge struct {
+	AgeDays int
+	Orders  int64
+	Amount  float64
+}
+
+// ReportRepository runs the aggregate queries behind the reports
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+// NewReportRepository creates a new report repository
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// revenueColumns selects RevenueTotals over transactions aliased t
+const revenueColumns = "COUNT(*) AS orders, COALESCE(SUM(t.total_price), 0) AS gross, " +
+	"COALESCE(SUM(CASE WHEN t.is_paid THEN t.total_price ELSE 0 END), 0) AS paid"
+
+// GetRevenueTotals sums every transaction in the range
+func (r *ReportRepository) GetRevenueTotals(ctx context.Context, filter ReportFilter) (*RevenueTotals, error) {
+	var totals RevenueTotals
+	err := filter.apply(conn(ctx, r.db).Table("transactions AS t")).
+		Select(revenueColumns).Scan(&totals).Error
+	return &totals, err
+}
+
+// GetRevenueByPeriod sums the transactions per period. Creation times, stored in the database
+// offset dbOffset, are shifted to reportOffset (both formatted as +07:00) before grouping.
+func (r *ReportRepository) GetRevenueByPeriod(ctx context.Context, filter ReportFilter, interval, dbOffset, reportOffset string) ([]PeriodRevenue, error) {
+	local := "CONVERT_TZ(t.created_at, '" + dbOffset + "', '" + reportOffset + "')"
+	var period string
+	switch interval {
+	case ReportIntervalWeek:
+		// Weeks start on Monday
+		period = "DATE_FORMAT(DATE_SUB(" + local + ", INTERVAL WEEKDAY(" + local + ") DAY), '%Y-%m-%d')"
+	case ReportIntervalMonth:
+		period = "DATE_FORMAT(" + local + ", '%Y-%m-01')"
+	default:
+		period = "DATE_FORMAT(" + local + ", '%Y-%m-%d')"
+	}
+
+	var rows []PeriodRevenue
+	err := filter.apply(conn(ctx, r.db).Table("transactions AS t")).
+		Select(period + " AS period, " + revenueColumns).
+		Group("period").Order("period").Scan(&rows).Error
+	return rows, err
+}
+
+// GetRevenueByService sums the items per service type, and per item name too when byItem is set
+func (r *ReportRepository) GetRevenueByService(ctx context.Context, filter ReportFilter, byItem bool) ([]ServiceRevenue, error) {
+	columns := "ti.service_type, COALESCE(SUM(ti.quantity), 0) AS quantity, COUNT(DISTINCT t.id) AS orders, " +
+		"COALESCE(SUM(ti.subtotal), 0) AS gross, COALESCE(SUM(CASE WHEN t.is_paid THEN ti.subtotal ELSE 0 END), 0) AS paid"
+	group := "ti.service_type"
+	if byItem {
+		columns += ", ti.item_name"
+		group += ", ti.item_name"
+	}
+
+	var rows []ServiceRevenue
+	err := filter.apply(conn(ctx, r.db).Table("transaction_items AS ti")).
+		Joins("JOIN transactions t ON t.id = ti.transaction_id").
+		Where("ti.deleted_at IS NULL").
+		Select(columns).Group(group).Order("gross DESC").Scan(&rows).Error
+	return rows, err
+}
+
+// GetRevenueByAdmin sums the transactions per admin who took the order
+func (r *ReportRepository) GetRevenueByAdmin(ctx context.Context, filter ReportFilter) ([]AdminRevenue, error) {
+	var rows []AdminRevenue
+	err := filter.apply(conn(ctx, r.db).Table("transactions AS t")).
+		Joins("LEFT JOIN admins a ON a.id = t.admin_id").
+		Select("t.admin_id, COALESCE(a.username, '') AS username, " + revenueColumns).
+		Group("t.admin_id, a.username").Order("gross DESC").Scan(&rows).Error
+	return rows, err
+}
+
+// GetTurnaroundTimes returns when each transaction in the range became ready and was picked up,
+// from the status history. Cancellations, which complete without being ready, are not pick-ups.
+func (r *ReportRepository) GetTurnaroundTimes(ctx context.Context, filter ReportFilter) ([]TurnaroundTimes, error) {
+	var rows []TurnaroundTimes
+	err := filter.apply(conn(ctx, r.db).Table("transactions AS t")).
+		Joins("JOIN transaction_history h ON h.transaction_id = t.id").
+		Select(
+			"t.id AS transaction_id, t.created_at, "+
+				"MIN(CASE WHEN h.new_status = ? THEN h.created_at END) AS ready_at, "+
+				"MIN(CASE WHEN h.new_status = ? AND h.previous_status = ? THEN h.created_at END) AS picked_up_at",
+			models.StatusReadytoPickup, models.StatusCompleted, models.StatusReadytoPickup,
+		).
+		Group("t.id, t.created_at").
+		Having("ready_at IS NOT NULL OR picked_up_at IS NOT NULL").
+		Scan(&rows).Error
+	return rows, err
+}
+
+// GetUnpaidByAge sums the unpaid transactions in the range by their age in whole days at now
+func (r *ReportRepository) GetUnpaidByAge(ctx context.Context, filter ReportFilter, now time.Time) ([]UnpaidAge, error) {
+	var rows []UnpaidAge
+	err := filter.apply(conn(ctx, r.db).Table("transactions AS t")).
+		Where("t.is_paid = ?", false).
+		Select("TIMESTAMPDIFF(DAY, t.created_at, ?) AS age_days, COUNT(*) AS orders, COALESCE(SUM(t.total_price), 0) AS amount", now).
+		Group("age_days").Order("age_days").Scan(&rows).Error
+	return rows, err
+}
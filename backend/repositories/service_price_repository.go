@@ -1,6 +1,9 @@
 package repositories
 
 import (
+	"context"
+	"time"
+
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"gorm.io/gorm"
 )
@@ -16,14 +19,14 @@ func NewServicePriceRepository(db *gorm.DB) *ServicePriceRepository {
 }
 
 // CreateServicePrice creates a new service price
-func (r *ServicePriceRepository) CreateServicePrice(servicePrice *models.ServicePrice) error {
-	return r.db.Create(servicePrice).Error
+func (r *ServicePriceRepository) CreateServicePrice(ctx context.Context, servicePrice *models.ServicePrice) error {
+	return conn(ctx, r.db).Create(servicePrice).Error
 }
 
 // GetServicePriceByID retrieves a service price by ID
-func (r *ServicePriceRepository) GetServicePriceByID(id uint) (*models.ServicePrice, error) {
+func (r *ServicePriceRepository) GetServicePriceByID(ctx context.Context, id uint) (*models.ServicePrice, error) {
 	var servicePrice models.ServicePrice
-	err := r.db.Where("id = ?", id).First(&servicePrice).Error
+	err := conn(ctx, r.db).Where("id = ?", id).First(&servicePrice).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
 	}
@@ -31,9 +34,20 @@ func (r *ServicePriceRepository) GetServicePriceByID(id uint) (*models.ServicePr
 }
 
 // GetServicePriceByTypeAndItem retrieves a service price by service type and item name
-func (r *ServicePriceRepository) GetServicePriceByTypeAndItem(serviceType, itemName string) (*models.ServicePrice, error) {
+func (r *ServicePriceRepository) GetServicePriceByTypeAndItem(ctx context.Context, serviceType, itemName string) (*models.ServicePrice, error) {
+	var servicePrice models.ServicePrice
+	err := conn(ctx, r.db).Where("service_type = ? AND item_name = ? AND is_active = ?", serviceType, itemName, true).
+		First(&servicePrice).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &servicePrice, err
+}
+
+// FindServicePriceByTypeAndItem retrieves a service price by service type and item name, active or not
+func (r *ServicePriceRepository) FindServicePriceByTypeAndItem(ctx context.Context, serviceType, itemName string) (*models.ServicePrice, error) {
 	var servicePrice models.ServicePrice
-	err := r.db.Where("service_type = ? AND item_name = ? AND is_active = ?", serviceType, itemName, true).
+	err := conn(ctx, r.db).Where("service_type = ? AND item_name = ?", serviceType, itemName).
 		First(&servicePrice).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -42,27 +56,57 @@ func (r *ServicePriceRepository) GetServicePriceByTypeAndItem(serviceType, itemN
 }
 
 // GetAllServicePrices retrieves all active service prices
-func (r *ServicePriceRepository) GetAllServicePrices() ([]models.ServicePrice, error) {
+func (r *ServicePriceRepository) GetAllServicePrices(ctx context.Context) ([]models.ServicePrice, error) {
 	var servicePrices []models.ServicePrice
-	err := r.db.Where("is_active = ?", true).
+	err := conn(ctx, r.db).Where("is_active = ?", true).
 		Order("service_type ASC, item_name ASC").
 		Find(&servicePrices).Error
 	return servicePrices, err
 }
 
+// GetCatalog retrieves every service price, including inactive ones
+func (r *ServicePriceRepository) GetCatalog(ctx context.Context) ([]models.ServicePrice, error) {
+	var servicePrices []models.ServicePrice
+	err := conn(ctx, r.db).Order("service_type ASC, item_name ASC").
+		Find(&servicePrices).Error
+	return servicePrices, err
+}
+
+// ApplyCatalog creates, updates and deactivates service prices in a single database transaction
+func (r *ServicePriceRepository) ApplyCatalog(ctx context.Context, added, changed []models.ServicePrice, deactivatedIDs []uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		for i := range added {
+			if err := tx.Create(&added[i]).Error; err != nil {
+				return err
+			}
+		}
+		for i := range changed {
+			if err := tx.Save(&changed[i]).Error; err != nil {
+				return err
+			}
+		}
+		if len(deactivatedIDs) > 0 {
+			if err := tx.Model(&models.ServicePrice{}).Where("id IN ?", deactivatedIDs).Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
 // GetServicePricesByType retrieves all service prices by service type
-func (r *ServicePriceRepository) GetServicePricesByType(serviceType string) ([]models.ServicePrice, error) {
+func (r *ServicePriceRepository) GetServicePricesByType(ctx context.Context, serviceType string) ([]models.ServicePrice, error) {
 	var servicePrices []models.ServicePrice
-	err := r.db.Where("service_type = ? AND is_active = ?", serviceType, true).
+	err := conn(ctx, r.db).Where("service_type = ? AND is_active = ?", serviceType, true).
 		Order("item_name ASC").
 		Find(&servicePrices).Error
 	return servicePrices, err
 }
 
 // GetServiceTypes retrieves all unique service types
-func (r *ServicePriceRepository) GetServiceTypes() ([]string, error) {
+func (r *ServicePriceRepository) GetServiceTypes(ctx context.Context) ([]string, error) {
 	var serviceTypes []string
-	err := r.db.Model(&models.ServicePrice{}).
+	err := conn(ctx, r.db).Model(&models.ServicePrice{}).
 		Where("is_active = ?", true).
 		Distinct("service_type").
 		Order("service_type ASC").
@@ -71,21 +115,73 @@ func (r *ServicePriceRepository) GetServiceTypes() ([]string, error) {
 }
 
 // UpdateServicePrice updates a service price
-func (r *ServicePriceRepository) UpdateServicePrice(servicePrice *models.ServicePrice) error {
-	return r.db.Save(servicePrice).Error
+func (r *ServicePriceRepository) UpdateServicePrice(ctx context.Context, servicePrice *models.ServicePrice) error {
+	return conn(ctx, r.db).Save(servicePrice).Error
+}
+
+// DeleteServicePrice moves a service price to the trash, recording who deleted it and why
+func (r *ServicePriceRepository) DeleteServicePrice(ctx context.Context, id uint, deletedBy, reason string) error {
+	return conn(ctx, r.db).Model(&models.ServicePrice{}).Where("id = ?", id).Updates(map[string]any{
+		"deleted_at":    time.Now().Truncate(time.Millisecond), // stored as DATETIME(3)
+		"deleted_by":    deletedBy,
+		"delete_reason": reason,
+	}).Error
+}
+
+// ListDeletedServicePrices retrieves a page of the service prices in the trash, most recently deleted first,
+// and the total number in the trash
+func (r *ServicePriceRepository) ListDeletedServicePrices(ctx context.Context, limit, offset int) ([]models.ServicePrice, int64, error) {
+	query := conn(ctx, r.db).Unscoped().Model(&models.ServicePrice{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var servicePrices []models.ServicePrice
+	err := query.Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).Find(&servicePrices).Error
+	return servicePrices, total, err
+}
+
+// GetDeletedServicePrice retrieves a service price in the trash
+func (r *ServicePriceRepository) GetDeletedServicePrice(ctx context.Context, id uint) (*models.ServicePrice, error) {
+	var servicePrice models.ServicePrice
+	err := conn(ctx, r.db).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).First(&servicePrice).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &servicePrice, err
+}
+
+// RestoreServicePrice takes a service price out of the trash
+func (r *ServicePriceRepository) RestoreServicePrice(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Unscoped().Model(&models.ServicePrice{}).Where("id = ?", id).Updates(map[string]any{
+		"deleted_at":    nil,
+		"deleted_by":    "",
+		"delete_reason": "",
+	}).Error
+}
+
+// PurgeServicePrice permanently deletes a service price in the trash
+func (r *ServicePriceRepository) PurgeServicePrice(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&models.ServicePrice{}).Error
 }
 
-// DeleteServicePrice soft deletes a service price
-func (r *ServicePriceRepository) DeleteServicePrice(id uint) error {
-	return r.db.Delete(&models.ServicePrice{}, id).Error
+// ExpiredServicePriceIDs returns up to limit IDs of service prices deleted before cutoff
+func (r *ServicePriceRepository) ExpiredServicePriceIDs(ctx context.Context, cutoff time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := conn(ctx, r.db).Unscoped().Model(&models.ServicePrice{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Order("deleted_at ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
 }
 
 // DeactivateServicePrice deactivates a service price
-func (r *ServicePriceRepository) DeactivateServicePrice(id uint) error {
-	return r.db.Model(&models.ServicePrice{}).Where("id = ?", id).Update("is_active", false).Error
+func (r *ServicePriceRepository) DeactivateServicePrice(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&models.ServicePrice{}).Where("id = ?", id).Update("is_active", false).Error
 }
 
 // ActivateServicePrice activates a service price
-func (r *ServicePriceRepository) ActivateServicePrice(id uint) error {
-	return r.db.Model(&models.ServicePrice{}).Where("id = ?", id).Update("is_active", true).Error
+func (r *ServicePriceRepository) ActivateServicePrice(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&models.ServicePrice{}).Where("id = ?", id).Update("is_active", true).Error
 }
@@ -0,0 +1,167 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/go-sql-driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// ErrShiftClosed is returned when a write targets a shift that has been closed
+var ErrShiftClosed = errors.New("shift is closed")
+
+// ShiftFilter narrows a shift listing. Zero values leave the constraint out.
+type ShiftFilter struct {
+	AdminID uint
+	From    *time.Time // opened at or after
+	To      *time.Time // opened before
+	Limit   int
+	Offset  int
+}
+
+// MethodTotal sums the payments of one method
+type MethodTotal struct {
+	Method models.PaymentMethod `json:"method"`
+	Count  int64                `json:"count"`
+	Amount float64              `json:"amount"`
+}
+
+// ShiftRepository handles cashier shift database operations
+type ShiftRepository struct {
+	db *gorm.DB
+}
+
+// NewShiftRepository creates a new shift repository
+func NewShiftRepository(db *gorm.DB) *ShiftRepository {
+	return &ShiftRepository{db: db}
+}
+
+// CreateShift opens a new shift. Opening a second shift for the same admin fails with a
+// duplicate key error, see IsDuplicateKey.
+func (r *ShiftRepository) CreateShift(ctx context.Context, shift *models.Shift) error {
+	return conn(ctx, r.db).Create(shift).Error
+}
+
+// GetShiftByID retrieves a shift with its admin
+func (r *ShiftRepository) GetShiftByID(ctx context.Context, id uint) (*models.Shift, error) {
+	var shift models.Shift
+	err := conn(ctx, r.db).Preload("Admin").Where("id = ?", id).First(&shift).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &shift, err
+}
+
+// GetOpenShift retrieves the open shift of an admin
+func (r *ShiftRepository) GetOpenShift(ctx context.Context, adminID uint) (*models.Shift, error) {
+	var shift models.Shift
+	err := conn(ctx, r.db).Preload("Admin").
+		Where("admin_id = ? AND closed_at IS NULL", adminID).First(&shift).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &shift, err
+}
+
+// GetShiftAt retrieves the shift of an admin that was, or still is, open at the given time
+func (r *ShiftRepository) GetShiftAt(ctx context.Context, adminID uint, at time.Time) (*models.Shift, error) {
+	var shift models.Shift
+	err := conn(ctx, r.db).
+		Where("admin_id = ? AND opened_at <= ? AND (closed_at IS NULL OR closed_at > ?)", adminID, at, at).
+		Order("opened_at DESC").First(&shift).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &shift, err
+}
+
+// ListShifts retrieves the shifts matching the filter, latest first, and their total count
+func (r *ShiftRepository) ListShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, int64, error) {
+	query := conn(ctx, r.db).Model(&models.Shift{})
+	if filter.AdminID != 0 {
+		query = query.Where("admin_id = ?", filter.AdminID)
+	}
+	if filter.From != nil {
+		query = query.Where("opened_at >= ?", *filter.From)
+	}
+	if filter.To != nil {
+		query = query.Where("opened_at < ?", *filter.To)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var shifts []models.Shift
+	err := query.Preload("Admin").Order("opened_at DESC").Order("id DESC").
+		Limit(filter.Limit).Offset(filter.Offset).Find(&shifts).Error
+	return shifts, total, err
+}
+
+// CloseShift locks the shift, computes its expected cash from the opening cash and the cash
+// payments recorded into it, and stores the closing figures. It returns nil, nil when the
+// shift does not exist and ErrShiftClosed when it was already closed.
+func (r *ShiftRepository) CloseShift(ctx context.Context, id uint, countedCash float64, notes string, closedAt time.Time) (*models.Shift, error) {
+	var shift models.Shift
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		// The row lock makes payments recorded concurrently wait, then see the shift closed
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("id = ?", id).First(&shift).Error; err != nil {
+			return err
+		}
+		if shift.IsClosed() {
+			return ErrShiftClosed
+		}
+
+		var cashTotal float64
+		if err := tx.Model(&models.Payment{}).
+			Where("shift_id = ? AND method = ?", id, models.PaymentCash).
+			Select("COALESCE(SUM(amount), 0)").Scan(&cashTotal).Error; err != nil {
+			return err
+		}
+
+		expected := shift.OpeningCash + cashTotal
+		variance := countedCash - expected
+		shift.ClosedAt = &closedAt
+		shift.ExpectedCash = &expected
+		shift.CountedCash = &countedCash
+		shift.Variance = &variance
+		shift.ClosingNotes = notes
+		return tx.Save(&shift).Error
+	})
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
+// GetPaymentTotals sums the payments recorded into a shift per method
+func (r *ShiftRepository) GetPaymentTotals(ctx context.Context, shiftID uint) ([]MethodTotal, error) {
+	var totals []MethodTotal
+	err := conn(ctx, r.db).Model(&models.Payment{}).
+		Select("method, COUNT(*) AS count, COALESCE(SUM(amount), 0) AS amount").
+		Where("shift_id = ?", shiftID).
+		Group("method").Order("method").Scan(&totals).Error
+	return totals, err
+}
+
+// GetShiftPayments retrieves the payments recorded into a shift in the order they were taken
+func (r *ShiftRepository) GetShiftPayments(ctx context.Context, shiftID uint) ([]models.Payment, error) {
+	var payments []models.Payment
+	err := conn(ctx, r.db).Where("shift_id = ?", shiftID).
+		Order("paid_at ASC").Order("id ASC").Find(&payments).Error
+	return payments, err
+}
+
+// IsDuplicateKey reports whether err is a MySQL unique constraint violation
+func IsDuplicateKey(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == 1062
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestGetShiftAtCondition checks that a payment time finds the shift open at that time: opened
+// at or before it and closed after it, or not closed yet
+func TestGetShiftAtCondition(t *testing.T) {
+	db := dryRunDB(t)
+	statements := captureSQL(t, db)
+	if _, err := NewShiftRepository(db).GetShiftAt(context.Background(), 1, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	want := "WHERE admin_id = ? AND opened_at <= ? AND (closed_at IS NULL OR closed_at > ?) ORDER BY opened_at DESC"
+	if len(*statements) != 1 || !strings.Contains((*statements)[0], want) {
+		t.Errorf("got %q, want a query containing %q", *statements, want)
+	}
+}
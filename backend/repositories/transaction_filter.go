@@ -0,0 +1,160 @@
+package repositories
+
+import (
+	"strings"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+)
+
+// Columns a transaction listing can be filtered by date or sorted on
+const (
+	TransactionFieldCreatedAt    = "created_at"
+	TransactionFieldPickupDate   = "pickup_date"
+	TransactionFieldCompletedAt  = "completed_at"
+	TransactionFieldTotalPrice   = "total_price"
+	TransactionFieldCustomerName = "customer_name"
+)
+
+// TransactionDateFields are the columns a date range can apply to
+var TransactionDateFields = []string{TransactionFieldCreatedAt, TransactionFieldPickupDate, TransactionFieldCompletedAt}
+
+// TransactionSortFields are the columns a listing can be sorted on
+var TransactionSortFields = []string{
+	TransactionFieldCreatedAt, TransactionFieldPickupDate, TransactionFieldCompletedAt,
+	TransactionFieldTotalPrice, TransactionFieldCustomerName,
+}
+
+// TransactionFilter narrows, orders and pages a transaction listing.
+// Zero values leave the corresponding constraint out.
+type TransactionFilter struct {
+	Keyword     string // matched against customer name, phone and transaction code
+	Statuses    []models.TransactionStatus
+	IsPaid      *bool
+	DateField   string     // one of TransactionDateFields, defaults to created_at
+	From        *time.Time // inclusive
+	To          *time.Time // exclusive
+	AdminID     uint
+	ServiceType string // transactions with at least one item of this service type
+	MinPrice    *float64
+	MaxPrice    *float64
+
+	SortField string // one of TransactionSortFields, defaults to created_at
+	SortDesc  bool
+
+	Limit  int
+	Offset int
+	After  *TransactionKey // keyset pagination, takes precedence over Offset
+}
+
+// TransactionKey is the position of a row in a sorted listing, used as a keyset cursor.
+// Value holds the sort column of the row: time.Time, float64 or string.
+type TransactionKey struct {
+	Value any
+	ID    uint
+}
+
+// apply adds the filter's WHERE clauses to query
+func (f TransactionFilter) apply(query *gorm.DB) *gorm.DB {
+	if f.Keyword != "" {
+		pattern := "%" + escapeLike(f.Keyword) + "%"
+		query = query.Where("(customer_name LIKE ? OR customer_phone LIKE ? OR transaction_code LIKE ?)", pattern, pattern, pattern)
+	}
+	if len(f.Statuses) > 0 {
+		query = query.Where("status IN ?", f.Statuses)
+	}
+	if f.IsPaid != nil {
+		query = query.Where("is_paid = ?", *f.IsPaid)
+	}
+
+	dateField := f.dateField()
+	if f.From != nil {
+		query = query.Where(dateField+" >= ?", *f.From)
+	}
+	if f.To != nil {
+		query = query.Where(dateField+" < ?", *f.To)
+	}
+
+	if f.AdminID != 0 {
+		query = query.Where("admin_id = ?", f.AdminID)
+	}
+	if f.ServiceType != "" {
+		query = query.Where("EXISTS (SELECT 1 FROM transaction_items ti WHERE ti.transaction_id = transactions.id AND ti.service_type = ? AND ti.deleted_at IS NULL)", f.ServiceType)
+	}
+	if f.MinPrice != nil {
+		query = query.Where("total_price >= ?", *f.MinPrice)
+	}
+	if f.MaxPrice != nil {
+		query = query.Where("total_price <= ?", *f.MaxPrice)
+	}
+	return query
+}
+
+// order adds the ORDER BY clause and, for keyset pagination, the position condition.
+// The id column breaks ties so the order is total and cursors never skip rows.
+func (f TransactionFilter) order(query *gorm.DB) *gorm.DB {
+	sortField := f.sortField()
+	direction, comparison := "ASC", ">"
+	if f.SortDesc {
+		direction, comparison = "DESC", "<"
+	}
+
+	if f.After != nil {
+		query = query.Where(
+			"("+sortField+" "+comparison+" ? OR ("+sortField+" = ? AND id "+comparison+" ?))",
+			f.After.Value, f.After.Value, f.After.ID,
+		)
+	}
+	return query.Order(sortField + " " + direction).Order("id " + direction)
+}
+
+// dateField returns the validated date range column
+func (f TransactionFilter) dateField() string {
+	for _, field := range TransactionDateFields {
+		if f.DateField == field {
+			return field
+		}
+	}
+	return TransactionFieldCreatedAt
+}
+
+// sortField returns the validated sort column
+func (f TransactionFilter) sortField() string {
+	for _, field := range TransactionSortFields {
+		if f.SortField == field {
+			return field
+		}
+	}
+	return TransactionFieldCreatedAt
+}
+
+// SupportsKeyset reports whether the sort field can back a keyset cursor.
+// Pickup and completion dates are nullable, and NULLs cannot be compared against a cursor.
+func (f TransactionFilter) SupportsKeyset() bool {
+	switch f.sortField() {
+	case TransactionFieldPickupDate, TransactionFieldCompletedAt:
+		return false
+	default:
+		return true
+	}
+}
+
+// KeyOf returns the keyset position of a transaction for the filter's sort field
+func (f TransactionFilter) KeyOf(transaction models.Transaction) TransactionKey {
+	key := TransactionKey{ID: transaction.ID}
+	switch f.sortField() {
+	case TransactionFieldTotalPrice:
+		key.Value = transaction.TotalPrice
+	case TransactionFieldCustomerName:
+		key.Value = transaction.CustomerName
+	default:
+		key.Value = transaction.CreatedAt
+	}
+	return key
+}
+
+// escapeLike escapes LIKE wildcards so keywords match literally
+func escapeLike(keyword string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(keyword)
+}
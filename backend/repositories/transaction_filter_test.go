@@ -0,0 +1,84 @@
+package repositories
+
+import (
+	"testing"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+)
+
+// dryRunDB returns a database that builds MySQL statements without connecting
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{DSN: "test@tcp(127.0.0.1:3306)/test", SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, SkipDefaultTransaction: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func TestTransactionFilterOrder(t *testing.T) {
+	db := dryRunDB(t)
+	tests := []struct {
+		name   string
+		filter TransactionFilter
+		want   string
+	}{
+		{
+			name:   "default sort",
+			filter: TransactionFilter{},
+			want:   "SELECT * FROM `transactions` WHERE `transactions`.`deleted_at` IS NULL ORDER BY created_at ASC,id ASC",
+		},
+		{
+			name:   "descending",
+			filter: TransactionFilter{SortField: TransactionFieldTotalPrice, SortDesc: true},
+			want:   "SELECT * FROM `transactions` WHERE `transactions`.`deleted_at` IS NULL ORDER BY total_price DESC,id DESC",
+		},
+		{
+			name:   "unknown field falls back to created_at",
+			filter: TransactionFilter{SortField: "password; DROP TABLE admins"},
+			want:   "SELECT * FROM `transactions` WHERE `transactions`.`deleted_at` IS NULL ORDER BY created_at ASC,id ASC",
+		},
+		{
+			name:   "keyset breaks ties on id",
+			filter: TransactionFilter{SortField: TransactionFieldCustomerName, After: &TransactionKey{Value: "Ridwan", ID: 42}},
+			want:   "SELECT * FROM `transactions` WHERE ((customer_name > 'Ridwan' OR (customer_name = 'Ridwan' AND id > 42))) AND `transactions`.`deleted_at` IS NULL ORDER BY customer_name ASC,id ASC",
+		},
+		{
+			name:   "keyset descending",
+			filter: TransactionFilter{SortField: TransactionFieldTotalPrice, SortDesc: true, After: &TransactionKey{Value: 15000.0, ID: 9}},
+			want:   "SELECT * FROM `transactions` WHERE ((total_price < 15000 OR (total_price = 15000 AND id < 9))) AND `transactions`.`deleted_at` IS NULL ORDER BY total_price DESC,id DESC",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := db.ToSQL(func(tx *gorm.DB) *gorm.DB {
+				var transactions []models.Transaction
+				return tt.filter.order(tx.Model(&models.Transaction{})).Find(&transactions)
+			})
+			if got != tt.want {
+				t.Errorf("SQL =\n%s\nwant\n%s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransactionFilterKeyOf(t *testing.T) {
+	transaction := models.Transaction{ID: 5, CustomerName: "Ridwan", TotalPrice: 20000}
+	tests := []struct {
+		sortField string
+		want      any
+	}{
+		{TransactionFieldTotalPrice, 20000.0},
+		{TransactionFieldCustomerName, "Ridwan"},
+		{TransactionFieldCreatedAt, transaction.CreatedAt},
+	}
+	for _, tt := range tests {
+		key := TransactionFilter{SortField: tt.sortField}.KeyOf(transaction)
+		if key.ID != 5 || key.Value != tt.want {
+			t.Errorf("KeyOf for %s = %+v, want value %v and id 5", tt.sortField, key, tt.want)
+		}
+	}
+}
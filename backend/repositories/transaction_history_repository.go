@@ -1,8 +1,11 @@
 package repositories
 
 import (
+	"context"
+
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TransactionHistoryRepository handles transaction history database operations
@@ -15,20 +18,21 @@ func NewTransactionHistoryRepository(db *gorm.DB) *TransactionHistoryRepository
 	return &TransactionHistoryRepository{db: db}
 }
 
-// CreateHistory creates a new transaction history record
-func (r *TransactionHistoryRepository) CreateHistory(history *models.TransactionHistory) error {
-	return r.db.Create(history).Error
+// CreateHistory creates a new transaction history record. A record for an event that
+// already has one is ignored.
+func (r *TransactionHistoryRepository) CreateHistory(ctx context.Context, history *models.TransactionHistory) error {
+	return conn(ctx, r.db).Clauses(clause.OnConflict{DoNothing: true}).Create(history).Error
 }
 
 // GetHistoryByTransactionID retrieves all history records for a transaction
-func (r *TransactionHistoryRepository) GetHistoryByTransactionID(transactionID uint) ([]models.TransactionHistory, error) {
+func (r *TransactionHistoryRepository) GetHistoryByTransactionID(ctx context.Context, transactionID uint) ([]models.TransactionHistory, error) {
 	var history []models.TransactionHistory
-	err := r.db.Where("transaction_id = ?", transactionID).
+	err := conn(ctx, r.db).Where("transaction_id = ?", transactionID).
 		Order("created_at ASC").Find(&history).Error
 	return history, err
 }
 
 // DeleteHistoryByTransactionID deletes all history records for a transaction
-func (r *TransactionHistoryRepository) DeleteHistoryByTransactionID(transactionID uint) error {
-	return r.db.Where("transaction_id = ?", transactionID).Delete(&models.TransactionHistory{}).Error
+func (r *TransactionHistoryRepository) DeleteHistoryByTransactionID(ctx context.Context, transactionID uint) error {
+	return conn(ctx, r.db).Where("transaction_id = ?", transactionID).Delete(&models.TransactionHistory{}).Error
 }
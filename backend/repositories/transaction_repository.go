@@ -1,8 +1,12 @@
 package repositories
 
 import (
+	"context"
+	"time"
+
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // TransactionRepository handles transaction database operations
@@ -16,14 +20,28 @@ func NewTransactionRepository(db *gorm.DB) *TransactionRepository {
 }
 
 // CreateTransaction creates a new transaction with items
-func (r *TransactionRepository) CreateTransaction(transaction *models.Transaction) error {
-	return r.db.Create(transaction).Error
+func (r *TransactionRepository) CreateTransaction(ctx context.Context, transaction *models.Transaction) error {
+	return conn(ctx, r.db).Create(transaction).Error
 }
 
 // GetTransactionByID retrieves a transaction by ID with preloaded relationships
-func (r *TransactionRepository) GetTransactionByID(id uint) (*models.Transaction, error) {
+func (r *TransactionRepository) GetTransactionByID(ctx context.Context, id uint) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := conn(ctx, r.db).Preload("Items").Preload("StatusHistory").Preload("Admin").Preload("Payments").
+		Where("id = ?", id).First(&transaction).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	return &transaction, err
+}
+
+// GetTransactionByIDForUpdate retrieves a transaction like GetTransactionByID and locks its row
+// until the database transaction in ctx ends, so changes to its items, total and payment
+// happen one at a time
+func (r *TransactionRepository) GetTransactionByIDForUpdate(ctx context.Context, id uint) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Preload("Items").Preload("StatusHistory").Preload("Admin").
+	err := conn(ctx, r.db).Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("Items").Preload("StatusHistory").Preload("Admin").Preload("Payments").
 		Where("id = ?", id).First(&transaction).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -32,9 +50,9 @@ func (r *TransactionRepository) GetTransactionByID(id uint) (*models.Transaction
 }
 
 // GetTransactionByCode retrieves a transaction by transaction code
-func (r *TransactionRepository) GetTransactionByCode(code string) (*models.Transaction, error) {
+func (r *TransactionRepository) GetTransactionByCode(ctx context.Context, code string) (*models.Transaction, error) {
 	var transaction models.Transaction
-	err := r.db.Preload("Items").Preload("StatusHistory").Preload("Admin").
+	err := conn(ctx, r.db).Preload("Items").Preload("StatusHistory").Preload("Admin").
 		Where("transaction_code = ?", code).First(&transaction).Error
 	if err == gorm.ErrRecordNotFound {
 		return nil, nil
@@ -42,204 +60,264 @@ func (r *TransactionRepository) GetTransactionByCode(code string) (*models.Trans
 	return &transaction, err
 }
 
-// UpdateTransaction updates a transaction
-func (r *TransactionRepository) UpdateTransaction(transaction *models.Transaction) error {
-	return r.db.Save(transaction).Error
+// UpdateTransaction updates the fields of a transaction, leaving its items, history and payments alone
+func (r *TransactionRepository) UpdateTransaction(ctx context.Context, transaction *models.Transaction) error {
+	return conn(ctx, r.db).Omit(clause.Associations).Save(transaction).Error
 }
 
-// DeleteTransaction soft deletes a transaction
-func (r *TransactionRepository) DeleteTransaction(id uint) error {
-	return r.db.Delete(&models.Transaction{}, id).Error
+// DeleteTransaction moves a transaction, its items and its history to the trash, recording who
+// deleted it and why. They share the same deleted_at, which is how a restore finds them again.
+func (r *TransactionRepository) DeleteTransaction(ctx context.Context, id uint, deletedBy, reason string) error {
+	deletedAt := time.Now().Truncate(time.Millisecond) // stored as DATETIME(3)
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.Transaction{}).Where("id = ?", id).Updates(map[string]any{
+			"deleted_at":    deletedAt,
+			"deleted_by":    deletedBy,
+			"delete_reason": reason,
+		}).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.TransactionItem{}).Where("transaction_id = ?", id).Update("deleted_at", deletedAt).Error; err != nil {
+			return err
+		}
+		return tx.Model(&models.TransactionHistory{}).Where("transaction_id = ?", id).Update("deleted_at", deletedAt).Error
+	})
 }
 
-// GetAllTransactions retrieves all transactions with pagination and optional status filter
-func (r *TransactionRepository) GetAllTransactions(limit, offset int, status string) ([]models.Transaction, int64, error) {
-	var transactions []models.Transaction
-	var total int64
-
-	query := r.db.Model(&models.Transaction{})
-	if status != "" {
-		query = query.Where("status = ?", status)
+// GetTransactionItem retrieves an item of a transaction
+func (r *TransactionRepository) GetTransactionItem(ctx context.Context, transactionID, itemID uint) (*models.TransactionItem, error) {
+	var item models.TransactionItem
+	err := conn(ctx, r.db).Where("id = ? AND transaction_id = ?", itemID, transactionID).First(&item).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
 	}
+	return &item, err
+}
 
-	err := query.Count(&total).Error
-	if err != nil {
+// CreateTransactionItem adds an item to a transaction
+func (r *TransactionRepository) CreateTransactionItem(ctx context.Context, item *models.TransactionItem) error {
+	return conn(ctx, r.db).Create(item).Error
+}
+
+// UpdateTransactionItem updates an item of a transaction
+func (r *TransactionRepository) UpdateTransactionItem(ctx context.Context, item *models.TransactionItem) error {
+	return conn(ctx, r.db).Save(item).Error
+}
+
+// DeleteTransactionItem soft deletes an item of a transaction
+func (r *TransactionRepository) DeleteTransactionItem(ctx context.Context, itemID uint) error {
+	return conn(ctx, r.db).Delete(&models.TransactionItem{}, itemID).Error
+}
+
+// RecalculateTotalPrice sets the total price of a transaction to the sum of its item subtotals
+func (r *TransactionRepository) RecalculateTotalPrice(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Model(&models.Transaction{}).Where("id = ?", id).Update("total_price", gorm.Expr(
+		"(SELECT COALESCE(SUM(subtotal), 0) FROM transaction_items WHERE transaction_id = ? AND deleted_at IS NULL)", id,
+	)).Error
+}
+
+// ListDeletedTransactions retrieves a page of the transactions in the trash, most recently deleted first,
+// with the items deleted along with them and their payments, and the total number in the trash
+func (r *TransactionRepository) ListDeletedTransactions(ctx context.Context, limit, offset int) ([]models.Transaction, int64, error) {
+	query := conn(ctx, r.db).Unscoped().Model(&models.Transaction{}).Where("deleted_at IS NOT NULL")
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
 		return nil, 0, err
 	}
 
-	err = query.Preload("Items").Preload("Admin").
-		Limit(limit).Offset(offset).
-		Order("created_at DESC").
+	var transactions []models.Transaction
+	err := query.Preload("Items").Preload("Admin").Preload("Payments").
+		Order("deleted_at DESC, id DESC").Limit(limit).Offset(offset).
 		Find(&transactions).Error
+	for i := range transactions {
+		transactions[i].Items = deletedWith(transactions[i].Items, transactions[i].DeletedAt)
+	}
 	return transactions, total, err
 }
 
-// GetTransactionsByStatus retrieves transactions by status with pagination
-func (r *TransactionRepository) GetTransactionsByStatus(status models.TransactionStatus, limit, offset int) ([]models.Transaction, int64, error) {
-	var transactions []models.Transaction
-	var total int64
-	err := r.db.Model(&models.Transaction{}).Where("status = ?", status).Count(&total).Error
+// GetDeletedTransaction retrieves a transaction in the trash with the items and history deleted along with it
+func (r *TransactionRepository) GetDeletedTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	var transaction models.Transaction
+	err := conn(ctx, r.db).Unscoped().Preload("Items").Preload("StatusHistory").Preload("Admin").Preload("Payments").
+		Where("id = ? AND deleted_at IS NOT NULL", id).First(&transaction).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
 	if err != nil {
-		return nil, 0, err
+		return nil, err
 	}
-	err = r.db.Preload("Items").Preload("Admin").
-		Where("status = ?", status).
-		Limit(limit).Offset(offset).
-		Order("created_at DESC").
-		Find(&transactions).Error
-	return transactions, total, err
+	transaction.Items = deletedWith(transaction.Items, transaction.DeletedAt)
+	history := transaction.StatusHistory[:0]
+	for _, entry := range transaction.StatusHistory {
+		if entry.DeletedAt.Valid && entry.DeletedAt.Time.Equal(transaction.DeletedAt.Time) {
+			history = append(history, entry)
+		}
+	}
+	transaction.StatusHistory = history
+	return &transaction, nil
 }
 
-// GetTransactionsByCustomerPhone retrieves transactions by customer phone
-func (r *TransactionRepository) GetTransactionsByCustomerPhone(phone string) ([]models.Transaction, error) {
-	var transactions []models.Transaction
-	err := r.db.Preload("Items").Preload("Admin").
-		Where("customer_phone = ?", phone).
-		Order("created_at DESC").
-		Find(&transactions).Error
-	return transactions, err
+// deletedWith keeps the items deleted at the same time as their transaction
+func deletedWith(items []models.TransactionItem, deletedAt gorm.DeletedAt) []models.TransactionItem {
+	kept := items[:0]
+	for _, item := range items {
+		if item.DeletedAt.Valid && item.DeletedAt.Time.Equal(deletedAt.Time) {
+			kept = append(kept, item)
+		}
+	}
+	return kept
 }
 
-// UpdateTransactionStatus updates transaction status only (history is handled by service layer)
-func (r *TransactionRepository) UpdateTransactionStatus(transactionID uint, newStatus models.TransactionStatus) error {
-	return r.db.Model(&models.Transaction{}).Where("id = ?", transactionID).Update("status", newStatus).Error
+// RestoreTransaction takes a transaction out of the trash with the items and history deleted along with it
+func (r *TransactionRepository) RestoreTransaction(ctx context.Context, transaction *models.Transaction) error {
+	deletedAt := transaction.DeletedAt.Time
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.TransactionItem{}).
+			Where("transaction_id = ? AND deleted_at = ?", transaction.ID, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Model(&models.TransactionHistory{}).
+			Where("transaction_id = ? AND deleted_at = ?", transaction.ID, deletedAt).
+			Update("deleted_at", nil).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Model(&models.Transaction{}).Where("id = ?", transaction.ID).Updates(map[string]any{
+			"deleted_at":    nil,
+			"deleted_by":    "",
+			"delete_reason": "",
+		}).Error
+	})
 }
 
-// UpdatePaymentStatus updates the payment status of a transaction
-func (r *TransactionRepository) UpdatePaymentStatus(id uint, isPaid bool) error {
-	return r.db.Model(&models.Transaction{}).Where("id = ?", id).Update("is_paid", isPaid).Error
+// PurgeTransaction permanently deletes a transaction in the trash with its items, history and notifications
+func (r *TransactionRepository) PurgeTransaction(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Where("transaction_id = ?", id).Delete(&models.TransactionItem{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Unscoped().Where("transaction_id = ?", id).Delete(&models.TransactionHistory{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("transaction_id = ?", id).Delete(&models.Notification{}).Error; err != nil {
+			return err
+		}
+		return tx.Unscoped().Where("id = ? AND deleted_at IS NOT NULL", id).Delete(&models.Transaction{}).Error
+	})
+}
+
+// ExpiredTransactionIDs returns up to limit IDs of transactions deleted before cutoff that can be purged.
+// Transactions with payments are kept for the cash book and never returned.
+func (r *TransactionRepository) ExpiredTransactionIDs(ctx context.Context, cutoff time.Time, limit int) ([]uint, error) {
+	var ids []uint
+	err := conn(ctx, r.db).Unscoped().Model(&models.Transaction{}).
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", cutoff).
+		Where("NOT EXISTS (SELECT 1 FROM payments p WHERE p.transaction_id = transactions.id)").
+		Order("deleted_at ASC").Limit(limit).Pluck("id", &ids).Error
+	return ids, err
 }
 
-// GetTransactionsByDateRange retrieves transactions within a date range
-func (r *TransactionRepository) GetTransactionsByDateRange(startDate, endDate int64, limit, offset int) ([]models.Transaction, int64, error) {
+// ListTransactions retrieves the transactions matching the filter, sorted and paged as it specifies
+func (r *TransactionRepository) ListTransactions(ctx context.Context, filter TransactionFilter) ([]models.Transaction, error) {
 	var transactions []models.Transaction
+	query := filter.order(filter.apply(conn(ctx, r.db).Model(&models.Transaction{})))
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+	if filter.After == nil && filter.Offset > 0 {
+		query = query.Offset(filter.Offset)
+	}
+	err := query.Preload("Items").Preload("Admin").Find(&transactions).Error
+	return transactions, err
+}
+
+// CountTransactions counts the transactions matching the filter, ignoring its paging
+func (r *TransactionRepository) CountTransactions(ctx context.Context, filter TransactionFilter) (int64, error) {
 	var total int64
-	query := r.db.Model(&models.Transaction{}).
-		Where("created_at >= ? AND created_at <= ?", startDate, endDate)
+	err := filter.apply(conn(ctx, r.db).Model(&models.Transaction{})).Count(&total).Error
+	return total, err
+}
 
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
+// StreamTransactions calls fn with the transactions matching the filter, their items and admin,
+// batchSize at a time in ID order. The filter's sort and paging are ignored.
+func (r *TransactionRepository) StreamTransactions(ctx context.Context, filter TransactionFilter, batchSize int, fn func([]models.Transaction) error) error {
+	var batch []models.Transaction
+	return filter.apply(conn(ctx, r.db).Model(&models.Transaction{})).
+		Preload("Items").Preload("Admin").Preload("Payments").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
+}
+
+// GetTransactionsByIDs retrieves the transactions with the given IDs, in no particular order
+func (r *TransactionRepository) GetTransactionsByIDs(ctx context.Context, ids []uint) ([]models.Transaction, error) {
+	var transactions []models.Transaction
+	if len(ids) == 0 {
+		return transactions, nil
 	}
+	err := conn(ctx, r.db).Preload("Items").Preload("Admin").
+		Where("id IN ?", ids).Find(&transactions).Error
+	return transactions, err
+}
 
-	err = query.Preload("Items").Preload("Admin").
-		Limit(limit).Offset(offset).
-		Order("created_at DESC").
-		Find(&transactions).Error
-	return transactions, total, err
+// FindTransactionsInBatches calls fn with every transaction and its items, batchSize at a time
+func (r *TransactionRepository) FindTransactionsInBatches(ctx context.Context, batchSize int, fn func([]models.Transaction) error) error {
+	var batch []models.Transaction
+	return conn(ctx, r.db).Preload("Items").Order("id").
+		FindInBatches(&batch, batchSize, func(tx *gorm.DB, _ int) error {
+			return fn(batch)
+		}).Error
 }
 
-// GetUnpaidTransactions retrieves all unpaid transactions
-func (r *TransactionRepository) GetUnpaidTransactions(limit, offset int) ([]models.Transaction, int64, error) {
+// GetTransactionsByCustomerPhone retrieves the transactions of a customer, newest first, with
+// their items and payments. A phone number can be given in each way it may have been written.
+func (r *TransactionRepository) GetTransactionsByCustomerPhone(ctx context.Context, phones ...string) ([]models.Transaction, error) {
 	var transactions []models.Transaction
-	var total int64
-	err := r.db.Model(&models.Transaction{}).Where("is_paid = ?", false).Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-	err = r.db.Preload("Items").Preload("Admin").
-		Where("is_paid = ?", false).
-		Limit(limit).Offset(offset).
+	err := conn(ctx, r.db).Preload("Items").Preload("Payments").
+		Where("customer_phone IN ?", phones).
 		Order("created_at DESC").
 		Find(&transactions).Error
-	return transactions, total, err
+	return transactions, err
 }
 
-// GetTransactionsByAdminID retrieves transactions created by a specific admin
-func (r *TransactionRepository) GetTransactionsByAdminID(adminID uint, limit, offset int) ([]models.Transaction, int64, error) {
-	var transactions []models.Transaction
-	var total int64
-	err := r.db.Model(&models.Transaction{}).Where("admin_id = ?", adminID).Count(&total).Error
-	if err != nil {
-		return nil, 0, err
+// UpdateCustomerAddress updates the customer address of a transaction only
+func (r *TransactionRepository) UpdateCustomerAddress(ctx context.Context, id uint, address string) error {
+	return conn(ctx, r.db).Model(&models.Transaction{}).Where("id = ?", id).Update("customer_address", address).Error
+}
+
+// UpdateTransactionStatus updates transaction status only (history is handled by service layer).
+// It reports false when the transaction is no longer in previousStatus, so two concurrent
+// changes cannot both apply. Moving to Completed stamps completed_at with changedAt in the
+// same statement.
+func (r *TransactionRepository) UpdateTransactionStatus(ctx context.Context, transactionID uint, previousStatus, newStatus models.TransactionStatus, changedAt time.Time) (bool, error) {
+	changes := map[string]any{"status": newStatus}
+	if newStatus == models.StatusCompleted {
+		changes["completed_at"] = changedAt
 	}
-	err = r.db.Preload("Items").Preload("Admin").
-		Where("admin_id = ?", adminID).
-		Limit(limit).Offset(offset).
-		Order("created_at DESC").
-		Find(&transactions).Error
-	return transactions, total, err
+	result := conn(ctx, r.db).Model(&models.Transaction{}).
+		Where("id = ? AND status = ?", transactionID, previousStatus).
+		Updates(changes)
+	return result.RowsAffected == 1, result.Error
+}
+
+// UpdatePaymentStatus updates the payment status of a transaction
+func (r *TransactionRepository) UpdatePaymentStatus(ctx context.Context, id uint, isPaid bool) error {
+	return conn(ctx, r.db).Model(&models.Transaction{}).Where("id = ?", id).Update("is_paid", isPaid).Error
 }
 
 // GetTransactionHistory retrieves status history for a transaction
-func (r *TransactionRepository) GetTransactionHistory(transactionID uint) ([]models.TransactionHistory, error) {
+func (r *TransactionRepository) GetTransactionHistory(ctx context.Context, transactionID uint) ([]models.TransactionHistory, error) {
 	var history []models.TransactionHistory
-	err := r.db.Where("transaction_id = ?", transactionID).
+	err := conn(ctx, r.db).Where("transaction_id = ?", transactionID).
 		Order("created_at DESC").
 		Find(&history).Error
 	return history, err
 }
 
-// SearchTransactions searches transactions by customer name or transaction code
-func (r *TransactionRepository) SearchTransactions(keyword string, limit, offset int) ([]models.Transaction, int64, error) {
-	var transactions []models.Transaction
-	var total int64
-	searchPattern := "%" + keyword + "%"
-	query := r.db.Model(&models.Transaction{}).
-		Where("customer_name LIKE ? OR transaction_code LIKE ?", searchPattern, searchPattern)
-
-	err := query.Count(&total).Error
-	if err != nil {
-		return nil, 0, err
-	}
-
-	err = query.Preload("Items").Preload("Admin").
-		Limit(limit).Offset(offset).
-		Order("created_at DESC").
-		Find(&transactions).Error
-	return transactions, total, err
-}
-
 // CountTransactionsByStatus counts transactions by status
-func (r *TransactionRepository) CountTransactionsByStatus(status models.TransactionStatus) (int64, error) {
+func (r *TransactionRepository) CountTransactionsByStatus(ctx context.Context, status models.TransactionStatus) (int64, error) {
 	var count int64
-	err := r.db.Model(&models.Transaction{}).Where("status = ?", status).Count(&count).Error
+	err := conn(ctx, r.db).Model(&models.Transaction{}).Where("status = ?", status).Count(&count).Error
 	return count, err
 }
-
-// GetDashboardStats retrieves statistics for dashboard
-func (r *TransactionRepository) GetDashboardStats() (map[string]interface{}, error) {
-	stats := make(map[string]interface{})
-
-	// Total transactions
-	var totalTransactions int64
-	if err := r.db.Model(&models.Transaction{}).Count(&totalTransactions).Error; err != nil {
-		return nil, err
-	}
-	stats["total_transactions"] = totalTransactions
-
-	// Transactions by status
-	var statusCounts []struct {
-		Status models.TransactionStatus
-		Count  int64
-	}
-	if err := r.db.Model(&models.Transaction{}).
-		Select("status, count(*) as count").
-		Group("status").
-		Scan(&statusCounts).Error; err != nil {
-		return nil, err
-	}
-	stats["status_counts"] = statusCounts
-
-	// Total revenue
-	var totalRevenue float64
-	if err := r.db.Model(&models.Transaction{}).
-		Where("is_paid = ?", true).
-		Select("COALESCE(SUM(total_price), 0)").
-		Scan(&totalRevenue).Error; err != nil {
-		return nil, err
-	}
-	stats["total_revenue"] = totalRevenue
-
-	// Unpaid amount
-	var unpaidAmount float64
-	if err := r.db.Model(&models.Transaction{}).
-		Where("is_paid = ?", false).
-		Select("COALESCE(SUM(total_price), 0)").
-		Scan(&unpaidAmount).Error; err != nil {
-		return nil, err
-	}
-	stats["unpaid_amount"] = unpaidAmount
-
-	return stats, nil
-}
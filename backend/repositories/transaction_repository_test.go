@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+)
+
+// captureSQL records the statements a dry-run db would have executed
+func captureSQL(t *testing.T, db *gorm.DB) *[]string {
+	t.Helper()
+	var statements []string
+	record := func(tx *gorm.DB) { statements = append(statements, tx.Statement.SQL.String()) }
+	for name, err := range map[string]error{
+		"update": db.Callback().Update().After("gorm:update").Register("test:capture_update", record),
+		"query":  db.Callback().Query().After("gorm:query").Register("test:capture_query", record),
+	} {
+		if err != nil {
+			t.Fatalf("register %s callback: %v", name, err)
+		}
+	}
+	return &statements
+}
+
+func TestUpdateTransactionStatus(t *testing.T) {
+	changedAt := time.Date(2025, 3, 1, 9, 30, 0, 0, time.UTC)
+	tests := []struct {
+		status        models.TransactionStatus
+		wantCompleted bool
+	}{
+		{models.StatusWashing, false},
+		{models.StatusReadytoPickup, false},
+		{models.StatusCompleted, true},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.status), func(t *testing.T) {
+			db := dryRunDB(t)
+			statements := captureSQL(t, db)
+			if _, err := NewTransactionRepository(db).UpdateTransactionStatus(context.Background(), 7, models.StatusWashing, tt.status, changedAt); err != nil {
+				t.Fatal(err)
+			}
+			if len(*statements) != 1 {
+				t.Fatalf("got %d statements, want 1: %q", len(*statements), *statements)
+			}
+			sql := (*statements)[0]
+			if !strings.Contains(sql, "WHERE (id = ? AND status = ?)") {
+				t.Errorf("update not conditional on the previous status: %s", sql)
+			}
+			if !strings.Contains(sql, "`status`=?") {
+				t.Errorf("status not updated: %s", sql)
+			}
+			if got := strings.Contains(sql, "`completed_at`=?"); got != tt.wantCompleted {
+				t.Errorf("completed_at in update = %v, want %v: %s", got, tt.wantCompleted, sql)
+			}
+		})
+	}
+}
+
+func TestGetTransactionByIDForUpdateLocksRow(t *testing.T) {
+	db := dryRunDB(t)
+	statements := captureSQL(t, db)
+	if _, err := NewTransactionRepository(db).GetTransactionByIDForUpdate(context.Background(), 7); err != nil {
+		t.Fatal(err)
+	}
+	if len(*statements) == 0 {
+		t.Fatal("no statement executed")
+	}
+	sql := (*statements)[0]
+	if !strings.HasPrefix(sql, "SELECT * FROM `transactions`") || !strings.HasSuffix(sql, "FOR UPDATE") {
+		t.Errorf("transaction row not locked: %s", sql)
+	}
+}
@@ -0,0 +1,76 @@
+package repositories
+
+import (
+	"context"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TransactionSearchRepository handles the transaction search index
+type TransactionSearchRepository struct {
+	db *gorm.DB
+}
+
+// NewTransactionSearchRepository creates a new transaction search repository
+func NewTransactionSearchRepository(db *gorm.DB) *TransactionSearchRepository {
+	return &TransactionSearchRepository{db: db}
+}
+
+// ReplaceTerms swaps the indexed terms of a transaction for the given ones
+func (r *TransactionSearchRepository) ReplaceTerms(ctx context.Context, transactionID uint, terms []models.TransactionSearchTerm) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("transaction_id = ?", transactionID).Delete(&models.TransactionSearchTerm{}).Error; err != nil {
+			return err
+		}
+		if len(terms) == 0 {
+			return nil
+		}
+		return tx.CreateInBatches(terms, 200).Error
+	})
+}
+
+// SearchCandidate is an indexed term that may match a query term
+type SearchCandidate struct {
+	QueryTerm     string
+	TransactionID uint
+	Field         string
+	Term          string
+}
+
+// FindCandidates returns the indexed terms of live transactions starting with a query term,
+// or sharing its first prefixLength characters with a length within maxEdits of it.
+// At most limit candidates are returned per query term: exact matches first, then prefix
+// matches, then the closest in length, latest transactions first among equals.
+func (r *TransactionSearchRepository) FindCandidates(ctx context.Context, queryTerm string, prefixLength, maxEdits, limit int) ([]SearchCandidate, error) {
+	query := conn(ctx, r.db).Table("transaction_search_terms AS st").
+		Select("DISTINCT st.transaction_id, st.field, st.term").
+		Joins("JOIN transactions t ON t.id = st.transaction_id AND t.deleted_at IS NULL")
+
+	runes := []rune(queryTerm)
+	prefix := escapeLike(queryTerm) + "%"
+	if maxEdits > 0 && len(runes) > prefixLength {
+		query = query.Where(
+			"(st.term LIKE ? OR (st.term LIKE ? AND CHAR_LENGTH(st.term) BETWEEN ? AND ?))",
+			prefix, escapeLike(string(runes[:prefixLength]))+"%",
+			len(runes)-maxEdits, len(runes)+maxEdits,
+		)
+	} else {
+		query = query.Where("st.term LIKE ?", prefix)
+	}
+
+	// Without an order the limit could cut off an exact match in favour of fuzzy ones
+	query = query.Order(clause.OrderBy{Expression: clause.Expr{
+		SQL:                "st.term = ? DESC, st.term LIKE ? DESC, ABS(CHAR_LENGTH(st.term) - ?) ASC, st.transaction_id DESC",
+		Vars:               []any{queryTerm, prefix, len(runes)},
+		WithoutParentheses: true,
+	}})
+
+	var candidates []SearchCandidate
+	err := query.Limit(limit).Scan(&candidates).Error
+	for i := range candidates {
+		candidates[i].QueryTerm = queryTerm
+	}
+	return candidates, err
+}
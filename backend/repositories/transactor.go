@@ -0,0 +1,66 @@
+package repositories
+
+import (
+	"context"
+
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the database transaction started by WithinTransaction
+type txKey struct{}
+
+// txState is a running database transaction and the functions to call once it commits
+type txState struct {
+	tx          *gorm.DB
+	afterCommit []func()
+}
+
+// Transactor runs several repository calls in one database transaction
+type Transactor struct {
+	db *gorm.DB
+}
+
+// NewTransactor creates a new transactor
+func NewTransactor(db *gorm.DB) *Transactor {
+	return &Transactor{db: db}
+}
+
+// WithinTransaction calls fn with a context carrying a database transaction, which every
+// repository called with that context joins. The transaction commits when fn returns nil and
+// rolls back otherwise. Called inside a transaction, fn simply joins it.
+func (t *Transactor) WithinTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(txKey{}).(*txState); ok {
+		return fn(ctx)
+	}
+
+	state := &txState{}
+	err := t.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		state.tx = tx
+		return fn(context.WithValue(ctx, txKey{}, state))
+	})
+	if err != nil {
+		return err
+	}
+	for _, f := range state.afterCommit {
+		f()
+	}
+	return nil
+}
+
+// AfterCommit calls f once the transaction in ctx commits, or right away outside a transaction.
+// f is not called when the transaction rolls back.
+func AfterCommit(ctx context.Context, f func()) {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		state.afterCommit = append(state.afterCommit, f)
+		return
+	}
+	f()
+}
+
+// conn returns the database transaction in ctx, or db outside a transaction
+func conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if state, ok := ctx.Value(txKey{}).(*txState); ok {
+		return state.tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
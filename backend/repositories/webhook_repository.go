@@ -0,0 +1,178 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// WebhookDeliveryFilter narrows a listing of an endpoint's deliveries
+type WebhookDeliveryFilter struct {
+	Status models.WebhookDeliveryStatus
+	Event  string
+	Limit  int
+	Offset int
+}
+
+// WebhookRepository handles webhook endpoints and their deliveries
+type WebhookRepository struct {
+	db *gorm.DB
+}
+
+// NewWebhookRepository creates a new webhook repository
+func NewWebhookRepository(db *gorm.DB) *WebhookRepository {
+	return &WebhookRepository{db: db}
+}
+
+// CreateEndpoint creates a webhook endpoint
+func (r *WebhookRepository) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	return conn(ctx, r.db).Create(endpoint).Error
+}
+
+// GetEndpointByID retrieves a webhook endpoint by ID
+func (r *WebhookRepository) GetEndpointByID(ctx context.Context, id uint) (*models.WebhookEndpoint, error) {
+	var endpoint models.WebhookEndpoint
+	err := conn(ctx, r.db).Where("id = ?", id).First(&endpoint).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &endpoint, nil
+}
+
+// GetEndpoints retrieves every webhook endpoint
+func (r *WebhookRepository) GetEndpoints(ctx context.Context) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := conn(ctx, r.db).Order("id ASC").Find(&endpoints).Error
+	return endpoints, err
+}
+
+// GetSubscribedEndpoints retrieves the active endpoints subscribed to event
+func (r *WebhookRepository) GetSubscribedEndpoints(ctx context.Context, event string) ([]models.WebhookEndpoint, error) {
+	var endpoints []models.WebhookEndpoint
+	err := conn(ctx, r.db).
+		Where("is_active = ? AND JSON_CONTAINS(events, JSON_QUOTE(?))", true, event).
+		Find(&endpoints).Error
+	return endpoints, err
+}
+
+// UpdateEndpoint saves a webhook endpoint
+func (r *WebhookRepository) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	return conn(ctx, r.db).Save(endpoint).Error
+}
+
+// DeleteEndpoint deletes a webhook endpoint together with its delivery log
+func (r *WebhookRepository) DeleteEndpoint(ctx context.Context, id uint) error {
+	return conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("endpoint_id = ?", id).Delete(&models.WebhookDelivery{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&models.WebhookEndpoint{}, id).Error
+	})
+}
+
+// CreateDeliveries queues deliveries
+func (r *WebhookRepository) CreateDeliveries(ctx context.Context, deliveries []models.WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	return conn(ctx, r.db).Omit(clause.Associations).Create(&deliveries).Error
+}
+
+// ClaimDue returns up to limit pending deliveries due at now, with their endpoint, and hides
+// them from other claims for lease, counting the attempt. Rows claimed elsewhere are skipped.
+func (r *WebhookRepository) ClaimDue(ctx context.Context, now time.Time, lease time.Duration, limit int) ([]models.WebhookDelivery, error) {
+	var claimed []models.WebhookDelivery
+	err := conn(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+			Order("next_attempt_at ASC").Limit(limit).Find(&claimed).Error; err != nil {
+			return err
+		}
+		if len(claimed) == 0 {
+			return nil
+		}
+
+		ids := make([]uint, len(claimed))
+		for i := range claimed {
+			ids[i] = claimed[i].ID
+			claimed[i].Attempts++
+		}
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).Updates(map[string]any{
+			"attempts":        gorm.Expr("attempts + 1"),
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	if err != nil || len(claimed) == 0 {
+		return claimed, err
+	}
+
+	// Attach the endpoints, which may have been deleted meanwhile
+	endpointIDs := make([]uint, len(claimed))
+	for i := range claimed {
+		endpointIDs[i] = claimed[i].EndpointID
+	}
+	var endpoints []models.WebhookEndpoint
+	if err := conn(ctx, r.db).Where("id IN ?", endpointIDs).Find(&endpoints).Error; err != nil {
+		return nil, err
+	}
+	byID := make(map[uint]*models.WebhookEndpoint, len(endpoints))
+	for i := range endpoints {
+		byID[endpoints[i].ID] = &endpoints[i]
+	}
+	for i := range claimed {
+		claimed[i].Endpoint = byID[claimed[i].EndpointID]
+	}
+	return claimed, nil
+}
+
+// RecordAttempt stores the outcome of a delivery attempt
+func (r *WebhookRepository) RecordAttempt(ctx context.Context, delivery *models.WebhookDelivery) error {
+	return conn(ctx, r.db).Model(&models.WebhookDelivery{}).Where("id = ?", delivery.ID).Updates(map[string]any{
+		"status":           delivery.Status,
+		"next_attempt_at":  delivery.NextAttemptAt,
+		"response_status":  delivery.ResponseStatus,
+		"response_body":    delivery.ResponseBody,
+		"response_time_ms": delivery.ResponseTimeMs,
+		"last_error":       delivery.LastError,
+		"delivered_at":     delivery.DeliveredAt,
+	}).Error
+}
+
+// GetDelivery retrieves a delivery of an endpoint
+func (r *WebhookRepository) GetDelivery(ctx context.Context, endpointID, id uint) (*models.WebhookDelivery, error) {
+	var delivery models.WebhookDelivery
+	err := conn(ctx, r.db).Where("id = ? AND endpoint_id = ?", id, endpointID).First(&delivery).Error
+	if err == gorm.ErrRecordNotFound {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &delivery, nil
+}
+
+// ListDeliveries retrieves the deliveries of an endpoint matching the filter, latest first, and their total count
+func (r *WebhookRepository) ListDeliveries(ctx context.Context, endpointID uint, filter WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	query := conn(ctx, r.db).Model(&models.WebhookDelivery{}).Where("endpoint_id = ?", endpointID)
+	if filter.Status != "" {
+		query = query.Where("status = ?", filter.Status)
+	}
+	if filter.Event != "" {
+		query = query.Where("event = ?", filter.Event)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var deliveries []models.WebhookDelivery
+	err := query.Order("id DESC").Limit(filter.Limit).Offset(filter.Offset).Find(&deliveries).Error
+	return deliveries, total, err
+}
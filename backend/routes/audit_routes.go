@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// AuditRoutes registers the audit log endpoints, open to owners only
+func AuditRoutes(rg *gin.RouterGroup, controller *controllers.AuditController, jwtManager *utils.JWTManager) {
+	audit := rg.Group("/audit-logs")
+	audit.Use(middlewares.AuthMiddleware(jwtManager), middlewares.RequireRole(models.RoleOwner))
+
+	audit.GET("", controller.ListAuditLogs)
+}
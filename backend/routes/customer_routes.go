@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// CustomerRoutes registers the customer portal. Customers log in with a code sent to their
+// phone and only ever see their own orders; admin tokens are not accepted.
+func CustomerRoutes(rg *gin.RouterGroup, controller *controllers.CustomerController, jwtManager *utils.JWTManager, loginLimit gin.HandlerFunc) {
+	customer := rg.Group("/customer")
+
+	auth := customer.Group("/auth")
+	auth.Use(loginLimit)
+	auth.POST("/request-code", controller.RequestLoginCode)
+	auth.POST("/verify", controller.VerifyLoginCode)
+
+	portal := customer.Group("")
+	portal.Use(middlewares.CustomerAuthMiddleware(jwtManager))
+	portal.GET("/profile", controller.GetProfile)
+	portal.PUT("/address", controller.UpdateAddress)
+	portal.GET("/orders", controller.ListOrders)
+	portal.GET("/orders/:code/receipt", controller.DownloadReceipt)
+}
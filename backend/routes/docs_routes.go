@@ -0,0 +1,12 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// DocsRoutes registers Swagger UI and the OpenAPI document
+func DocsRoutes(rg *gin.RouterGroup, docsController *controllers.DocsController) {
+	rg.GET("/docs", docsController.SwaggerUI)
+	rg.GET("/docs/openapi.yaml", docsController.OpenAPISpec)
+}
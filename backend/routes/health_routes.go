@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/gin-gonic/gin"
+)
+
+// HealthRoutes registers the load balancer probes outside the /api prefix
+func HealthRoutes(router *gin.Engine, healthController *controllers.HealthController) {
+	router.GET("/healthz", healthController.Healthz)
+	router.GET("/readyz", healthController.Readyz)
+}
+
+// MetricsRoutes registers the Prometheus scrape endpoint outside the /api prefix
+func MetricsRoutes(router *gin.Engine, metricsController *controllers.MetricsController) {
+	router.GET("/metrics", metricsController.Metrics)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// NotificationRoutes registers the customer notification outbox endpoints
+func NotificationRoutes(rg *gin.RouterGroup, controller *controllers.NotificationController, jwtManager *utils.JWTManager) {
+	notifications := rg.Group("/notifications")
+	notifications.Use(middlewares.AuthMiddleware(jwtManager))
+
+	notifications.GET("", controller.ListNotifications)
+	notifications.POST("/:id/retry", controller.RetryNotification)
+}
@@ -0,0 +1,22 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ReportRoutes registers the revenue and operations reports
+func ReportRoutes(rg *gin.RouterGroup, controller *controllers.ReportController, jwtManager *utils.JWTManager) {
+	reports := rg.Group("/reports")
+	reports.Use(middlewares.AuthMiddleware(jwtManager))
+
+	reports.GET("/summary", controller.GetSummary)
+	reports.GET("/revenue", controller.GetRevenueByPeriod)
+	reports.GET("/revenue/services", controller.GetRevenueByService)
+	reports.GET("/revenue/items", controller.GetRevenueByItem)
+	reports.GET("/revenue/admins", controller.GetRevenueByAdmin)
+	reports.GET("/turnaround", controller.GetTurnaround)
+	reports.GET("/unpaid-aging", controller.GetUnpaidAging)
+}
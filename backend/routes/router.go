@@ -1,44 +1,89 @@
 package routes
 
 import (
+	"log/slog"
 	"time"
 
+	"github.com/RidwanRamdhani/chronos-laundry/backend/config"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
 )
 
-func SetupRouter(
-	authController *controllers.AuthController,
-	transactionController *controllers.TransactionController,
-	servicePriceController *controllers.ServicePriceController,
-) *gin.Engine {
+// Controllers groups the handlers wired into the router
+type Controllers struct {
+	Auth         *controllers.AuthController
+	Transaction  *controllers.TransactionController
+	ServicePrice *controllers.ServicePriceController
+	Report       *controllers.ReportController
+	Shift        *controllers.ShiftController
+	Notification *controllers.NotificationController
+	Webhook      *controllers.WebhookController
+	Stream       *controllers.StreamController
+	Audit        *controllers.AuditController
+	Trash        *controllers.TrashController
+	Customer     *controllers.CustomerController
+	Health       *controllers.HealthController
+	Metrics      *controllers.MetricsController
+	Docs         *controllers.DocsController
+}
+
+// RateLimits holds the rate limiting middlewares, created once so every API version shares the
+// same counts
+type RateLimits struct {
+	Tracking      gin.HandlerFunc // public tracking, per client IP
+	CustomerLogin gin.HandlerFunc // customer portal login, per client IP
+}
+
+// SetupRouter builds the gin engine with global middlewares, operational endpoints and every API version
+func SetupRouter(cfg *config.Config, jwtManager *utils.JWTManager, c Controllers) *gin.Engine {
+
+	gin.SetMode(cfg.Server.Mode)
+	r := gin.New()
 
-	r := gin.Default()
+	// Client IPs, used for rate limits and the audit log, only come from forwarding headers set
+	// by trusted proxies. The addresses were checked by config.Validate.
+	r.SetTrustedProxies(cfg.Server.TrustedProxies)
+
+	// Request IDs first so access logs, panics and responses all carry them
+	r.Use(middlewares.RequestIDMiddleware())
+	r.Use(middlewares.LoggerMiddleware(slog.Default()))
+	r.Use(middlewares.RecoveryMiddleware(slog.Default()))
+	r.Use(middlewares.MetricsMiddleware())
+
+	// Deadline for handlers, cancelling their database queries when exceeded
+	r.Use(middlewares.TimeoutMiddleware(cfg.Server.RequestTimeout))
+
+	// Translate errors attached with c.Error into the standard error response
+	r.Use(middlewares.ErrorMiddleware())
 
 	// Disable automatic trailing slash redirect to prevent CORS issues
 	r.RedirectTrailingSlash = false
 
 	// CORS Middleware
 	r.Use(cors.New(cors.Config{
-		AllowOrigins:     []string{"http://localhost:5173"},
+		AllowOrigins:     cfg.Server.AllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization"},
-		ExposeHeaders:    []string{"Content-Length"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", middlewares.RequestIDHeader},
+		ExposeHeaders:    []string{"Content-Length", middlewares.RequestIDHeader, "Deprecation", "Sunset", "Link", "Retry-After"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	api := r.Group("/api")
-
-	// Auth
-	AuthRoutes(api, authController)
+	// Liveness and readiness probes
+	HealthRoutes(r, c.Health)
 
-	// Transactions
-	TransactionRoutes(api, transactionController)
+	// Prometheus metrics
+	MetricsRoutes(r, c.Metrics)
 
-	// Service Prices
-	SetupServicePriceRoutes(r, servicePriceController)
+	// Versioned API under /api/v1, plus deprecated unversioned aliases
+	limits := RateLimits{
+		Tracking:      middlewares.RateLimitMiddleware(cfg.Tracking.RateLimit, cfg.Tracking.RateWindow),
+		CustomerLogin: middlewares.RateLimitMiddleware(cfg.Customers.RateLimit, cfg.Customers.RateWindow),
+	}
+	mountAPIVersions(r.Group("/api"), cfg, jwtManager, limits, c)
 
 	return r
 }
@@ -0,0 +1,109 @@
+package routes
+
+import (
+	"regexp"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/config"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/docs"
+	"github.com/goccy/go-yaml"
+)
+
+// openAPIMethods are the operation keys of an OpenAPI path item
+var openAPIMethods = map[string]bool{
+	"get": true, "put": true, "post": true, "delete": true,
+	"options": true, "head": true, "patch": true, "trace": true,
+}
+
+// ginParam matches gin path parameters such as :id
+var ginParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// TestRoutesMatchOpenAPISpec fails when a route is registered without being documented,
+// or documented without being registered
+func TestRoutesMatchOpenAPISpec(t *testing.T) {
+	cfg := config.Default()
+	cfg.Server.Mode = "test"
+
+	// Handlers are only referenced, never called, so nil controllers are enough
+	r := SetupRouter(cfg, nil, Controllers{})
+
+	// Deprecated aliases are not documented separately, they mirror their successor
+	registered := make(map[string]bool)
+	var aliases []string
+	for _, route := range r.Routes() {
+		path := ginParam.ReplaceAllString(route.Path, "{$1}")
+		if isDeprecatedAlias(path) {
+			aliases = append(aliases, route.Method+" "+path)
+			continue
+		}
+		registered[route.Method+" "+path] = true
+	}
+
+	var spec struct {
+		Paths map[string]map[string]any `yaml:"paths"`
+	}
+	if err := yaml.Unmarshal(docs.OpenAPISpec, &spec); err != nil {
+		t.Fatalf("failed to parse openapi.yaml: %v", err)
+	}
+	documented := make(map[string]bool)
+	for path, item := range spec.Paths {
+		for key := range item {
+			if openAPIMethods[key] {
+				documented[strings.ToUpper(key)+" "+path] = true
+			}
+		}
+	}
+
+	for _, route := range missing(registered, documented) {
+		t.Errorf("route %s is registered but missing from docs/openapi.yaml", route)
+	}
+	for _, route := range missing(documented, registered) {
+		t.Errorf("route %s is documented in docs/openapi.yaml but not registered", route)
+	}
+	for _, alias := range aliases {
+		if successor := successorOf(alias); !registered[successor] {
+			t.Errorf("deprecated alias %s has no successor %s", alias, successor)
+		}
+	}
+}
+
+// isDeprecatedAlias reports whether path belongs to a deprecated API version
+// and not to one that is still current
+func isDeprecatedAlias(path string) bool {
+	deprecated := false
+	for _, version := range apiVersions {
+		prefix := "/api" + version.prefix + "/"
+		if !strings.HasPrefix(path, prefix) {
+			continue
+		}
+		if version.deprecation == nil {
+			return false
+		}
+		deprecated = true
+	}
+	return deprecated
+}
+
+// successorOf maps a deprecated alias route to the route replacing it
+func successorOf(route string) string {
+	for _, version := range apiVersions {
+		if d := version.deprecation; d != nil {
+			route = strings.Replace(route, " "+d.Prefix+"/", " "+d.Successor+"/", 1)
+		}
+	}
+	return route
+}
+
+// missing returns the keys of want that are not in have, sorted
+func missing(want, have map[string]bool) []string {
+	var result []string
+	for key := range want {
+		if !have[key] {
+			result = append(result, key)
+		}
+	}
+	sort.Strings(result)
+	return result
+}
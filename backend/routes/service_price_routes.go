@@ -3,34 +3,43 @@ package routes
 import (
 	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
-// SetupServicePriceRoutes sets up service price routes
-func SetupServicePriceRoutes(router *gin.Engine, servicePriceController *controllers.ServicePriceController) {
+// ServicePriceRoutes sets up service price routes
+func ServicePriceRoutes(rg *gin.RouterGroup, servicePriceController *controllers.ServicePriceController, jwtManager *utils.JWTManager) {
+	// Get all service types (for dropdown)
+	rg.GET("/service-types", servicePriceController.GetServiceTypes)
+
+	sp := rg.Group("/service-prices")
+
 	// Public routes (no authentication required)
-	public := router.Group("/api")
+	public := sp.Group("")
 	{
-		// Get all service types (for dropdown)
-		public.GET("/service-types", servicePriceController.GetServiceTypes)
-
 		// Get all service prices
-		public.GET("/service-prices", servicePriceController.GetAllServicePrices)
+		public.GET("", servicePriceController.GetAllServicePrices)
 
 		// Get service prices by type (for dropdown based on selected service type)
-		public.GET("/service-prices/by-type", servicePriceController.GetServicePricesByType)
+		public.GET("/by-type", servicePriceController.GetServicePricesByType)
 
 		// Get single service price
-		public.GET("/service-prices/:id", servicePriceController.GetServicePrice)
+		public.GET("/:id", servicePriceController.GetServicePrice)
 	}
 
 	// Protected routes (authentication required)
-	protected := router.Group("/api/service-prices")
-	protected.Use(middlewares.AuthMiddleware())
+	protected := sp.Group("")
+	protected.Use(middlewares.AuthMiddleware(jwtManager))
 	{
 		// Create service price
 		protected.POST("", servicePriceController.CreateServicePrice)
 
+		// Export the full catalog as CSV or JSON
+		protected.GET("/export", servicePriceController.ExportServicePrices)
+
+		// Import a catalog file (dry_run=true previews the diff)
+		protected.POST("/import", servicePriceController.ImportServicePrices)
+
 		// Update service price
 		protected.PUT("/:id", servicePriceController.UpdateServicePrice)
 
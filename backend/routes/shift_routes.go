@@ -0,0 +1,20 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// ShiftRoutes registers the cashier shift endpoints
+func ShiftRoutes(rg *gin.RouterGroup, controller *controllers.ShiftController, jwtManager *utils.JWTManager) {
+	shifts := rg.Group("/shifts")
+	shifts.Use(middlewares.AuthMiddleware(jwtManager))
+
+	shifts.POST("", controller.OpenShift)
+	shifts.GET("", controller.ListShifts)
+	shifts.GET("/current", controller.GetCurrentShift)
+	shifts.POST("/current/close", controller.CloseCurrentShift)
+	shifts.GET("/:id", controller.GetShift)
+}
@@ -0,0 +1,17 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// StreamRoutes registers the live event streams. They stay open, so they have no deadline.
+func StreamRoutes(rg *gin.RouterGroup, controller *controllers.StreamController, jwtManager *utils.JWTManager, trackingLimit gin.HandlerFunc) {
+	rg.GET("/transactions/stream",
+		middlewares.EventStreamAuthMiddleware(jwtManager), middlewares.ExtendedTimeoutMiddleware(0), controller.StreamBoard)
+
+	// Public tracking (tanpa auth)
+	rg.GET("/track/:code/stream", trackingLimit, middlewares.ExtendedTimeoutMiddleware(0), controller.StreamTracking)
+}
@@ -1,19 +1,24 @@
 package routes
 
 import (
+	"time"
+
 	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
 	"github.com/gin-gonic/gin"
 )
 
-func TransactionRoutes(rg *gin.RouterGroup, controller *controllers.TransactionController) {
+func TransactionRoutes(rg *gin.RouterGroup, controller *controllers.TransactionController, jwtManager *utils.JWTManager, exportTimeout time.Duration, trackingLimit gin.HandlerFunc) {
 	tr := rg.Group("/transactions")
-	tr.Use(middlewares.AuthMiddleware())
+	tr.Use(middlewares.AuthMiddleware(jwtManager))
 
 	// CRUD + dashboard
 	tr.POST("", controller.CreateTransaction)
 	tr.GET("", controller.GetAllTransactions)
 	tr.GET("/dashboard", controller.GetDashboard)
+	tr.GET("/search", controller.SearchTransactions)
+	tr.GET("/export", middlewares.ExtendedTimeoutMiddleware(exportTimeout), controller.ExportTransactions)
 
 	tr.GET("/:id", controller.GetTransaction)
 	tr.PUT("/:id", controller.UpdateTransaction)
@@ -22,6 +27,14 @@ func TransactionRoutes(rg *gin.RouterGroup, controller *controllers.TransactionC
 	// Update status
 	tr.PUT("/:id/status", controller.UpdateTransactionStatus)
 
-	// Public tracking (tanpa auth)
-	rg.GET("/track/:code", controller.TrackTransaction)
+	// Record payment
+	tr.POST("/:id/payments", controller.RecordPayment)
+
+	// Edit items, repricing from the catalog
+	tr.POST("/:id/items", controller.AddTransactionItem)
+	tr.PUT("/:id/items/:item_id", controller.UpdateTransactionItem)
+	tr.DELETE("/:id/items/:item_id", controller.RemoveTransactionItem)
+
+	// Public tracking (tanpa auth), rate limited against code guessing
+	rg.GET("/track/:code", trackingLimit, controller.TrackTransaction)
 }
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// TrashRoutes registers the trash bins of deleted transactions and service prices. Any admin may
+// restore; purging for good is left to owners.
+func TrashRoutes(rg *gin.RouterGroup, controller *controllers.TrashController, jwtManager *utils.JWTManager) {
+	transactions := rg.Group("/transactions/trash")
+	transactions.Use(middlewares.AuthMiddleware(jwtManager))
+	transactions.GET("", controller.ListTransactions)
+	transactions.POST("/:id/restore", controller.RestoreTransaction)
+	transactions.DELETE("/:id", middlewares.RequireRole(models.RoleOwner), controller.PurgeTransaction)
+
+	prices := rg.Group("/service-prices/trash")
+	prices.Use(middlewares.AuthMiddleware(jwtManager))
+	prices.GET("", controller.ListServicePrices)
+	prices.POST("/:id/restore", controller.RestoreServicePrice)
+	prices.DELETE("/:id", middlewares.RequireRole(models.RoleOwner), controller.PurgeServicePrice)
+}
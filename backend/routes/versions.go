@@ -0,0 +1,89 @@
+package routes
+
+import (
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/config"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// apiVersion mounts one version of the route tree below /api.
+//
+// Breaking changes go into a new version: add a registerV2 that reuses the v1 route functions
+// for unchanged resources and mounts the new handlers (e.g. TransactionRoutesV2) for the changed
+// ones, append {prefix: "/v2", register: registerV2} below, and give the v1 entry a deprecation
+// once clients have moved.
+type apiVersion struct {
+	prefix      string
+	register    func(rg *gin.RouterGroup, cfg *config.Config, jwtManager *utils.JWTManager, limits RateLimits, c Controllers)
+	deprecation *middlewares.Deprecation
+}
+
+// apiVersions lists every mounted version, including deprecated aliases
+var apiVersions = []apiVersion{
+	{prefix: "/v1", register: registerV1},
+
+	// The unversioned /api paths predate /api/v1 and are served as aliases until the sunset date
+	{
+		prefix:   "",
+		register: registerV1,
+		deprecation: &middlewares.Deprecation{
+			DeprecatedAt: time.Date(2026, time.October, 19, 0, 0, 0, 0, time.UTC),
+			Sunset:       time.Date(2027, time.April, 30, 0, 0, 0, 0, time.UTC),
+			Prefix:       "/api",
+			Successor:    "/api/v1",
+		},
+	},
+}
+
+// mountAPIVersions registers every API version on the /api group
+func mountAPIVersions(api *gin.RouterGroup, cfg *config.Config, jwtManager *utils.JWTManager, limits RateLimits, c Controllers) {
+	for _, version := range apiVersions {
+		group := api.Group(version.prefix)
+		if version.deprecation != nil {
+			group.Use(middlewares.DeprecationMiddleware(*version.deprecation))
+		}
+		version.register(group, cfg, jwtManager, limits, c)
+	}
+}
+
+// registerV1 registers the v1 route tree
+func registerV1(rg *gin.RouterGroup, cfg *config.Config, jwtManager *utils.JWTManager, limits RateLimits, c Controllers) {
+	// OpenAPI document and Swagger UI
+	DocsRoutes(rg, c.Docs)
+
+	// Auth
+	AuthRoutes(rg, c.Auth)
+
+	// Transactions and public tracking
+	TransactionRoutes(rg, c.Transaction, jwtManager, cfg.Server.ExportTimeout, limits.Tracking)
+
+	// Service prices
+	ServicePriceRoutes(rg, c.ServicePrice, jwtManager)
+
+	// Cashier shifts
+	ShiftRoutes(rg, c.Shift, jwtManager)
+
+	// Customer notification outbox
+	NotificationRoutes(rg, c.Notification, jwtManager)
+
+	// Partner webhooks
+	WebhookRoutes(rg, c.Webhook, jwtManager)
+
+	// Live order board and tracking streams
+	StreamRoutes(rg, c.Stream, jwtManager, limits.Tracking)
+
+	// Revenue and operations reports
+	ReportRoutes(rg, c.Report, jwtManager)
+
+	// Audit log
+	AuditRoutes(rg, c.Audit, jwtManager)
+
+	// Trash bins of deleted transactions and service prices
+	TrashRoutes(rg, c.Trash, jwtManager)
+
+	// Customer portal
+	CustomerRoutes(rg, c.Customer, jwtManager, limits.CustomerLogin)
+}
@@ -0,0 +1,26 @@
+package routes
+
+import (
+	"github.com/RidwanRamdhani/chronos-laundry/backend/controllers"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/middlewares"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/gin-gonic/gin"
+)
+
+// WebhookRoutes registers the partner webhook endpoints
+func WebhookRoutes(rg *gin.RouterGroup, controller *controllers.WebhookController, jwtManager *utils.JWTManager) {
+	webhooks := rg.Group("/webhooks")
+	webhooks.Use(middlewares.AuthMiddleware(jwtManager))
+
+	webhooks.GET("", controller.ListWebhooks)
+	webhooks.POST("", controller.CreateWebhook)
+	webhooks.GET("/:id", controller.GetWebhook)
+	webhooks.PUT("/:id", controller.UpdateWebhook)
+	webhooks.DELETE("/:id", controller.DeleteWebhook)
+	webhooks.POST("/:id/rotate-secret", controller.RotateWebhookSecret)
+
+	// Delivery log
+	webhooks.GET("/:id/deliveries", controller.ListWebhookDeliveries)
+	webhooks.GET("/:id/deliveries/:delivery_id", controller.GetWebhookDelivery)
+	webhooks.POST("/:id/deliveries/:delivery_id/redeliver", controller.RedeliverWebhook)
+}
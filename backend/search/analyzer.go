@@ -0,0 +1,111 @@
+// Package search tokenizes text into normalized terms and matches query terms
+// against them by exact, prefix or typo-tolerant comparison.
+package search
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxTermLength is the longest term stored in the index, longer tokens are truncated
+const MaxTermLength = 64
+
+// Token is a normalized term and the byte range of the text it was read from
+type Token struct {
+	Term  string
+	Start int
+	End   int
+}
+
+// Tokenize splits text into letter and digit runs and normalizes each one
+func Tokenize(text string) []Token {
+	var tokens []Token
+	start := -1
+	for i, r := range text {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			tokens = appendToken(tokens, text, start, i)
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = appendToken(tokens, text, start, len(text))
+	}
+	return tokens
+}
+
+func appendToken(tokens []Token, text string, start, end int) []Token {
+	if term := Normalize(text[start:end]); term != "" {
+		tokens = append(tokens, Token{Term: term, Start: start, End: end})
+	}
+	return tokens
+}
+
+// Terms returns the distinct normalized terms of text
+func Terms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, token := range Tokenize(text) {
+		if !seen[token.Term] {
+			seen[token.Term] = true
+			terms = append(terms, token.Term)
+		}
+	}
+	return terms
+}
+
+// Normalize lowercases a word, strips diacritics so "José" matches "jose",
+// and truncates it to MaxTermLength runes
+func Normalize(word string) string {
+	var b strings.Builder
+	length := 0
+	for _, r := range norm.NFD.String(word) {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if length == MaxTermLength {
+			break
+		}
+		b.WriteRune(unicode.ToLower(r))
+		length++
+	}
+	return b.String()
+}
+
+// PhoneTerms returns the digits of a phone number in the international and the local
+// form, so "+62 812-3456" is found by both "62812" and "0812"
+func PhoneTerms(phone string) []string {
+	digits := PhoneDigits(phone)
+	if digits == "" {
+		return nil
+	}
+	terms := []string{digits}
+	if local, ok := strings.CutPrefix(digits, "62"); ok && local != "" {
+		terms = append(terms, "0"+local)
+	}
+	return terms
+}
+
+// PhoneDigits returns the digits of text when it looks like a phone number,
+// that is digits with only spaces, dashes, dots, parentheses and a leading plus in between
+func PhoneDigits(text string) string {
+	var b strings.Builder
+	for i, r := range strings.TrimSpace(text) {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case r == ' ' || r == '-' || r == '.' || r == '(' || r == ')':
+		case r == '+' && i == 0:
+		default:
+			return ""
+		}
+	}
+	return b.String()
+}
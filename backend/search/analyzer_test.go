@@ -0,0 +1,60 @@
+package search
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestTerms(t *testing.T) {
+	tests := []struct {
+		text string
+		want []string
+	}{
+		{"", nil},
+		{"Ridwan Ramdhani", []string{"ridwan", "ramdhani"}},
+		{"  José, jose; JOSÉ!", []string{"jose"}},
+		{"Jl. Merdeka No.10", []string{"jl", "merdeka", "no", "10"}},
+		{"LDR-2024-0001", []string{"ldr", "2024", "0001"}},
+		{"--- ...", nil},
+	}
+	for _, tt := range tests {
+		if got := Terms(tt.text); !slices.Equal(got, tt.want) {
+			t.Errorf("Terms(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestTokenizeOffsets(t *testing.T) {
+	text := "Bu José"
+	for _, token := range Tokenize(text) {
+		if Normalize(text[token.Start:token.End]) != token.Term {
+			t.Errorf("token %q spans %q", token.Term, text[token.Start:token.End])
+		}
+	}
+}
+
+func TestNormalizeTruncates(t *testing.T) {
+	if got := Normalize(strings.Repeat("é", MaxTermLength+5)); got != strings.Repeat("e", MaxTermLength) {
+		t.Errorf("Normalize kept %d characters, want %d", len([]rune(got)), MaxTermLength)
+	}
+}
+
+func TestPhoneTerms(t *testing.T) {
+	tests := []struct {
+		phone string
+		want  []string
+	}{
+		{"+62 812-3456", []string{"628123456", "08123456"}},
+		{"(0812) 3456.789", []string{"08123456789"}},
+		{"62", []string{"62"}},
+		{"", nil},
+		{"0812 ext 5", nil},
+		{"0812+5", nil}, // a plus only at the start
+	}
+	for _, tt := range tests {
+		if got := PhoneTerms(tt.phone); !slices.Equal(got, tt.want) {
+			t.Errorf("PhoneTerms(%q) = %q, want %q", tt.phone, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,28 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+// Highlight HTML-escapes text and wraps every token accepted by matches in <mark> tags.
+// It returns an empty string when no token matches.
+func Highlight(text string, matches func(term string) bool) string {
+	var b strings.Builder
+	last, marked := 0, false
+	for _, token := range Tokenize(text) {
+		if !matches(token.Term) {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[last:token.Start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[token.Start:token.End]))
+		b.WriteString("</mark>")
+		last, marked = token.End, true
+	}
+	if !marked {
+		return ""
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+	return b.String()
+}
@@ -0,0 +1,25 @@
+package search
+
+import "testing"
+
+func TestHighlight(t *testing.T) {
+	tests := []struct {
+		text  string
+		query string
+		want  string
+	}{
+		{"Ridwan Ramdhani", "Ridwa", "<mark>Ridwan</mark> Ramdhani"},
+		{"Ridwan Ramdhani", "ramdhnai", "Ridwan <mark>Ramdhani</mark>"},
+		{"José & Ridwan", "jose", "<mark>José</mark> &amp; Ridwan"},
+		{"<b>Budi</b>", "budi", "&lt;b&gt;<mark>Budi</mark>&lt;/b&gt;"},
+		{"Ridwan Ramdhani", "budi", ""},
+	}
+	for _, tt := range tests {
+		got := Highlight(tt.text, func(term string) bool {
+			return MatchTerm(Normalize(tt.query), term).Kind != NoMatch
+		})
+		if got != tt.want {
+			t.Errorf("Highlight(%q) for %q = %q, want %q", tt.text, tt.query, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,119 @@
+package search
+
+import "strings"
+
+// MatchKind describes how a query term matched an indexed term
+type MatchKind int
+
+const (
+	NoMatch MatchKind = iota
+	FuzzyMatch
+	PrefixMatch
+	ExactMatch
+)
+
+// Match is the outcome of comparing a query term with an indexed term
+type Match struct {
+	Kind     MatchKind
+	Distance int // edits needed for a fuzzy match
+}
+
+// Score rates a match between 0 and 1, exact matches highest and fuzzy matches by distance
+func (m Match) Score() float64 {
+	switch m.Kind {
+	case ExactMatch:
+		return 1
+	case PrefixMatch:
+		return 0.75
+	case FuzzyMatch:
+		return 0.6 - 0.15*float64(m.Distance)
+	default:
+		return 0
+	}
+}
+
+// MaxEdits returns the typos tolerated for a query term: none for short terms,
+// one from four characters and two from eight
+func MaxEdits(queryTerm string) int {
+	switch length := len([]rune(queryTerm)); {
+	case length >= 8:
+		return 2
+	case length >= 4:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// FuzzyPrefixLength is the number of leading characters a fuzzy match must share with the
+// query term. Typos rarely hit the first characters, and the shared prefix keeps the
+// candidate lookup on the term index.
+const FuzzyPrefixLength = 2
+
+// MatchTerm compares a normalized query term with a normalized indexed term
+func MatchTerm(queryTerm, term string) Match {
+	switch {
+	case queryTerm == term:
+		return Match{Kind: ExactMatch}
+	case strings.HasPrefix(term, queryTerm):
+		return Match{Kind: PrefixMatch}
+	}
+
+	maxEdits := MaxEdits(queryTerm)
+	if maxEdits == 0 {
+		return Match{}
+	}
+	q, t := []rune(queryTerm), []rune(term)
+	if len(t) < FuzzyPrefixLength || string(q[:FuzzyPrefixLength]) != string(t[:FuzzyPrefixLength]) {
+		return Match{}
+	}
+	if distance := Distance(q, t, maxEdits); distance <= maxEdits {
+		return Match{Kind: FuzzyMatch, Distance: distance}
+	}
+	return Match{}
+}
+
+// Distance returns the optimal string alignment distance between a and b: insertions,
+// deletions, substitutions and transpositions of adjacent characters each count as one edit.
+// Any result above limit is reported as limit+1.
+func Distance(a, b []rune, limit int) int {
+	if abs(len(a)-len(b)) > limit {
+		return limit + 1
+	}
+
+	// Three rows of the dynamic programming matrix are enough for transpositions
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		rowMin := curr[0]
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+			rowMin = min(rowMin, curr[j])
+		}
+		if rowMin > limit {
+			return limit + 1
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return min(prev[len(b)], limit+1)
+}
+
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
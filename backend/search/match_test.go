@@ -0,0 +1,87 @@
+package search
+
+import "testing"
+
+func TestDistance(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		limit int
+		want  int
+	}{
+		{"ridwan", "ridwan", 2, 0},
+		{"ridwan", "ridwn", 2, 1},          // deletion
+		{"ridwan", "ridwann", 2, 1},        // insertion
+		{"ridwan", "ridwen", 2, 1},         // substitution
+		{"ridwan", "ridawn", 2, 1},         // adjacent transposition
+		{"ridwan", "rdiwna", 2, 2},         // two transpositions
+		{"ridwan", "budi", 2, 3},           // above the limit
+		{"ridwan", "ridwanramdhani", 2, 3}, // length difference alone is above the limit
+		{"", "abc", 3, 3},
+		{"josé", "jose", 1, 1},
+	}
+	for _, tt := range tests {
+		if got := Distance([]rune(tt.a), []rune(tt.b), tt.limit); got != tt.want {
+			t.Errorf("Distance(%q, %q, %d) = %d, want %d", tt.a, tt.b, tt.limit, got, tt.want)
+		}
+	}
+}
+
+func TestMaxEdits(t *testing.T) {
+	tests := []struct {
+		term string
+		want int
+	}{
+		{"", 0},
+		{"abc", 0},
+		{"budi", 1},
+		{"ridwan", 1},
+		{"ramdhan", 1},
+		{"ramdhani", 2},
+		{"jéjé", 1}, // counted in characters, not bytes
+	}
+	for _, tt := range tests {
+		if got := MaxEdits(tt.term); got != tt.want {
+			t.Errorf("MaxEdits(%q) = %d, want %d", tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestMatchTerm(t *testing.T) {
+	tests := []struct {
+		query, term string
+		want        Match
+	}{
+		{"ridwan", "ridwan", Match{Kind: ExactMatch}},
+		{"ridwa", "ridwan", Match{Kind: PrefixMatch}},
+		{"rid", "ridwan", Match{Kind: PrefixMatch}},
+		{"ridwn", "ridwan", Match{Kind: FuzzyMatch, Distance: 1}},
+		{"ridawn", "ridwan", Match{Kind: FuzzyMatch, Distance: 1}},
+		{"ramdhnai", "ramdhani", Match{Kind: FuzzyMatch, Distance: 1}},
+		{"rmadhnai", "ramdhani", Match{}},                              // typo in the shared prefix
+		{"ramdahin", "ramdhani", Match{Kind: FuzzyMatch, Distance: 2}}, // two edits from eight characters
+		{"rdw", "rdx", Match{}},                                        // too short for typos
+		{"budi", "bido", Match{}},                                      // two edits from four characters
+		{"ridwan", "ridw", Match{}},                                    // the indexed term is a prefix of the query
+		{"ridwan", "r", Match{}},
+	}
+	for _, tt := range tests {
+		if got := MatchTerm(tt.query, tt.term); got != tt.want {
+			t.Errorf("MatchTerm(%q, %q) = %+v, want %+v", tt.query, tt.term, got, tt.want)
+		}
+	}
+}
+
+func TestMatchScoreOrder(t *testing.T) {
+	ranked := []Match{
+		{Kind: ExactMatch},
+		{Kind: PrefixMatch},
+		{Kind: FuzzyMatch, Distance: 1},
+		{Kind: FuzzyMatch, Distance: 2},
+		{},
+	}
+	for i := 1; i < len(ranked); i++ {
+		if ranked[i-1].Score() <= ranked[i].Score() {
+			t.Errorf("%+v scores %v, not above %+v at %v", ranked[i-1], ranked[i-1].Score(), ranked[i], ranked[i].Score())
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"gorm.io/datatypes"
+)
+
+// AuditFilter narrows a search of the audit log
+type AuditFilter = repositories.AuditFilter
+
+// auditIgnoredFields are never compared or recorded: bookkeeping, secrets, and associations
+// that are audited as entities of their own
+var auditIgnoredFields = map[string]bool{
+	"id":             true,
+	"created_at":     true,
+	"updated_at":     true,
+	"password":       true,
+	"status_history": true,
+	"payments":       true,
+}
+
+// AuditEntry is a change to record in the audit log
+type AuditEntry struct {
+	Action     models.AuditAction
+	EntityType string
+	EntityID   uint
+	Label      string
+	Before     any                 // the entity before the change, nil when it was created
+	After      any                 // the entity after the change, nil when it was deleted
+	Changes    models.AuditChanges // recorded as well, for changes the entity does not show
+}
+
+// AuditService records every change to the audit log, with who made it and from where
+type AuditService struct {
+	auditRepo *repositories.AuditRepository
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(auditRepo *repositories.AuditRepository) *AuditService {
+	return &AuditService{auditRepo: auditRepo}
+}
+
+// Record writes entries with the actor in ctx, inside the database transaction in ctx if
+// there is one, so they exist exactly when the change does. Updates that changed nothing are skipped.
+func (s *AuditService) Record(ctx context.Context, entries ...AuditEntry) error {
+	actor, ok := utils.ActorFromContext(ctx)
+	if !ok {
+		actor.Username = "system"
+	}
+	var actorID *uint
+	if actor.AdminID != 0 {
+		actorID = &actor.AdminID
+	}
+
+	rows := make([]models.AuditLog, 0, len(entries))
+	for _, entry := range entries {
+		changes, err := auditDiff(entry.Before, entry.After)
+		if err != nil {
+			return fmt.Errorf("failed to compare %s #%d: %w", entry.EntityType, entry.EntityID, err)
+		}
+		for field, change := range entry.Changes {
+			changes[field] = change
+		}
+		if len(changes) == 0 && entry.Action == models.AuditUpdate {
+			continue
+		}
+
+		rows = append(rows, models.AuditLog{
+			ActorID:     actorID,
+			ActorName:   actor.Username,
+			Action:      entry.Action,
+			EntityType:  entry.EntityType,
+			EntityID:    entry.EntityID,
+			EntityLabel: truncateLabel(entry.Label),
+			Changes:     datatypes.NewJSONType(changes),
+			IP:          actor.IP,
+			RequestID:   utils.RequestIDFromContext(ctx),
+		})
+	}
+	if err := s.auditRepo.CreateEntries(ctx, rows); err != nil {
+		return fmt.Errorf("failed to write audit log: %w", err)
+	}
+	return nil
+}
+
+// ListAuditLogs returns the audit log entries matching the filter, latest first, and their total count
+func (s *AuditService) ListAuditLogs(ctx context.Context, filter AuditFilter) ([]models.AuditLog, int64, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, utils.NewValidationError("to", "must be after from")
+	}
+	entries, total, err := s.auditRepo.ListEntries(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve audit log: %w", err)
+	}
+	return entries, total, nil
+}
+
+// auditDiff compares the JSON fields of two versions of an entity and returns the ones that
+// differ. A missing version counts as having no fields, so creations and deletions list every field.
+func auditDiff(before, after any) (models.AuditChanges, error) {
+	from, err := auditFields(before)
+	if err != nil {
+		return nil, err
+	}
+	to, err := auditFields(after)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(models.AuditChanges)
+	for field, value := range from {
+		if next, ok := to[field]; !ok || !reflect.DeepEqual(value, next) {
+			changes[field] = models.FieldChange{From: value, To: next}
+		}
+	}
+	for field, value := range to {
+		if _, ok := from[field]; !ok {
+			changes[field] = models.FieldChange{To: value}
+		}
+	}
+	return changes, nil
+}
+
+// auditFields returns the JSON fields of an entity that are audited, none for nil
+func auditFields(entity any) (map[string]any, error) {
+	fields := make(map[string]any)
+	if entity == nil || reflect.ValueOf(entity).Kind() == reflect.Pointer && reflect.ValueOf(entity).IsNil() {
+		return fields, nil
+	}
+	encoded, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(encoded, &fields); err != nil {
+		return nil, err
+	}
+	for field := range fields {
+		if auditIgnoredFields[field] {
+			delete(fields, field)
+		}
+	}
+	return fields, nil
+}
+
+// truncateLabel shortens an entity label to what the audit log stores
+func truncateLabel(label string) string {
+	const maxLabel = 255
+	if runes := []rune(label); len(runes) > maxLabel {
+		return string(runes[:maxLabel])
+	}
+	return label
+}
@@ -1,7 +1,8 @@
 package services
 
 import (
-	"errors"
+	"context"
+	"fmt"
 
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
@@ -9,40 +10,154 @@ import (
 )
 
 type AuthService struct {
-	adminRepo *repositories.AdminRepository
+	adminRepo  *repositories.AdminRepository
+	jwtManager *utils.JWTManager
+	transactor *repositories.Transactor
+	audit      *AuditService
 }
 
-func NewAuthService(adminRepo *repositories.AdminRepository) *AuthService {
-	return &AuthService{adminRepo: adminRepo}
+func NewAuthService(adminRepo *repositories.AdminRepository, jwtManager *utils.JWTManager, transactor *repositories.Transactor, audit *AuditService) *AuthService {
+	return &AuthService{adminRepo: adminRepo, jwtManager: jwtManager, transactor: transactor, audit: audit}
 }
 
-func (s *AuthService) Login(username, password string) (*models.Admin, string, error) {
+func (s *AuthService) Login(ctx context.Context, username, password string) (*models.Admin, string, error) {
 	// Retrieve admin from repository
-	admin, err := s.adminRepo.GetAdminByUsername(username)
+	admin, err := s.adminRepo.GetAdminByUsername(ctx, username)
 	if err != nil {
-		return nil, "", errors.New("failed to retrieve admin data")
+		return nil, "", fmt.Errorf("failed to retrieve admin data: %w", err)
 	}
 
 	if admin == nil {
-		return nil, "", errors.New("username not found")
+		return nil, "", utils.NewError(utils.ErrUnauthorized, "username not found")
 	}
 
 	// Verify password
 	if !utils.VerifyPassword(admin.Password, password) {
-		return nil, "", errors.New("incorrect password")
+		return nil, "", utils.NewError(utils.ErrUnauthorized, "incorrect password")
 	}
 
-	// Generate token (your utils/jwt.go)
-	token, err := utils.GenerateToken(
+	// Generate token, valid for the configured token lifetime
+	token, err := s.jwtManager.GenerateToken(
 		admin.ID,
 		admin.Username,
 		admin.Email,
 		admin.FullName,
-		6, // token valid for 6 hours
+		string(admin.Role),
 	)
 	if err != nil {
-		return nil, "", errors.New("failed to generate token")
+		return nil, "", fmt.Errorf("failed to generate token: %w", err)
 	}
 
 	return admin, token, nil
 }
+
+// CreateAdmin creates a new admin account with a hashed password and the given role
+func (s *AuthService) CreateAdmin(ctx context.Context, username, password, email, fullName string, role models.AdminRole) (*models.Admin, error) {
+	if username == "" || password == "" {
+		return nil, utils.NewValidationError("username", "username and password are required")
+	}
+	if !role.IsValid() {
+		return nil, utils.NewValidationError("role", "must be owner or staff")
+	}
+
+	existing, err := s.adminRepo.GetAdminByUsername(ctx, username)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve admin data: %w", err)
+	}
+	if existing != nil {
+		return nil, utils.NewError(utils.ErrConflict, "admin %s already exists", username)
+	}
+
+	hashedPassword, err := utils.HashPassword(password)
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	admin := &models.Admin{
+		Username: username,
+		Password: hashedPassword,
+		Email:    email,
+		FullName: fullName,
+		Role:     role,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.adminRepo.CreateAdmin(ctx, admin); err != nil {
+			return fmt.Errorf("failed to create admin: %w", err)
+		}
+		return s.audit.Record(ctx, AuditEntry{
+			Action: models.AuditCreate, EntityType: models.AuditEntityAdmin, EntityID: admin.ID, Label: admin.Username, After: admin,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return admin, nil
+}
+
+// ResetPassword replaces the password of an existing admin
+func (s *AuthService) ResetPassword(ctx context.Context, username, newPassword string) error {
+	if newPassword == "" {
+		return utils.NewValidationError("password", "password is required")
+	}
+
+	admin, err := s.adminRepo.GetAdminByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve admin data: %w", err)
+	}
+	if admin == nil {
+		return utils.NewError(utils.ErrNotFound, "username not found")
+	}
+
+	hashedPassword, err := utils.HashPassword(newPassword)
+	if err != nil {
+		return fmt.Errorf("failed to hash password: %w", err)
+	}
+
+	admin.Password = hashedPassword
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.adminRepo.UpdateAdmin(ctx, admin); err != nil {
+			return fmt.Errorf("failed to update admin: %w", err)
+		}
+		// The password is never recorded, only that it changed
+		return s.audit.Record(ctx, AuditEntry{
+			Action: models.AuditUpdate, EntityType: models.AuditEntityAdmin, EntityID: admin.ID, Label: admin.Username,
+			Changes: models.AuditChanges{"password": {From: "[redacted]", To: "[redacted]"}},
+		})
+	})
+}
+
+// SetRole changes the role of an existing admin. It applies from the admin's next login.
+func (s *AuthService) SetRole(ctx context.Context, username string, role models.AdminRole) error {
+	if !role.IsValid() {
+		return utils.NewValidationError("role", "must be owner or staff")
+	}
+
+	admin, err := s.adminRepo.GetAdminByUsername(ctx, username)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve admin data: %w", err)
+	}
+	if admin == nil {
+		return utils.NewError(utils.ErrNotFound, "username not found")
+	}
+
+	before := *admin
+	admin.Role = role
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.adminRepo.UpdateAdmin(ctx, admin); err != nil {
+			return fmt.Errorf("failed to update admin: %w", err)
+		}
+		return s.audit.Record(ctx, AuditEntry{
+			Action: models.AuditUpdate, EntityType: models.AuditEntityAdmin, EntityID: admin.ID, Label: admin.Username,
+			Before: before, After: admin,
+		})
+	})
+}
+
+// ListAdmins retrieves admins with pagination
+func (s *AuthService) ListAdmins(ctx context.Context, limit, offset int) ([]models.Admin, int64, error) {
+	admins, total, err := s.adminRepo.GetAllAdmins(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve admins: %w", err)
+	}
+	return admins, total, nil
+}
@@ -0,0 +1,331 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/notification"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// loginCodeDigits is the length of customer login codes
+const loginCodeDigits = 6
+
+// maxAddress is the longest address a customer may save, in characters
+const maxAddress = 1000
+
+// CustomerSettings configures the customer portal
+type CustomerSettings struct {
+	OTPTTL         time.Duration // how long a login code can be used
+	OTPMaxAttempts int           // tries before a login code stops working
+	OTPResend      time.Duration // minimum time between two codes for one phone number
+	TokenTTL       time.Duration
+	SendTimeout    time.Duration // deadline for sending a login code
+	Language       string        // of login codes and receipts for customers without a language of their own
+	LaundryName    string
+}
+
+// CustomerProfile is what the portal shows a customer about themselves
+type CustomerProfile struct {
+	Phone   string `json:"phone"`
+	Name    string `json:"name"`    // from their latest order
+	Address string `json:"address"` // saved in the portal, else from their latest order
+}
+
+// CustomerService lets customers log in with a one-time code sent to their phone and look after
+// their own orders. Customers are identified by their phone number in international form.
+type CustomerService struct {
+	customerRepo    *repositories.CustomerRepository
+	transactionRepo *repositories.TransactionRepository
+	transactor      *repositories.Transactor
+	events          *EventService
+	audit           *AuditService
+	jwtManager      *utils.JWTManager
+	sender          notification.Channel // delivers login codes
+	settings        CustomerSettings
+}
+
+// NewCustomerService creates a new customer service sending login codes through sender
+func NewCustomerService(
+	customerRepo *repositories.CustomerRepository,
+	transactionRepo *repositories.TransactionRepository,
+	transactor *repositories.Transactor,
+	events *EventService,
+	audit *AuditService,
+	jwtManager *utils.JWTManager,
+	sender notification.Channel,
+	settings CustomerSettings,
+) *CustomerService {
+	return &CustomerService{
+		customerRepo:    customerRepo,
+		transactionRepo: transactionRepo,
+		transactor:      transactor,
+		events:          events,
+		audit:           audit,
+		jwtManager:      jwtManager,
+		sender:          sender,
+		settings:        settings,
+	}
+}
+
+// RequestLoginCode sends a login code to a phone number that has orders. Numbers without orders
+// and requests within the resend interval are ignored without an error, so the response never
+// tells whether a number belongs to a customer.
+func (s *CustomerService) RequestLoginCode(ctx context.Context, phone string) error {
+	customerPhone, err := parseCustomerPhone(phone)
+	if err != nil {
+		return err
+	}
+
+	orders, err := s.orders(ctx, customerPhone)
+	if err != nil {
+		return err
+	}
+	if len(orders) == 0 {
+		return nil
+	}
+	current, err := s.customerRepo.GetLoginCode(ctx, customerPhone)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve login code: %w", err)
+	}
+	if current != nil && time.Since(current.CreatedAt) < s.settings.OTPResend {
+		return nil
+	}
+
+	code, err := randomDigits(loginCodeDigits)
+	if err != nil {
+		return fmt.Errorf("failed to generate login code: %w", err)
+	}
+	hash, err := utils.HashPassword(code)
+	if err != nil {
+		return fmt.Errorf("failed to hash login code: %w", err)
+	}
+	loginCode := &models.CustomerLoginCode{
+		Phone:     customerPhone,
+		CodeHash:  hash,
+		ExpiresAt: time.Now().Add(s.settings.OTPTTL),
+	}
+	if err := s.customerRepo.ReplaceLoginCode(ctx, loginCode); err != nil {
+		return fmt.Errorf("failed to store login code: %w", err)
+	}
+
+	subject, body, err := notification.RenderLoginCode(s.language(orders[0]), s.settings.LaundryName, code, s.settings.OTPTTL)
+	if err != nil {
+		return fmt.Errorf("failed to render login code: %w", err)
+	}
+	sendCtx, cancel := context.WithTimeout(ctx, s.settings.SendTimeout)
+	defer cancel()
+	msg := notification.Message{
+		To:      s.sender.Recipient(notification.Contact{Name: orders[0].CustomerName, Phone: customerPhone}),
+		Subject: subject,
+		Body:    body,
+	}
+	if err := s.sender.Send(sendCtx, msg); err != nil {
+		// Drop the code so the customer can ask again right away
+		if deleteErr := s.customerRepo.DeleteLoginCode(ctx, loginCode.ID); deleteErr != nil {
+			err = errors.Join(err, deleteErr)
+		}
+		return fmt.Errorf("failed to send login code: %w", err)
+	}
+	return nil
+}
+
+// VerifyLoginCode exchanges a login code for a customer token, returning the token and when it
+// expires. Every try counts, so a code stops working after the configured number of tries.
+func (s *CustomerService) VerifyLoginCode(ctx context.Context, phone, code string) (string, time.Time, error) {
+	customerPhone, err := parseCustomerPhone(phone)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	invalid := utils.NewError(utils.ErrUnauthorized, "the code is wrong or has expired, request a new one")
+
+	loginCode, err := s.customerRepo.GetLoginCode(ctx, customerPhone)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to retrieve login code: %w", err)
+	}
+	if loginCode == nil || loginCode.UsedAt != nil || time.Now().After(loginCode.ExpiresAt) {
+		return "", time.Time{}, invalid
+	}
+	counted, err := s.customerRepo.CountLoginCodeAttempt(ctx, loginCode.ID, s.settings.OTPMaxAttempts)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to count login attempt: %w", err)
+	}
+	if !counted || !utils.VerifyPassword(loginCode.CodeHash, code) {
+		return "", time.Time{}, invalid
+	}
+	used, err := s.customerRepo.UseLoginCode(ctx, loginCode.ID, time.Now())
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to use login code: %w", err)
+	}
+	if !used {
+		return "", time.Time{}, invalid
+	}
+
+	token, expiresAt, err := s.jwtManager.GenerateCustomerToken(customerPhone, s.settings.TokenTTL)
+	if err != nil {
+		return "", time.Time{}, fmt.Errorf("failed to generate token: %w", err)
+	}
+	return token, expiresAt, nil
+}
+
+// GetProfile retrieves the profile of a customer
+func (s *CustomerService) GetProfile(ctx context.Context, phone string) (*CustomerProfile, error) {
+	orders, err := s.orders(ctx, phone)
+	if err != nil {
+		return nil, err
+	}
+	customer, err := s.customerRepo.GetCustomerByPhone(ctx, phone)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	profile := &CustomerProfile{Phone: phone}
+	if len(orders) > 0 {
+		profile.Name = orders[0].CustomerName
+		profile.Address = orders[0].CustomerAddress
+	}
+	if customer != nil && customer.Address != "" {
+		profile.Address = customer.Address
+	}
+	return profile, nil
+}
+
+// UpdateAddress saves the address of a customer, used for their new orders without one, and
+// moves their orders that are not completed yet to it
+func (s *CustomerService) UpdateAddress(ctx context.Context, phone, address string) (*CustomerProfile, error) {
+	address = strings.TrimSpace(address)
+	if address == "" {
+		return nil, utils.NewValidationError("address", "is required")
+	}
+	if utf8.RuneCountInString(address) > maxAddress {
+		return nil, utils.NewValidationError("address", fmt.Sprintf("must be at most %d characters", maxAddress))
+	}
+
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.customerRepo.GetCustomerByPhone(ctx, phone)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve customer: %w", err)
+		}
+		customer := &models.Customer{Phone: phone}
+		entry := AuditEntry{Action: models.AuditCreate, EntityType: models.AuditEntityCustomer, Label: phone}
+		if existing != nil {
+			before := *existing
+			customer, entry.Action, entry.Before = existing, models.AuditUpdate, before
+		}
+		customer.Address = address
+		if err := s.customerRepo.SaveCustomer(ctx, customer); err != nil {
+			return fmt.Errorf("failed to save customer: %w", err)
+		}
+		entry.EntityID, entry.After = customer.ID, customer
+		entries := []AuditEntry{entry}
+
+		orders, err := s.orders(ctx, phone)
+		if err != nil {
+			return err
+		}
+		var updated []events.Event
+		for _, order := range orders {
+			if order.Status == models.StatusCompleted || order.CustomerAddress == address {
+				continue
+			}
+			if err := s.transactionRepo.UpdateCustomerAddress(ctx, order.ID, address); err != nil {
+				return fmt.Errorf("failed to update order %s: %w", order.TransactionCode, err)
+			}
+			entries = append(entries, AuditEntry{
+				Action: models.AuditUpdate, EntityType: models.AuditEntityTransaction, EntityID: order.ID, Label: order.TransactionCode,
+				Changes: models.AuditChanges{"customer_address": {From: order.CustomerAddress, To: address}},
+			})
+			order.CustomerAddress = address
+			updated = append(updated, events.TransactionUpdated{Transaction: order})
+		}
+
+		if err := s.audit.Record(ctx, entries...); err != nil {
+			return err
+		}
+		return s.events.Publish(ctx, updated...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetProfile(ctx, phone)
+}
+
+// ListOrders retrieves the orders of a customer, newest first
+func (s *CustomerService) ListOrders(ctx context.Context, phone string) ([]models.Transaction, error) {
+	return s.orders(ctx, phone)
+}
+
+// WriteReceipt writes the receipt of an order of the customer, failing when the customer has no
+// order with that code
+func (s *CustomerService) WriteReceipt(ctx context.Context, w io.Writer, phone, code string) error {
+	orders, err := s.orders(ctx, phone)
+	if err != nil {
+		return err
+	}
+	for i := range orders {
+		if orders[i].TransactionCode == code {
+			return notification.WriteReceipt(w, s.language(orders[i]), s.settings.LaundryName, &orders[i])
+		}
+	}
+	return utils.NewError(utils.ErrNotFound, "order not found")
+}
+
+// orders retrieves the orders of a customer in every form their phone number may have been
+// written in
+func (s *CustomerService) orders(ctx context.Context, phone string) ([]models.Transaction, error) {
+	orders, err := s.transactionRepo.GetTransactionsByCustomerPhone(ctx, phoneForms(phone)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve orders: %w", err)
+	}
+	return orders, nil
+}
+
+// language returns the language of messages and receipts for the customer of an order
+func (s *CustomerService) language(order models.Transaction) string {
+	if notification.IsLanguage(order.CustomerLanguage) {
+		return order.CustomerLanguage
+	}
+	return s.settings.Language
+}
+
+// parseCustomerPhone converts a phone number to the international form customers are known by
+func parseCustomerPhone(phone string) (string, error) {
+	customerPhone := notification.InternationalPhone(phone)
+	if customerPhone == "" {
+		return "", utils.NewValidationError("phone", "must be a phone number such as 08123456789")
+	}
+	return customerPhone, nil
+}
+
+// phoneForms lists the ways an Indonesian number in international form (628123456789) may
+// have been written on an order: 628123456789, +628123456789 and 08123456789
+func phoneForms(phone string) []string {
+	forms := []string{phone, "+" + phone}
+	if local, ok := strings.CutPrefix(phone, "62"); ok {
+		forms = append(forms, "0"+local)
+	}
+	return forms
+}
+
+// randomDigits returns n random decimal digits
+func randomDigits(n int) (string, error) {
+	var b strings.Builder
+	for range n {
+		digit, err := rand.Int(rand.Reader, big.NewInt(10))
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(digit.String())
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/notification"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"gorm.io/gorm"
+)
+
+const testPhone = "628123456789"
+
+// loginDB answers the queries for orders and login codes; a stored login code becomes the
+// phone's code
+type loginDB struct {
+	dryRunDB
+	orders []models.Transaction
+	code   *models.CustomerLoginCode
+}
+
+func (f *loginDB) open(t *testing.T) *gorm.DB {
+	f.query = func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *[]models.Transaction:
+			*dest = f.orders
+		case *models.CustomerLoginCode:
+			if f.code == nil {
+				tx.AddError(gorm.ErrRecordNotFound)
+				return
+			}
+			*dest = *f.code
+		}
+	}
+	f.written = func(tx *gorm.DB) {
+		if code, ok := tx.Statement.Dest.(*models.CustomerLoginCode); ok && strings.HasPrefix(tx.Statement.SQL.String(), "INSERT") {
+			stored := *code
+			stored.ID = 3
+			f.code = &stored
+		}
+	}
+	return f.dryRunDB.open(t)
+}
+
+// loginSender is a notification channel recording the messages it is asked to send
+type loginSender struct {
+	err  error
+	sent []notification.Message
+}
+
+func (s *loginSender) Name() string                                  { return "test" }
+func (s *loginSender) Recipient(contact notification.Contact) string { return contact.Phone }
+func (s *loginSender) Send(_ context.Context, msg notification.Message) error {
+	s.sent = append(s.sent, msg)
+	return s.err
+}
+
+func newLoginService(t *testing.T, f *loginDB, sender *loginSender) *CustomerService {
+	db := f.open(t)
+	return NewCustomerService(repositories.NewCustomerRepository(db), repositories.NewTransactionRepository(db),
+		nil, nil, nil, utils.NewJWTManager("secret", time.Hour), sender, CustomerSettings{
+			OTPTTL:         5 * time.Minute,
+			OTPMaxAttempts: 5,
+			OTPResend:      time.Minute,
+			TokenTTL:       time.Hour,
+			SendTimeout:    time.Second,
+			Language:       notification.LanguageIndonesian,
+			LaundryName:    "Chronos",
+		})
+}
+
+func TestRequestLoginCode(t *testing.T) {
+	order := models.Transaction{CustomerName: "Budi", CustomerPhone: "08123456789"}
+	tests := []struct {
+		name        string
+		orders      []models.Transaction
+		current     *models.CustomerLoginCode
+		sendErr     error
+		wantErr     bool
+		wantSent    bool
+		wantDeleted bool
+	}{
+		{name: "no orders", wantSent: false},
+		{name: "first code", orders: []models.Transaction{order}, wantSent: true},
+		{
+			name:    "within the resend interval",
+			orders:  []models.Transaction{order},
+			current: &models.CustomerLoginCode{ID: 3, Phone: testPhone, CreatedAt: time.Now().Add(-30 * time.Second)},
+		},
+		{
+			name:     "after the resend interval",
+			orders:   []models.Transaction{order},
+			current:  &models.CustomerLoginCode{ID: 3, Phone: testPhone, CreatedAt: time.Now().Add(-2 * time.Minute)},
+			wantSent: true,
+		},
+		{
+			name:        "sending fails",
+			orders:      []models.Transaction{order},
+			sendErr:     errors.New("gateway down"),
+			wantErr:     true,
+			wantSent:    true,
+			wantDeleted: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &loginDB{orders: tt.orders, code: tt.current}
+			sender := &loginSender{err: tt.sendErr}
+			err := newLoginService(t, f, sender).RequestLoginCode(context.Background(), "08123456789")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("got error %v, want error %v", err, tt.wantErr)
+			}
+			if sent := len(sender.sent) > 0; sent != tt.wantSent {
+				t.Fatalf("code sent = %v, want %v", sent, tt.wantSent)
+			}
+			// The code replaces the phone's previous one in place, so each phone has one code
+			if stored := f.wrote("INSERT INTO `customer_login_codes`"); stored != tt.wantSent {
+				t.Errorf("code stored = %v, want %v: %q", stored, tt.wantSent, f.writes)
+			}
+			if tt.wantSent {
+				for _, reset := range []string{"ON DUPLICATE KEY UPDATE", "`attempts`=", "`used_at`=", "`code_hash`="} {
+					if !strings.Contains(f.writes[0], reset) {
+						t.Errorf("previous code not replaced, no %s: %s", reset, f.writes[0])
+					}
+				}
+			}
+			if deleted := f.wrote("DELETE FROM `customer_login_codes`"); deleted != tt.wantDeleted {
+				t.Errorf("code deleted = %v, want %v: %q", deleted, tt.wantDeleted, f.writes)
+			}
+		})
+	}
+}
+
+func TestVerifyLoginCode(t *testing.T) {
+	hash, err := utils.HashPassword("123456")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	code := func(change func(*models.CustomerLoginCode)) *models.CustomerLoginCode {
+		c := &models.CustomerLoginCode{ID: 3, Phone: testPhone, CodeHash: hash, ExpiresAt: now.Add(time.Minute)}
+		if change != nil {
+			change(c)
+		}
+		return c
+	}
+
+	tests := []struct {
+		name       string
+		code       *models.CustomerLoginCode
+		input      string
+		refuse     []string
+		wantToken  bool
+		wantWrites int
+	}{
+		{name: "no code", input: "123456"},
+		{name: "expired", code: code(func(c *models.CustomerLoginCode) { c.ExpiresAt = now.Add(-time.Second) }), input: "123456"},
+		{name: "already used", code: code(func(c *models.CustomerLoginCode) { c.UsedAt = &now }), input: "123456"},
+		{name: "wrong code counts an attempt", code: code(nil), input: "654321", wantWrites: 1},
+		{name: "attempts used up", code: code(nil), input: "123456", refuse: []string{"attempts < ?"}, wantWrites: 1},
+		// A concurrent request used the code between the attempt count and the use
+		{name: "used concurrently", code: code(nil), input: "123456", refuse: []string{"used_at IS NULL"}, wantWrites: 2},
+		{name: "right code", code: code(nil), input: "123456", wantToken: true, wantWrites: 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &loginDB{dryRunDB: dryRunDB{refuse: tt.refuse}, code: tt.code}
+			token, _, err := newLoginService(t, f, &loginSender{}).VerifyLoginCode(context.Background(), "08123456789", tt.input)
+			if tt.wantToken {
+				if err != nil || token == "" {
+					t.Fatalf("got token %q and error %v, want a token", token, err)
+				}
+			} else if !errors.Is(err, utils.ErrUnauthorized) {
+				t.Fatalf("got error %v, want unauthorized", err)
+			}
+			if len(f.writes) != tt.wantWrites {
+				t.Errorf("got %d writes, want %d: %q", len(f.writes), tt.wantWrites, f.writes)
+			}
+		})
+	}
+}
+
+func TestPhoneForms(t *testing.T) {
+	got := phoneForms(testPhone)
+	want := []string{"628123456789", "+628123456789", "08123456789"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestRandomDigits(t *testing.T) {
+	code, err := randomDigits(loginCodeDigits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(code) != loginCodeDigits || strings.Trim(code, "0123456789") != "" {
+		t.Errorf("got %q, want %d digits", code, loginCodeDigits)
+	}
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"testing"
+
+	"gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// dryRunDB is a dry-run database standing in for MySQL in service tests. Queries are answered
+// by query, which fills in the statement's Dest. Writes are recorded, passed to written and
+// affect one row, unless their SQL contains one of refuse, as when their condition fails.
+type dryRunDB struct {
+	query   func(tx *gorm.DB)
+	written func(tx *gorm.DB)
+	refuse  []string
+	writes  []string
+}
+
+func (d *dryRunDB) open(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(mysql.New(mysql.Config{Conn: &dryRunPool{}, SkipInitializeWithVersion: true}),
+		&gorm.Config{DryRun: true, DisableAutomaticPing: true, Logger: logger.Discard})
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := func(tx *gorm.DB) {
+		if d.query != nil {
+			d.query(tx)
+		}
+	}
+	write := func(tx *gorm.DB) {
+		sql := tx.Statement.SQL.String()
+		d.writes = append(d.writes, sql)
+		tx.RowsAffected = 1
+		for _, condition := range d.refuse {
+			if strings.Contains(sql, condition) {
+				tx.RowsAffected = 0
+			}
+		}
+		if d.written != nil {
+			d.written(tx)
+		}
+	}
+	for _, err := range []error{
+		db.Callback().Query().After("gorm:query").Before("gorm:preload").Register("test:query", query),
+		db.Callback().Create().After("gorm:create").Register("test:create", write),
+		db.Callback().Update().After("gorm:update").Register("test:update", write),
+		db.Callback().Delete().After("gorm:delete").Register("test:delete", write),
+	} {
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	return db
+}
+
+// wrote reports whether a recorded write starts with prefix
+func (d *dryRunDB) wrote(prefix string) bool {
+	for _, sql := range d.writes {
+		if strings.HasPrefix(sql, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// dryRunPool is a connection pool for dry-run databases: it begins transactions, which dry
+// runs still do, and never runs a statement
+type dryRunPool struct{}
+
+func (*dryRunPool) PrepareContext(context.Context, string) (*sql.Stmt, error) {
+	return nil, errors.New("dry run")
+}
+func (*dryRunPool) ExecContext(context.Context, string, ...any) (sql.Result, error) {
+	return nil, errors.New("dry run")
+}
+func (*dryRunPool) QueryContext(context.Context, string, ...any) (*sql.Rows, error) {
+	return nil, errors.New("dry run")
+}
+func (*dryRunPool) QueryRowContext(context.Context, string, ...any) *sql.Row { return nil }
+func (p *dryRunPool) BeginTx(context.Context, *sql.TxOptions) (gorm.ConnPool, error) {
+	return p, nil
+}
+func (*dryRunPool) Commit() error   { return nil }
+func (*dryRunPool) Rollback() error { return nil }
@@ -0,0 +1,205 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+)
+
+// eventBatch is how many due events the dispatcher claims at once
+const eventBatch = 100
+
+// eventLease is how long a claimed event stays hidden from other dispatchers.
+// Subscribers only write to the database, so a batch finishes well within it.
+const eventLease = 5 * time.Minute
+
+const (
+	// eventPruneInterval is how often events past the retention are pruned
+	eventPruneInterval = time.Hour
+	// eventPruneBatch is how many outbox rows one delete removes at most
+	eventPruneBatch = 1000
+)
+
+// EventSettings configures the dispatch of domain events
+type EventSettings struct {
+	PollInterval time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Retention    time.Duration // dispatched and failed events are pruned after this long, never when 0
+}
+
+// EventService publishes domain events to the outbox and dispatches them to the subscribers
+// on the bus in the background. Every subscriber sees every event at least once.
+type EventService struct {
+	outboxRepo *repositories.OutboxRepository
+	bus        *events.Bus
+	settings   EventSettings
+	wake       chan struct{}
+}
+
+// NewEventService creates an event service dispatching to the subscribers on bus
+func NewEventService(outboxRepo *repositories.OutboxRepository, bus *events.Bus, settings EventSettings) *EventService {
+	return &EventService{
+		outboxRepo: outboxRepo,
+		bus:        bus,
+		settings:   settings,
+		wake:       make(chan struct{}, 1),
+	}
+}
+
+// Publish stores events in the outbox. Inside a database transaction they are stored with the
+// change and dispatched once it commits; when it rolls back they are never dispatched.
+func (s *EventService) Publish(ctx context.Context, evs ...events.Event) error {
+	now := time.Now()
+	rows := make([]models.OutboxEvent, len(evs))
+	for i, event := range evs {
+		payload, err := json.Marshal(event)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s: %w", event.EventName(), err)
+		}
+		var code *string
+		if c := events.TransactionCode(event); c != "" {
+			code = &c
+		}
+		rows[i] = models.OutboxEvent{
+			EventID:         events.NewID(),
+			Name:            event.EventName(),
+			TransactionCode: code,
+			Payload:         payload,
+			Status:          models.OutboxPending,
+			NextAttemptAt:   now,
+			Handled:         []string{},
+			OccurredAt:      now,
+		}
+	}
+	if err := s.outboxRepo.Append(ctx, rows); err != nil {
+		return fmt.Errorf("failed to publish events: %w", err)
+	}
+	repositories.AfterCommit(ctx, s.signal)
+	return nil
+}
+
+// signal wakes the dispatcher up without waiting for the next poll
+func (s *EventService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run dispatches due events until ctx is cancelled
+func (s *EventService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.settings.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.dispatchDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// dispatchDue dispatches due events batch by batch until none is left
+func (s *EventService) dispatchDue(ctx context.Context) {
+	for ctx.Err() == nil {
+		claimed, err := s.outboxRepo.ClaimDue(ctx, time.Now(), eventLease, eventBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to claim due events", slog.String("error", err.Error()))
+			}
+			return
+		}
+		for i := range claimed {
+			s.dispatch(ctx, &claimed[i])
+		}
+		if len(claimed) < eventBatch {
+			return
+		}
+	}
+}
+
+// dispatch hands one event to the subscribers that have not handled it yet and records the outcome
+func (s *EventService) dispatch(ctx context.Context, row *models.OutboxEvent) {
+	handled := []string(row.Handled)
+	event, err := events.Decode(row.Name, row.Payload)
+	if err == nil {
+		handled, err = s.bus.Dispatch(ctx, events.Envelope{ID: row.EventID, OccurredAt: row.OccurredAt, Event: event}, handled)
+	}
+
+	// Record the outcome even when shutting down
+	recordCtx := context.WithoutCancel(ctx)
+	logAttrs := []any{
+		slog.String("event_id", row.EventID),
+		slog.String("event", row.Name),
+		slog.Int("attempt", row.Attempts),
+	}
+
+	var recordErr error
+	switch {
+	case err == nil:
+		metrics.EventDispatchesTotal.Inc(row.Name, "dispatched")
+		recordErr = s.outboxRepo.MarkDispatched(recordCtx, row.ID, handled, time.Now())
+	case event == nil || row.Attempts >= s.settings.MaxAttempts:
+		metrics.EventDispatchesTotal.Inc(row.Name, "failed")
+		slog.ErrorContext(ctx, "event dispatch failed", append(logAttrs, slog.String("error", err.Error()))...)
+		recordErr = s.outboxRepo.MarkFailed(recordCtx, row.ID, handled, err.Error())
+	default:
+		metrics.EventDispatchesTotal.Inc(row.Name, "retry")
+		slog.WarnContext(ctx, "event dispatch failed, retrying", append(logAttrs, slog.String("error", err.Error()))...)
+		recordErr = s.outboxRepo.MarkRetry(recordCtx, row.ID, handled, time.Now().Add(retryDelay(s.settings.RetryBackoff, row.Attempts)), err.Error())
+	}
+	if recordErr != nil {
+		slog.ErrorContext(ctx, "failed to record event dispatch", append(logAttrs, slog.String("error", recordErr.Error()))...)
+	}
+}
+
+// RunPruning deletes the dispatched and failed events past the retention every prune
+// interval until ctx is cancelled
+func (s *EventService) RunPruning(ctx context.Context) {
+	if s.settings.Retention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(eventPruneInterval)
+	defer ticker.Stop()
+	for {
+		s.pruneFinished(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pruneFinished deletes the finished events that occurred before the retention, batch by batch
+func (s *EventService) pruneFinished(ctx context.Context) {
+	cutoff := time.Now().Add(-s.settings.Retention)
+	var pruned int64
+	for ctx.Err() == nil {
+		deleted, err := s.outboxRepo.DeleteFinished(ctx, cutoff, eventPruneBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to prune the event outbox", slog.String("error", err.Error()))
+			}
+			break
+		}
+		pruned += deleted
+		if deleted < eventPruneBatch {
+			break
+		}
+	}
+	if pruned > 0 {
+		slog.InfoContext(ctx, "pruned finished events from the outbox", slog.Int64("events", pruned))
+	}
+}
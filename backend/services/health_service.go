@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/migrations"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"gorm.io/gorm"
+)
+
+// ReadinessCheck is the outcome of a single readiness check
+type ReadinessCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"` // "ok" or "fail"
+	Error  string `json:"error,omitempty"`
+}
+
+// ReadinessReport summarizes whether the service can take traffic
+type ReadinessReport struct {
+	Ready  bool             `json:"ready"`
+	Checks []ReadinessCheck `json:"checks"`
+}
+
+// HealthService checks the dependencies the API needs to serve requests
+type HealthService struct {
+	db       *gorm.DB
+	migrator *migrations.Migrator
+}
+
+// NewHealthService creates a new health service
+func NewHealthService(db *gorm.DB, migrator *migrations.Migrator) *HealthService {
+	return &HealthService{db: db, migrator: migrator}
+}
+
+// CheckReadiness pings the database and verifies that no migrations are pending
+func (s *HealthService) CheckReadiness(ctx context.Context) ReadinessReport {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	report := ReadinessReport{Ready: true}
+	// The probe response only carries a short summary, the cause is logged
+	record := func(name string, err error, summary string) {
+		check := ReadinessCheck{Name: name, Status: "ok"}
+		if err != nil {
+			slog.WarnContext(ctx, "readiness check failed", slog.String("check", name), slog.String("error", err.Error()))
+			check.Status = "fail"
+			check.Error = summary
+			report.Ready = false
+		}
+		report.Checks = append(report.Checks, check)
+	}
+
+	dbErr := s.pingDatabase(ctx)
+	record("database", dbErr, "database unreachable")
+
+	// Migration status needs the database, so only check it once the ping succeeded
+	if dbErr == nil {
+		migrationErr := s.checkMigrations()
+		summary := "migration status unavailable"
+		if errors.Is(migrationErr, utils.ErrConflict) {
+			summary = migrationErr.Error()
+		}
+		record("migrations", migrationErr, summary)
+	}
+	return report
+}
+
+// pingDatabase verifies the database connection is alive
+func (s *HealthService) pingDatabase(ctx context.Context) error {
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.PingContext(ctx)
+}
+
+// checkMigrations reports an error when the schema is behind the binary
+func (s *HealthService) checkMigrations() error {
+	pending, err := s.migrator.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return utils.NewError(utils.ErrConflict, "%d pending migration(s)", len(pending))
+	}
+	return nil
+}
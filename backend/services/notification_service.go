@@ -0,0 +1,256 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/notification"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// NotificationFilter narrows a listing of the notification outbox
+type NotificationFilter = repositories.NotificationFilter
+
+const (
+	// notificationBatch is how many due notifications the worker claims at once
+	notificationBatch = 50
+	// maxRetryDelay caps the exponential backoff between attempts
+	maxRetryDelay = 6 * time.Hour
+)
+
+// NotificationSettings configures which messages are queued and how they are delivered
+type NotificationSettings struct {
+	Statuses     []models.TransactionStatus // statuses that notify the customer
+	Language     string                     // for customers without a language of their own
+	LaundryName  string
+	PollInterval time.Duration
+	SendTimeout  time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+}
+
+// NotificationService queues customer notifications in the outbox and delivers them in the
+// background, so a slow or failing provider never holds up the change that triggered them
+type NotificationService struct {
+	notificationRepo *repositories.NotificationRepository
+	channels         map[string]notification.Channel
+	settings         NotificationSettings
+	wake             chan struct{}
+}
+
+// NewNotificationService creates a notification service delivering through channels
+func NewNotificationService(notificationRepo *repositories.NotificationRepository, channels []notification.Channel, settings NotificationSettings) *NotificationService {
+	byName := make(map[string]notification.Channel, len(channels))
+	for _, channel := range channels {
+		byName[channel.Name()] = channel
+	}
+	return &NotificationService{
+		notificationRepo: notificationRepo,
+		channels:         byName,
+		settings:         settings,
+		wake:             make(chan struct{}, 1),
+	}
+}
+
+// Subscribe queues the customer messages of transaction events, when any channel is enabled
+func (s *NotificationService) Subscribe(bus *events.Bus) {
+	if len(s.channels) == 0 {
+		return
+	}
+	events.On(bus, "notifications", func(ctx context.Context, envelope events.Envelope, e events.TransactionCreated) error {
+		return s.NotifyStatusChange(ctx, envelope, &e.Transaction, models.StatusQueued)
+	})
+	events.On(bus, "notifications", func(ctx context.Context, envelope events.Envelope, e events.StatusChanged) error {
+		return s.NotifyStatusChange(ctx, envelope, &e.Transaction, e.Transaction.Status)
+	})
+}
+
+// NotifyStatusChange queues a message on every channel that can reach the customer when
+// the transaction reached a status that notifies, once per event and channel
+func (s *NotificationService) NotifyStatusChange(ctx context.Context, envelope events.Envelope, transaction *models.Transaction, status models.TransactionStatus) error {
+	if len(s.channels) == 0 || !slices.Contains(s.settings.Statuses, status) {
+		return nil
+	}
+
+	lang := transaction.CustomerLanguage
+	if !notification.IsLanguage(lang) {
+		lang = s.settings.Language
+	}
+	data := notification.TemplateData{
+		LaundryName:     s.settings.LaundryName,
+		CustomerName:    transaction.CustomerName,
+		TransactionCode: transaction.TransactionCode,
+		Status:          status,
+		TotalPrice:      transaction.TotalPrice,
+		IsPaid:          transaction.IsPaid,
+		PickupDate:      time.Time(transaction.PickupDate),
+	}
+	subject, body, err := notification.Render(lang, status, data)
+	if err != nil {
+		return fmt.Errorf("failed to render customer notification: %w", err)
+	}
+
+	contact := notification.Contact{Name: transaction.CustomerName, Phone: transaction.CustomerPhone, Email: transaction.CustomerEmail}
+	now := time.Now()
+	var queued []models.Notification
+	for name, channel := range s.channels {
+		recipient := channel.Recipient(contact)
+		if recipient == "" {
+			continue
+		}
+		queued = append(queued, models.Notification{
+			TransactionID: transaction.ID,
+			EventID:       &envelope.ID,
+			Event:         string(status),
+			Channel:       name,
+			Recipient:     recipient,
+			Language:      lang,
+			Subject:       subject,
+			Body:          body,
+			Status:        models.NotificationPending,
+			NextAttemptAt: now,
+		})
+	}
+	if err := s.notificationRepo.CreateNotifications(ctx, queued); err != nil {
+		return fmt.Errorf("failed to queue customer notification: %w", err)
+	}
+	if len(queued) > 0 {
+		s.signal()
+	}
+	return nil
+}
+
+// signal wakes the worker up without waiting for the next poll
+func (s *NotificationService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Run delivers due notifications until ctx is cancelled. Deliveries are at least once:
+// a message whose outcome could not be recorded is sent again once its claim expires.
+func (s *NotificationService) Run(ctx context.Context) {
+	if len(s.channels) == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.settings.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliverDue delivers due notifications batch by batch until none is left
+func (s *NotificationService) deliverDue(ctx context.Context) {
+	// Claims outlive a whole batch of slow attempts before other workers may retry them
+	lease := notificationBatch*s.settings.SendTimeout + time.Minute
+	for ctx.Err() == nil {
+		claimed, err := s.notificationRepo.ClaimDue(ctx, time.Now(), lease, notificationBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to claim due notifications", slog.String("error", err.Error()))
+			}
+			return
+		}
+		for i := range claimed {
+			s.deliver(ctx, &claimed[i])
+		}
+		if len(claimed) < notificationBatch {
+			return
+		}
+	}
+}
+
+// deliver attempts to send one notification and records the outcome
+func (s *NotificationService) deliver(ctx context.Context, n *models.Notification) {
+	var err error
+	channel, ok := s.channels[n.Channel]
+	if ok {
+		sendCtx, cancel := context.WithTimeout(ctx, s.settings.SendTimeout)
+		err = channel.Send(sendCtx, notification.Message{To: n.Recipient, Subject: n.Subject, Body: n.Body})
+		cancel()
+	} else {
+		err = notification.Permanent(fmt.Errorf("channel %q is not configured", n.Channel))
+	}
+
+	// Record the outcome even when shutting down
+	recordCtx := context.WithoutCancel(ctx)
+	logAttrs := []any{
+		slog.Uint64("notification_id", uint64(n.ID)),
+		slog.Uint64("transaction_id", uint64(n.TransactionID)),
+		slog.String("channel", n.Channel),
+		slog.Int("attempt", n.Attempts),
+	}
+
+	var recordErr error
+	switch {
+	case err == nil:
+		metrics.NotificationAttemptsTotal.Inc(n.Channel, "sent")
+		recordErr = s.notificationRepo.MarkSent(recordCtx, n.ID, time.Now())
+	case notification.IsPermanent(err) || n.Attempts >= s.settings.MaxAttempts:
+		metrics.NotificationAttemptsTotal.Inc(n.Channel, "failed")
+		slog.ErrorContext(ctx, "customer notification failed", append(logAttrs, slog.String("error", err.Error()))...)
+		recordErr = s.notificationRepo.MarkFailed(recordCtx, n.ID, err.Error())
+	default:
+		metrics.NotificationAttemptsTotal.Inc(n.Channel, "retry")
+		slog.WarnContext(ctx, "customer notification attempt failed, retrying", append(logAttrs, slog.String("error", err.Error()))...)
+		recordErr = s.notificationRepo.MarkRetry(recordCtx, n.ID, time.Now().Add(retryDelay(s.settings.RetryBackoff, n.Attempts)), err.Error())
+	}
+	if recordErr != nil {
+		slog.ErrorContext(ctx, "failed to record notification attempt", append(logAttrs, slog.String("error", recordErr.Error()))...)
+	}
+}
+
+// retryDelay returns the exponential backoff from base after the given number of attempts
+func retryDelay(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryDelay; i++ {
+		delay *= 2
+	}
+	return min(delay, maxRetryDelay)
+}
+
+// ListNotifications returns the outbox notifications matching the filter, latest first, and their total count
+func (s *NotificationService) ListNotifications(ctx context.Context, filter NotificationFilter) ([]models.Notification, int64, error) {
+	notifications, total, err := s.notificationRepo.ListNotifications(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve notifications: %w", err)
+	}
+	return notifications, total, nil
+}
+
+// RetryNotification queues a failed notification for delivery again
+func (s *NotificationService) RetryNotification(ctx context.Context, id uint) (*models.Notification, error) {
+	requeued, err := s.notificationRepo.Requeue(ctx, id, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to requeue notification: %w", err)
+	}
+
+	n, err := s.notificationRepo.GetNotificationByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve notification: %w", err)
+	}
+	if n == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "notification not found")
+	}
+	if !requeued {
+		return nil, utils.NewError(utils.ErrConflict, "only failed notifications can be retried, this one is %s", n.Status)
+	}
+	s.signal()
+	return n, nil
+}
@@ -0,0 +1,59 @@
+package services
+
+import (
+	"fmt"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/export"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+)
+
+// WriteReport writes a report returned by ReportService as a sheet with a header row
+func WriteReport(w export.Writer, report any) error {
+	var rows [][]any
+	switch r := report.(type) {
+	case *ReportSummary:
+		rows = [][]any{
+			{"orders", "gross", "paid", "unpaid", "average_order_value"},
+			{r.Orders, r.Gross, r.Paid, r.Unpaid, r.AverageOrderValue},
+		}
+	case []repositories.PeriodRevenue:
+		rows = [][]any{{"period", "orders", "gross", "paid"}}
+		for _, row := range r {
+			rows = append(rows, []any{row.Period, row.Orders, row.Gross, row.Paid})
+		}
+	case []repositories.ServiceRevenue:
+		rows = [][]any{{"service_type", "item_name", "quantity", "orders", "gross", "paid"}}
+		for _, row := range r {
+			rows = append(rows, []any{row.ServiceType, row.ItemName, row.Quantity, row.Orders, row.Gross, row.Paid})
+		}
+	case []repositories.AdminRevenue:
+		rows = [][]any{{"admin_id", "username", "orders", "gross", "paid"}}
+		for _, row := range r {
+			rows = append(rows, []any{row.AdminID, row.Username, row.Orders, row.Gross, row.Paid})
+		}
+	case *TurnaroundReport:
+		rows = [][]any{
+			{"measure", "count", "average_hours", "p50_hours", "p90_hours", "p95_hours"},
+			durationRow("to_ready", r.ToReady),
+			durationRow("to_pickup", r.ToPickup),
+		}
+	case []AgingBucket:
+		rows = [][]any{{"label", "min_days", "max_days", "orders", "amount"}}
+		for _, bucket := range r {
+			rows = append(rows, []any{bucket.Label, bucket.MinDays, bucket.MaxDays, bucket.Orders, bucket.Amount})
+		}
+	default:
+		return fmt.Errorf("unsupported report type %T", report)
+	}
+
+	for _, row := range rows {
+		if err := w.WriteRow(row...); err != nil {
+			return err
+		}
+	}
+	return w.Close()
+}
+
+func durationRow(measure string, stats DurationStats) []any {
+	return []any{measure, stats.Count, stats.AverageHours, stats.P50Hours, stats.P90Hours, stats.P95Hours}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// ReportFilter selects the transactions a report covers
+type ReportFilter = repositories.ReportFilter
+
+// ReportSummary gives the totals of a range and its average order value
+type ReportSummary struct {
+	repositories.RevenueTotals
+	Unpaid            float64 `json:"unpaid"`
+	AverageOrderValue float64 `json:"average_order_value"`
+}
+
+// TurnaroundReport describes how long orders took to become ready and to be picked up
+type TurnaroundReport struct {
+	ToReady  DurationStats `json:"to_ready"`
+	ToPickup DurationStats `json:"to_pickup"`
+}
+
+// DurationStats summarizes a set of durations, in hours
+type DurationStats struct {
+	Count        int     `json:"count"`
+	AverageHours float64 `json:"average_hours"`
+	P50Hours     float64 `json:"p50_hours"`
+	P90Hours     float64 `json:"p90_hours"`
+	P95Hours     float64 `json:"p95_hours"`
+}
+
+// AgingBucket sums the unpaid orders within an age range, in days. MaxDays is nil for the last bucket.
+type AgingBucket struct {
+	Label   string  `json:"label"`
+	MinDays int     `json:"min_days"`
+	MaxDays *int    `json:"max_days"`
+	Orders  int64   `json:"orders"`
+	Amount  float64 `json:"amount"`
+}
+
+// agingBucketBounds are the upper bounds, in days, of every aging bucket but the open-ended last one
+var agingBucketBounds = []int{7, 30, 60}
+
+// ReportService computes revenue and operations reports
+type ReportService struct {
+	reportRepo *repositories.ReportRepository
+}
+
+// NewReportService creates a new report service
+func NewReportService(reportRepo *repositories.ReportRepository) *ReportService {
+	return &ReportService{reportRepo: reportRepo}
+}
+
+// GetSummary returns the order count, gross, paid and unpaid totals and the average order value
+func (s *ReportService) GetSummary(ctx context.Context, filter ReportFilter) (*ReportSummary, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	totals, err := s.reportRepo.GetRevenueTotals(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute revenue totals: %w", err)
+	}
+	summary := &ReportSummary{RevenueTotals: *totals, Unpaid: totals.Gross - totals.Paid}
+	if totals.Orders > 0 {
+		summary.AverageOrderValue = roundTo(totals.Gross/float64(totals.Orders), 2)
+	}
+	return summary, nil
+}
+
+// GetRevenueByPeriod returns the revenue per day, week or month, with periods cut in loc
+func (s *ReportService) GetRevenueByPeriod(ctx context.Context, filter ReportFilter, interval string, loc *time.Location) ([]repositories.PeriodRevenue, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+	switch interval {
+	case repositories.ReportIntervalDay, repositories.ReportIntervalWeek, repositories.ReportIntervalMonth:
+	default:
+		return nil, utils.NewValidationError("interval", "must be one of day, week, month")
+	}
+
+	// The database stores local server time. Offsets are taken at the start of the range,
+	// so a daylight saving change inside the range shifts later periods by an hour.
+	at := time.Now()
+	if filter.From != nil {
+		at = *filter.From
+	}
+	rows, err := s.reportRepo.GetRevenueByPeriod(ctx, filter, interval, utcOffset(at.In(time.Local)), utcOffset(at.In(loc)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute revenue by period: %w", err)
+	}
+	return rows, nil
+}
+
+// GetRevenueByService returns the revenue per service type, or per service type and item
+func (s *ReportService) GetRevenueByService(ctx context.Context, filter ReportFilter, byItem bool) ([]repositories.ServiceRevenue, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.GetRevenueByService(ctx, filter, byItem)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute revenue by service: %w", err)
+	}
+	return rows, nil
+}
+
+// GetRevenueByAdmin returns the revenue of the orders each admin took
+func (s *ReportService) GetRevenueByAdmin(ctx context.Context, filter ReportFilter) ([]repositories.AdminRevenue, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.GetRevenueByAdmin(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute revenue by admin: %w", err)
+	}
+	return rows, nil
+}
+
+// GetTurnaround returns the time from order to ready and from order to pick-up
+func (s *ReportService) GetTurnaround(ctx context.Context, filter ReportFilter) (*TurnaroundReport, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.GetTurnaroundTimes(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute turnaround times: %w", err)
+	}
+
+	var toReady, toPickup []time.Duration
+	for _, row := range rows {
+		if row.ReadyAt != nil {
+			toReady = append(toReady, row.ReadyAt.Sub(row.CreatedAt))
+		}
+		if row.PickedUpAt != nil {
+			toPickup = append(toPickup, row.PickedUpAt.Sub(row.CreatedAt))
+		}
+	}
+	return &TurnaroundReport{ToReady: durationStats(toReady), ToPickup: durationStats(toPickup)}, nil
+}
+
+// GetUnpaidAging returns the unpaid orders grouped by how many days old they are
+func (s *ReportService) GetUnpaidAging(ctx context.Context, filter ReportFilter) ([]AgingBucket, error) {
+	if err := validateReportFilter(filter); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.reportRepo.GetUnpaidByAge(ctx, filter, time.Now())
+	if err != nil {
+		return nil, fmt.Errorf("failed to compute unpaid aging: %w", err)
+	}
+	return agingBuckets(rows), nil
+}
+
+// agingBuckets sums unpaid orders by age into the buckets bounded by agingBucketBounds
+func agingBuckets(rows []repositories.UnpaidAge) []AgingBucket {
+	buckets := make([]AgingBucket, 0, len(agingBucketBounds)+1)
+	minDays := 0
+	for _, bound := range agingBucketBounds {
+		maxDays := bound
+		buckets = append(buckets, AgingBucket{Label: fmt.Sprintf("%d-%d", minDays, maxDays), MinDays: minDays, MaxDays: &maxDays})
+		minDays = bound + 1
+	}
+	buckets = append(buckets, AgingBucket{Label: fmt.Sprintf("%d+", minDays), MinDays: minDays})
+
+	for _, row := range rows {
+		// Clock skew can make a fresh order look a day younger than zero
+		i, _ := slices.BinarySearch(agingBucketBounds, max(row.AgeDays, 0))
+		buckets[i].Orders += row.Orders
+		buckets[i].Amount += row.Amount
+	}
+	return buckets
+}
+
+// validateReportFilter rejects empty and inverted ranges
+func validateReportFilter(filter ReportFilter) error {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return utils.NewValidationError("to", "must be after from")
+	}
+	return nil
+}
+
+// durationStats computes the average and nearest-rank percentiles of durations
+func durationStats(durations []time.Duration) DurationStats {
+	stats := DurationStats{Count: len(durations)}
+	if len(durations) == 0 {
+		return stats
+	}
+	slices.Sort(durations)
+
+	var total time.Duration
+	for _, d := range durations {
+		total += d
+	}
+	percentile := func(p float64) float64 {
+		rank := int(math.Ceil(p/100*float64(len(durations)))) - 1
+		return roundTo(durations[max(rank, 0)].Hours(), 2)
+	}
+
+	stats.AverageHours = roundTo((total / time.Duration(len(durations))).Hours(), 2)
+	stats.P50Hours = percentile(50)
+	stats.P90Hours = percentile(90)
+	stats.P95Hours = percentile(95)
+	return stats
+}
+
+// utcOffset formats the UTC offset of t as +07:00
+func utcOffset(t time.Time) string {
+	return t.Format("-07:00")
+}
+
+// roundTo rounds x to the given number of decimals
+func roundTo(x float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(x*scale) / scale
+}
@@ -0,0 +1,100 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+)
+
+func TestDurationStats(t *testing.T) {
+	hours := func(values ...float64) []time.Duration {
+		durations := make([]time.Duration, len(values))
+		for i, v := range values {
+			durations[i] = time.Duration(v * float64(time.Hour))
+		}
+		return durations
+	}
+	tests := []struct {
+		name      string
+		durations []time.Duration
+		want      DurationStats
+	}{
+		{"empty", nil, DurationStats{}},
+		{"one", hours(5), DurationStats{Count: 1, AverageHours: 5, P50Hours: 5, P90Hours: 5, P95Hours: 5}},
+		{
+			// Nearest rank: p50 of 4 is the 2nd, p90 and p95 the 4th
+			"unsorted", hours(4, 1, 3, 2),
+			DurationStats{Count: 4, AverageHours: 2.5, P50Hours: 2, P90Hours: 4, P95Hours: 4},
+		},
+		{
+			// p50 of 10 is the 5th, p90 the 9th, p95 the 10th
+			"ten", hours(1, 2, 3, 4, 5, 6, 7, 8, 9, 10),
+			DurationStats{Count: 10, AverageHours: 5.5, P50Hours: 5, P90Hours: 9, P95Hours: 10},
+		},
+		{
+			// p50 of 20 is the 10th, p90 the 18th, p95 the 19th
+			"twenty", hours(1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16, 17, 18, 19, 20),
+			DurationStats{Count: 20, AverageHours: 10.5, P50Hours: 10, P90Hours: 18, P95Hours: 19},
+		},
+		{"rounded to two decimals", hours(1.0/3, 2.0/3), DurationStats{Count: 2, AverageHours: 0.5, P50Hours: 0.33, P90Hours: 0.67, P95Hours: 0.67}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := durationStats(tt.durations); got != tt.want {
+				t.Errorf("durationStats = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAgingBuckets(t *testing.T) {
+	buckets := agingBuckets([]repositories.UnpaidAge{
+		{AgeDays: -1, Orders: 1, Amount: 10}, // clock skew, counted as 0 days
+		{AgeDays: 0, Orders: 2, Amount: 20},
+		{AgeDays: 7, Orders: 1, Amount: 70},
+		{AgeDays: 8, Orders: 1, Amount: 80},
+		{AgeDays: 30, Orders: 1, Amount: 300},
+		{AgeDays: 31, Orders: 1, Amount: 310},
+		{AgeDays: 60, Orders: 1, Amount: 600},
+		{AgeDays: 61, Orders: 1, Amount: 610},
+		{AgeDays: 400, Orders: 2, Amount: 4000},
+	})
+
+	want := []struct {
+		label            string
+		minDays, maxDays int // maxDays -1 for the open-ended bucket
+		orders           int64
+		amount           float64
+	}{
+		{"0-7", 0, 7, 4, 100},
+		{"8-30", 8, 30, 2, 380},
+		{"31-60", 31, 60, 2, 910},
+		{"61+", 61, -1, 3, 4610},
+	}
+	if len(buckets) != len(want) {
+		t.Fatalf("got %d buckets, want %d", len(buckets), len(want))
+	}
+	for i, w := range want {
+		b := buckets[i]
+		maxDays := -1
+		if b.MaxDays != nil {
+			maxDays = *b.MaxDays
+		}
+		if b.Label != w.label || b.MinDays != w.minDays || maxDays != w.maxDays || b.Orders != w.orders || b.Amount != w.amount {
+			t.Errorf("bucket %d = %+v (max %d), want %+v", i, b, maxDays, w)
+		}
+	}
+}
+
+func TestAgingBucketsEmpty(t *testing.T) {
+	buckets := agingBuckets(nil)
+	if len(buckets) != len(agingBucketBounds)+1 {
+		t.Fatalf("got %d buckets, want %d", len(buckets), len(agingBucketBounds)+1)
+	}
+	for _, b := range buckets {
+		if b.Orders != 0 || b.Amount != 0 {
+			t.Errorf("bucket %s = %+v, want it empty", b.Label, b)
+		}
+	}
+}
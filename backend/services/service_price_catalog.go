@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// CatalogFormat is a supported service price catalog file format
+type CatalogFormat string
+
+const (
+	CatalogFormatCSV  CatalogFormat = "csv"
+	CatalogFormatJSON CatalogFormat = "json"
+)
+
+// catalogCSVHeader is the column order of catalog CSV files
+var catalogCSVHeader = []string{"service_type", "item_name", "description", "price", "is_active"}
+
+// CatalogEntry is one row of an exported or imported service price catalog
+type CatalogEntry struct {
+	ServiceType string  `json:"service_type"`
+	ItemName    string  `json:"item_name"`
+	Description string  `json:"description"`
+	Price       float64 `json:"price"`
+	IsActive    bool    `json:"is_active"`
+}
+
+// CatalogChange describes a service price whose fields differ from the imported entry
+type CatalogChange struct {
+	ID     uint         `json:"id"`
+	Before CatalogEntry `json:"before"`
+	After  CatalogEntry `json:"after"`
+}
+
+// CatalogDiff summarizes what an import adds, changes and deactivates
+type CatalogDiff struct {
+	Added       []CatalogEntry  `json:"added"`
+	Changed     []CatalogChange `json:"changed"`
+	Deactivated []CatalogEntry  `json:"deactivated"`
+	Unchanged   int             `json:"unchanged"`
+	Applied     bool            `json:"applied"`
+}
+
+// ParseCatalogFormat validates a catalog format name
+func ParseCatalogFormat(format string) (CatalogFormat, error) {
+	switch CatalogFormat(strings.ToLower(format)) {
+	case CatalogFormatCSV:
+		return CatalogFormatCSV, nil
+	case CatalogFormatJSON:
+		return CatalogFormatJSON, nil
+	default:
+		return "", utils.NewValidationError("format", fmt.Sprintf("unsupported catalog format %q, use csv or json", format))
+	}
+}
+
+// ExportCatalog retrieves the full service price catalog, including inactive prices
+func (s *ServicePriceService) ExportCatalog(ctx context.Context) ([]CatalogEntry, error) {
+	servicePrices, err := s.servicePriceRepo.GetCatalog(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve service prices: %w", err)
+	}
+
+	entries := make([]CatalogEntry, len(servicePrices))
+	for i, sp := range servicePrices {
+		entries[i] = catalogEntryFromModel(sp)
+	}
+	return entries, nil
+}
+
+// ImportCatalog compares the given entries with the stored catalog and, unless dryRun is set,
+// applies the difference atomically. Active prices missing from the entries are deactivated.
+func (s *ServicePriceService) ImportCatalog(ctx context.Context, entries []CatalogEntry, dryRun bool) (*CatalogDiff, error) {
+	if err := validateCatalogEntries(entries); err != nil {
+		return nil, err
+	}
+
+	current, err := s.servicePriceRepo.GetCatalog(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve service prices: %w", err)
+	}
+
+	diff, plan := diffCatalog(current, entries)
+	if dryRun {
+		return diff, nil
+	}
+
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.servicePriceRepo.ApplyCatalog(ctx, plan.added, plan.changed, plan.deactivatedIDs); err != nil {
+			return fmt.Errorf("failed to apply catalog: %w", err)
+		}
+
+		var changes []events.Event
+		var entries []AuditEntry
+		for i, sp := range plan.added {
+			changes = append(changes, events.PriceChanged{Change: events.PriceCreated, ServicePrice: sp})
+			entries = append(entries, priceAudit(models.AuditCreate, nil, &plan.added[i]))
+		}
+		for i, sp := range plan.changed {
+			changes = append(changes, events.PriceChanged{Change: events.PriceUpdated, ServicePrice: sp, Previous: &plan.previous[i]})
+			entries = append(entries, priceAudit(models.AuditUpdate, &plan.previous[i], &plan.changed[i]))
+		}
+		for _, sp := range current {
+			if slices.Contains(plan.deactivatedIDs, sp.ID) {
+				before := sp
+				sp.IsActive = false
+				changes = append(changes, events.PriceChanged{Change: events.PriceDeactivated, ServicePrice: sp})
+				entries = append(entries, priceAudit(models.AuditDeactivate, &before, &sp))
+			}
+		}
+		if err := s.audit.Record(ctx, entries...); err != nil {
+			return err
+		}
+		return s.publish(ctx, changes...)
+	})
+	if err != nil {
+		return nil, err
+	}
+	diff.Applied = true
+	return diff, nil
+}
+
+// catalogPlan holds the service price writes that apply a catalog import
+type catalogPlan struct {
+	added          []models.ServicePrice
+	changed        []models.ServicePrice // after the import
+	previous       []models.ServicePrice // changed prices before the import, in the same order
+	deactivatedIDs []uint
+}
+
+// diffCatalog compares imported entries with the current catalog and returns what the import
+// changes, both as a report and as the writes that apply it
+func diffCatalog(current []models.ServicePrice, entries []CatalogEntry) (*CatalogDiff, catalogPlan) {
+	existing := make(map[string]models.ServicePrice, len(current))
+	for _, sp := range current {
+		existing[catalogKey(sp.ServiceType, sp.ItemName)] = sp
+	}
+
+	diff := &CatalogDiff{
+		Added:       []CatalogEntry{},
+		Changed:     []CatalogChange{},
+		Deactivated: []CatalogEntry{},
+	}
+	var plan catalogPlan
+	seen := make(map[string]bool, len(entries))
+
+	for _, entry := range entries {
+		key := catalogKey(entry.ServiceType, entry.ItemName)
+		seen[key] = true
+
+		sp, ok := existing[key]
+		if !ok {
+			diff.Added = append(diff.Added, entry)
+			plan.added = append(plan.added, models.ServicePrice{
+				ServiceType: entry.ServiceType,
+				ItemName:    entry.ItemName,
+				Description: entry.Description,
+				Price:       entry.Price,
+				IsActive:    entry.IsActive,
+			})
+			continue
+		}
+
+		before := catalogEntryFromModel(sp)
+		if before == entry {
+			diff.Unchanged++
+			continue
+		}
+
+		diff.Changed = append(diff.Changed, CatalogChange{ID: sp.ID, Before: before, After: entry})
+		plan.previous = append(plan.previous, sp)
+		sp.Description = entry.Description
+		sp.Price = entry.Price
+		sp.IsActive = entry.IsActive
+		plan.changed = append(plan.changed, sp)
+	}
+
+	for _, sp := range current {
+		if sp.IsActive && !seen[catalogKey(sp.ServiceType, sp.ItemName)] {
+			diff.Deactivated = append(diff.Deactivated, catalogEntryFromModel(sp))
+			plan.deactivatedIDs = append(plan.deactivatedIDs, sp.ID)
+		}
+	}
+	return diff, plan
+}
+
+// WriteCatalog encodes catalog entries in the given format
+func WriteCatalog(w io.Writer, format CatalogFormat, entries []CatalogEntry) error {
+	if format == CatalogFormatJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(entries)
+	}
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(catalogCSVHeader); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		record := []string{
+			entry.ServiceType,
+			entry.ItemName,
+			entry.Description,
+			strconv.FormatFloat(entry.Price, 'f', -1, 64),
+			strconv.FormatBool(entry.IsActive),
+		}
+		if err := writer.Write(record); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// ReadCatalog decodes catalog entries in the given format
+func ReadCatalog(r io.Reader, format CatalogFormat) ([]CatalogEntry, error) {
+	if format == CatalogFormatJSON {
+		var entries []CatalogEntry
+		if err := json.NewDecoder(r).Decode(&entries); err != nil {
+			return nil, utils.NewValidationError("file", "invalid catalog JSON: "+err.Error())
+		}
+		return entries, nil
+	}
+
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, utils.NewValidationError("file", "invalid catalog CSV: "+err.Error())
+	}
+	if len(records) == 0 {
+		return nil, utils.NewValidationError("file", "catalog CSV is empty")
+	}
+
+	columns := make(map[string]int, len(records[0]))
+	for i, name := range records[0] {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range catalogCSVHeader {
+		if _, ok := columns[name]; !ok {
+			return nil, utils.NewValidationError("file", fmt.Sprintf("catalog CSV is missing the %s column", name))
+		}
+	}
+
+	entries := make([]CatalogEntry, 0, len(records)-1)
+	for line, record := range records[1:] {
+		price, err := strconv.ParseFloat(strings.TrimSpace(record[columns["price"]]), 64)
+		if err != nil {
+			return nil, utils.NewValidationError(fmt.Sprintf("line %d", line+2), fmt.Sprintf("invalid price %q", record[columns["price"]]))
+		}
+		isActive, err := strconv.ParseBool(strings.TrimSpace(record[columns["is_active"]]))
+		if err != nil {
+			return nil, utils.NewValidationError(fmt.Sprintf("line %d", line+2), fmt.Sprintf("invalid is_active %q", record[columns["is_active"]]))
+		}
+		entries = append(entries, CatalogEntry{
+			ServiceType: strings.TrimSpace(record[columns["service_type"]]),
+			ItemName:    strings.TrimSpace(record[columns["item_name"]]),
+			Description: strings.TrimSpace(record[columns["description"]]),
+			Price:       price,
+			IsActive:    isActive,
+		})
+	}
+	return entries, nil
+}
+
+// validateCatalogEntries rejects entries with missing keys, non-positive prices or duplicates
+func validateCatalogEntries(entries []CatalogEntry) error {
+	if len(entries) == 0 {
+		return utils.NewValidationError("entries", "catalog must contain at least one entry")
+	}
+
+	seen := make(map[string]bool, len(entries))
+	for i, entry := range entries {
+		if entry.ServiceType == "" || entry.ItemName == "" {
+			return utils.NewValidationError(fmt.Sprintf("entry %d", i+1), "service_type and item_name are required")
+		}
+		if entry.Price <= 0 {
+			return utils.NewValidationError(fmt.Sprintf("entry %d", i+1), fmt.Sprintf("price for %s - %s must be greater than 0", entry.ServiceType, entry.ItemName))
+		}
+		key := catalogKey(entry.ServiceType, entry.ItemName)
+		if seen[key] {
+			return utils.NewValidationError(fmt.Sprintf("entry %d", i+1), fmt.Sprintf("duplicate service price %s - %s", entry.ServiceType, entry.ItemName))
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// catalogEntryFromModel converts a stored service price into a catalog entry
+func catalogEntryFromModel(sp models.ServicePrice) CatalogEntry {
+	return CatalogEntry{
+		ServiceType: sp.ServiceType,
+		ItemName:    sp.ItemName,
+		Description: sp.Description,
+		Price:       sp.Price,
+		IsActive:    sp.IsActive,
+	}
+}
+
+// catalogKey identifies a service price by service type and item name
+func catalogKey(serviceType, itemName string) string {
+	return serviceType + "\x00" + itemName
+}
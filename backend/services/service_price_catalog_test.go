@@ -0,0 +1,164 @@
+package services
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+func TestDiffCatalog(t *testing.T) {
+	current := []models.ServicePrice{
+		{ID: 1, ServiceType: "Cuci Kering", ItemName: "Kemeja", Description: "Per potong", Price: 8000, IsActive: true},
+		{ID: 2, ServiceType: "Cuci Kering", ItemName: "Celana", Price: 9000, IsActive: true},
+		{ID: 3, ServiceType: "Setrika", ItemName: "Kemeja", Price: 4000, IsActive: true},
+		{ID: 4, ServiceType: "Setrika", ItemName: "Jas", Price: 15000, IsActive: false},
+	}
+	entries := []CatalogEntry{
+		{ServiceType: "Cuci Kering", ItemName: "Kemeja", Description: "Per potong", Price: 8000, IsActive: true}, // unchanged
+		{ServiceType: "Cuci Kering", ItemName: "Celana", Price: 10000, IsActive: true},                           // new price
+		{ServiceType: "Setrika", ItemName: "Jas", Price: 15000, IsActive: true},                                  // reactivated
+		{ServiceType: "Cuci Basah", ItemName: "Selimut", Price: 25000, IsActive: true},                           // added
+		// Setrika - Kemeja is missing, so it is deactivated
+	}
+
+	diff, plan := diffCatalog(current, entries)
+
+	if want := []CatalogEntry{entries[3]}; !slices.Equal(diff.Added, want) {
+		t.Errorf("Added = %+v, want %+v", diff.Added, want)
+	}
+	wantChanged := []CatalogChange{
+		{ID: 2, Before: catalogEntryFromModel(current[1]), After: entries[1]},
+		{ID: 4, Before: catalogEntryFromModel(current[3]), After: entries[2]},
+	}
+	if !slices.Equal(diff.Changed, wantChanged) {
+		t.Errorf("Changed = %+v, want %+v", diff.Changed, wantChanged)
+	}
+	if want := []CatalogEntry{catalogEntryFromModel(current[2])}; !slices.Equal(diff.Deactivated, want) {
+		t.Errorf("Deactivated = %+v, want %+v", diff.Deactivated, want)
+	}
+	if diff.Unchanged != 1 {
+		t.Errorf("Unchanged = %d, want 1", diff.Unchanged)
+	}
+	if diff.Applied {
+		t.Error("a diff is not applied until the import writes it")
+	}
+
+	if len(plan.added) != 1 || plan.added[0].ID != 0 || plan.added[0].ItemName != "Selimut" || plan.added[0].Price != 25000 {
+		t.Errorf("plan.added = %+v, want the new Selimut price", plan.added)
+	}
+	if len(plan.changed) != 2 || plan.changed[0].ID != 2 || plan.changed[0].Price != 10000 || plan.changed[1].ID != 4 || !plan.changed[1].IsActive {
+		t.Errorf("plan.changed = %+v, want prices 2 and 4 after the import", plan.changed)
+	}
+	if !reflect.DeepEqual(plan.previous, []models.ServicePrice{current[1], current[3]}) {
+		t.Errorf("plan.previous = %+v, want prices 2 and 4 before the import", plan.previous)
+	}
+	if !slices.Equal(plan.deactivatedIDs, []uint{3}) {
+		t.Errorf("plan.deactivatedIDs = %v, want [3]", plan.deactivatedIDs)
+	}
+}
+
+func TestDiffCatalogLeavesInactivePricesAlone(t *testing.T) {
+	current := []models.ServicePrice{{ID: 1, ServiceType: "Setrika", ItemName: "Jas", Price: 15000, IsActive: false}}
+	diff, plan := diffCatalog(current, []CatalogEntry{{ServiceType: "Setrika", ItemName: "Kemeja", Price: 4000, IsActive: true}})
+	if len(diff.Deactivated) != 0 || len(plan.deactivatedIDs) != 0 {
+		t.Errorf("an inactive price missing from the import was deactivated again: %+v", diff.Deactivated)
+	}
+}
+
+func TestCatalogRoundTrip(t *testing.T) {
+	entries := []CatalogEntry{
+		{ServiceType: "Cuci Kering", ItemName: "Kemeja, lengan panjang", Description: "Per \"potong\"", Price: 8500.5, IsActive: true},
+		{ServiceType: "Setrika", ItemName: "Jas", Price: 15000, IsActive: false},
+	}
+	for _, format := range []CatalogFormat{CatalogFormatCSV, CatalogFormatJSON} {
+		var buf bytes.Buffer
+		if err := WriteCatalog(&buf, format, entries); err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		got, err := ReadCatalog(&buf, format)
+		if err != nil {
+			t.Fatalf("%s: %v", format, err)
+		}
+		if !slices.Equal(got, entries) {
+			t.Errorf("%s round trip = %+v, want %+v", format, got, entries)
+		}
+	}
+}
+
+func TestReadCatalogCSV(t *testing.T) {
+	csv := "Item_Name, price ,is_active,service_type,description\n" +
+		" Kemeja , 8000 , TRUE , Setrika ,  rapi \n" +
+		"Jas,15000,0,Setrika,\n"
+	got, err := ReadCatalog(strings.NewReader(csv), CatalogFormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []CatalogEntry{
+		{ServiceType: "Setrika", ItemName: "Kemeja", Description: "rapi", Price: 8000, IsActive: true},
+		{ServiceType: "Setrika", ItemName: "Jas", Price: 15000, IsActive: false},
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ReadCatalog = %+v, want %+v", got, want)
+	}
+}
+
+func TestReadCatalogErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  CatalogFormat
+		content string
+		field   string
+		message string
+	}{
+		{"empty CSV", CatalogFormatCSV, "", "file", "catalog CSV is empty"},
+		{"missing column", CatalogFormatCSV, "service_type,item_name,price,is_active\n", "file", "catalog CSV is missing the description column"},
+		{"bad price", CatalogFormatCSV, "service_type,item_name,description,price,is_active\nSetrika,Jas,,lima,true\n", "line 2", `invalid price "lima"`},
+		{"bad is_active", CatalogFormatCSV, "service_type,item_name,description,price,is_active\nSetrika,Jas,,5000,ya\n", "line 2", `invalid is_active "ya"`},
+		{"ragged CSV", CatalogFormatCSV, "service_type,item_name,description,price,is_active\nSetrika,Jas\n", "file", ""},
+		{"bad JSON", CatalogFormatJSON, `[{"service_type": "Setrika"`, "file", ""},
+		{"JSON price as text", CatalogFormatJSON, `[{"service_type": "Setrika", "item_name": "Jas", "price": "5000"}]`, "file", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ReadCatalog(strings.NewReader(tt.content), tt.format)
+			var validation *utils.ValidationError
+			if !errors.As(err, &validation) {
+				t.Fatalf("err = %v, want a validation error", err)
+			}
+			if got := validation.Fields[0]; got.Field != tt.field || !strings.Contains(got.Message, tt.message) {
+				t.Errorf("error = %s: %s, want %s: %s", got.Field, got.Message, tt.field, tt.message)
+			}
+		})
+	}
+}
+
+func TestValidateCatalogEntries(t *testing.T) {
+	valid := CatalogEntry{ServiceType: "Setrika", ItemName: "Jas", Price: 15000}
+	tests := []struct {
+		name    string
+		entries []CatalogEntry
+		field   string
+	}{
+		{"empty", nil, "entries"},
+		{"missing item name", []CatalogEntry{{ServiceType: "Setrika", Price: 1000}}, "entry 1"},
+		{"zero price", []CatalogEntry{valid, {ServiceType: "Setrika", ItemName: "Kemeja"}}, "entry 2"},
+		{"duplicate", []CatalogEntry{valid, {ServiceType: "Cuci", ItemName: "Jas", Price: 1}, valid}, "entry 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var validation *utils.ValidationError
+			if err := validateCatalogEntries(tt.entries); !errors.As(err, &validation) || validation.Fields[0].Field != tt.field {
+				t.Errorf("err = %v, want a validation error on %s", err, tt.field)
+			}
+		})
+	}
+	if err := validateCatalogEntries([]CatalogEntry{valid}); err != nil {
+		t.Errorf("valid entries: %v", err)
+	}
+}
@@ -1,67 +1,77 @@
 package services
 
 import (
+	"context"
 	"fmt"
 
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
 )
 
 // ServicePriceService handles service price business logic
 type ServicePriceService struct {
 	servicePriceRepo *repositories.ServicePriceRepository
+	transactor       *repositories.Transactor
+	events           *EventService // optional, price changes are not published when nil
+	audit            *AuditService
 }
 
 // NewServicePriceService creates a new service price service
-func NewServicePriceService(servicePriceRepo *repositories.ServicePriceRepository) *ServicePriceService {
-	return &ServicePriceService{servicePriceRepo: servicePriceRepo}
+func NewServicePriceService(servicePriceRepo *repositories.ServicePriceRepository, transactor *repositories.Transactor, events *EventService, audit *AuditService) *ServicePriceService {
+	return &ServicePriceService{servicePriceRepo: servicePriceRepo, transactor: transactor, events: events, audit: audit}
 }
 
 // CreateServicePrice creates a new service price
-func (s *ServicePriceService) CreateServicePrice(servicePrice *models.ServicePrice) error {
+func (s *ServicePriceService) CreateServicePrice(ctx context.Context, servicePrice *models.ServicePrice) error {
 	// Check if service price already exists
-	existing, err := s.servicePriceRepo.GetServicePriceByTypeAndItem(servicePrice.ServiceType, servicePrice.ItemName)
+	existing, err := s.servicePriceRepo.GetServicePriceByTypeAndItem(ctx, servicePrice.ServiceType, servicePrice.ItemName)
 	if err != nil {
 		return fmt.Errorf("failed to check existing service price: %w", err)
 	}
 	if existing != nil {
-		return fmt.Errorf("service price for %s - %s already exists", servicePrice.ServiceType, servicePrice.ItemName)
+		return utils.NewError(utils.ErrConflict, "service price for %s - %s already exists", servicePrice.ServiceType, servicePrice.ItemName)
 	}
 
-	err = s.servicePriceRepo.CreateServicePrice(servicePrice)
-	if err != nil {
-		return fmt.Errorf("failed to create service price: %w", err)
-	}
-	return nil
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.servicePriceRepo.CreateServicePrice(ctx, servicePrice); err != nil {
+			return fmt.Errorf("failed to create service price: %w", err)
+		}
+		if err := s.audit.Record(ctx, priceAudit(models.AuditCreate, nil, servicePrice)); err != nil {
+			return err
+		}
+		return s.publish(ctx, events.PriceChanged{Change: events.PriceCreated, ServicePrice: *servicePrice})
+	})
 }
 
 // GetServicePrice retrieves a service price by ID
-func (s *ServicePriceService) GetServicePrice(id uint) (*models.ServicePrice, error) {
-	servicePrice, err := s.servicePriceRepo.GetServicePriceByID(id)
+func (s *ServicePriceService) GetServicePrice(ctx context.Context, id uint) (*models.ServicePrice, error) {
+	servicePrice, err := s.servicePriceRepo.GetServicePriceByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve service price: %w", err)
 	}
 	if servicePrice == nil {
-		return nil, fmt.Errorf("service price not found")
+		return nil, utils.NewError(utils.ErrNotFound, "service price not found")
 	}
 	return servicePrice, nil
 }
 
 // GetServicePriceByTypeAndItem retrieves a service price by service type and item name
-func (s *ServicePriceService) GetServicePriceByTypeAndItem(serviceType, itemName string) (*models.ServicePrice, error) {
-	servicePrice, err := s.servicePriceRepo.GetServicePriceByTypeAndItem(serviceType, itemName)
+func (s *ServicePriceService) GetServicePriceByTypeAndItem(ctx context.Context, serviceType, itemName string) (*models.ServicePrice, error) {
+	servicePrice, err := s.servicePriceRepo.GetServicePriceByTypeAndItem(ctx, serviceType, itemName)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve service price: %w", err)
 	}
 	if servicePrice == nil {
-		return nil, fmt.Errorf("service price not found for %s - %s", serviceType, itemName)
+		return nil, utils.NewError(utils.ErrNotFound, "service price not found for %s - %s", serviceType, itemName)
 	}
 	return servicePrice, nil
 }
 
 // GetAllServicePrices retrieves all active service prices
-func (s *ServicePriceService) GetAllServicePrices() ([]models.ServicePrice, error) {
-	servicePrices, err := s.servicePriceRepo.GetAllServicePrices()
+func (s *ServicePriceService) GetAllServicePrices(ctx context.Context) ([]models.ServicePrice, error) {
+	servicePrices, err := s.servicePriceRepo.GetAllServicePrices(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve service prices: %w", err)
 	}
@@ -69,8 +79,8 @@ func (s *ServicePriceService) GetAllServicePrices() ([]models.ServicePrice, erro
 }
 
 // GetServicePricesByType retrieves all service prices by service type
-func (s *ServicePriceService) GetServicePricesByType(serviceType string) ([]models.ServicePrice, error) {
-	servicePrices, err := s.servicePriceRepo.GetServicePricesByType(serviceType)
+func (s *ServicePriceService) GetServicePricesByType(ctx context.Context, serviceType string) ([]models.ServicePrice, error) {
+	servicePrices, err := s.servicePriceRepo.GetServicePricesByType(ctx, serviceType)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve service prices: %w", err)
 	}
@@ -78,8 +88,8 @@ func (s *ServicePriceService) GetServicePricesByType(serviceType string) ([]mode
 }
 
 // GetServiceTypes retrieves all unique service types
-func (s *ServicePriceService) GetServiceTypes() ([]string, error) {
-	serviceTypes, err := s.servicePriceRepo.GetServiceTypes()
+func (s *ServicePriceService) GetServiceTypes(ctx context.Context) ([]string, error) {
+	serviceTypes, err := s.servicePriceRepo.GetServiceTypes(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve service types: %w", err)
 	}
@@ -87,58 +97,158 @@ func (s *ServicePriceService) GetServiceTypes() ([]string, error) {
 }
 
 // UpdateServicePrice updates a service price
-func (s *ServicePriceService) UpdateServicePrice(servicePrice *models.ServicePrice) error {
+func (s *ServicePriceService) UpdateServicePrice(ctx context.Context, servicePrice *models.ServicePrice) error {
 	// Check if service price exists
-	existing, err := s.servicePriceRepo.GetServicePriceByID(servicePrice.ID)
+	existing, err := s.servicePriceRepo.GetServicePriceByID(ctx, servicePrice.ID)
 	if err != nil {
 		return fmt.Errorf("failed to check existing service price: %w", err)
 	}
 	if existing == nil {
-		return fmt.Errorf("service price not found")
+		return utils.NewError(utils.ErrNotFound, "service price not found")
 	}
 
-	err = s.servicePriceRepo.UpdateServicePrice(servicePrice)
-	if err != nil {
-		return fmt.Errorf("failed to update service price: %w", err)
-	}
-	return nil
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.servicePriceRepo.UpdateServicePrice(ctx, servicePrice); err != nil {
+			return fmt.Errorf("failed to update service price: %w", err)
+		}
+		if err := s.audit.Record(ctx, priceAudit(models.AuditUpdate, existing, servicePrice)); err != nil {
+			return err
+		}
+		return s.publish(ctx, events.PriceChanged{Change: events.PriceUpdated, ServicePrice: *servicePrice, Previous: existing})
+	})
 }
 
-// DeleteServicePrice deletes a service price
-func (s *ServicePriceService) DeleteServicePrice(id uint) error {
-	err := s.servicePriceRepo.DeleteServicePrice(id)
+// DeleteServicePrice moves a service price to the trash with the reason it was deleted. It can be
+// restored until it is purged.
+func (s *ServicePriceService) DeleteServicePrice(ctx context.Context, id uint, reason string) error {
+	reason, err := deleteReason(reason)
 	if err != nil {
-		return fmt.Errorf("failed to delete service price: %w", err)
+		return err
 	}
-	return nil
+	existing, err := s.servicePriceRepo.GetServicePriceByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to check existing service price: %w", err)
+	}
+	if existing == nil {
+		return utils.NewError(utils.ErrNotFound, "service price not found")
+	}
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.servicePriceRepo.DeleteServicePrice(ctx, id, deletedBy(ctx), reason); err != nil {
+			return fmt.Errorf("failed to delete service price: %w", err)
+		}
+		entry := priceAudit(models.AuditDelete, existing, nil)
+		entry.Changes = models.AuditChanges{"delete_reason": {To: reason}}
+		if err := s.audit.Record(ctx, entry); err != nil {
+			return err
+		}
+		return s.publish(ctx, events.PriceChanged{Change: events.PriceDeleted, ServicePrice: *existing})
+	})
 }
 
 // DeactivateServicePrice deactivates a service price
-func (s *ServicePriceService) DeactivateServicePrice(id uint) error {
-	err := s.servicePriceRepo.DeactivateServicePrice(id)
-	if err != nil {
-		return fmt.Errorf("failed to deactivate service price: %w", err)
-	}
-	return nil
+func (s *ServicePriceService) DeactivateServicePrice(ctx context.Context, id uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.servicePriceRepo.GetServicePriceByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve service price: %w", err)
+		}
+		if err := s.servicePriceRepo.DeactivateServicePrice(ctx, id); err != nil {
+			return fmt.Errorf("failed to deactivate service price: %w", err)
+		}
+		return s.recordStored(ctx, models.AuditDeactivate, events.PriceDeactivated, before)
+	})
 }
 
 // ActivateServicePrice activates a service price
-func (s *ServicePriceService) ActivateServicePrice(id uint) error {
-	err := s.servicePriceRepo.ActivateServicePrice(id)
+func (s *ServicePriceService) ActivateServicePrice(ctx context.Context, id uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		before, err := s.servicePriceRepo.GetServicePriceByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve service price: %w", err)
+		}
+		if err := s.servicePriceRepo.ActivateServicePrice(ctx, id); err != nil {
+			return fmt.Errorf("failed to activate service price: %w", err)
+		}
+		return s.recordStored(ctx, models.AuditActivate, events.PriceActivated, before)
+	})
+}
+
+// recordStored audits and publishes a price change with the stored state of a service price
+func (s *ServicePriceService) recordStored(ctx context.Context, action models.AuditAction, change string, before *models.ServicePrice) error {
+	if before == nil {
+		return nil
+	}
+	servicePrice, err := s.servicePriceRepo.GetServicePriceByID(ctx, before.ID)
 	if err != nil {
-		return fmt.Errorf("failed to activate service price: %w", err)
+		return fmt.Errorf("failed to retrieve service price: %w", err)
+	}
+	if servicePrice == nil {
+		return nil
+	}
+	if err := s.audit.Record(ctx, priceAudit(action, before, servicePrice)); err != nil {
+		return err
+	}
+	return s.publish(ctx, events.PriceChanged{Change: change, ServicePrice: *servicePrice})
+}
+
+// priceAudit describes a change of a service price for the audit log; before or after is nil
+// when the price was created or deleted
+func priceAudit(action models.AuditAction, before, after *models.ServicePrice) AuditEntry {
+	current := after
+	if current == nil {
+		current = before
+	}
+	return AuditEntry{
+		Action: action, EntityType: models.AuditEntityServicePrice, EntityID: current.ID,
+		Label: current.ServiceType + " - " + current.ItemName, Before: before, After: after,
+	}
+}
+
+// publish stores events in the outbox with the change being made in ctx
+func (s *ServicePriceService) publish(ctx context.Context, evs ...events.Event) error {
+	if s.events == nil {
+		return nil
+	}
+	return s.events.Publish(ctx, evs...)
+}
+
+// SeedServicePrices creates the given service prices, skipping any service type and item pair that already exists
+func (s *ServicePriceService) SeedServicePrices(ctx context.Context, servicePrices []models.ServicePrice) (created int, skipped int, err error) {
+	for i := range servicePrices {
+		sp := &servicePrices[i]
+
+		existing, err := s.servicePriceRepo.FindServicePriceByTypeAndItem(ctx, sp.ServiceType, sp.ItemName)
+		if err != nil {
+			return created, skipped, fmt.Errorf("failed to check existing service price: %w", err)
+		}
+		if existing != nil {
+			skipped++
+			continue
+		}
+
+		err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+			if err := s.servicePriceRepo.CreateServicePrice(ctx, sp); err != nil {
+				return fmt.Errorf("failed to create service price %s - %s: %w", sp.ServiceType, sp.ItemName, err)
+			}
+			return s.audit.Record(ctx, priceAudit(models.AuditCreate, nil, sp))
+		})
+		if err != nil {
+			return created, skipped, err
+		}
+		created++
 	}
-	return nil
+	return created, skipped, nil
 }
 
 // ValidatePrice validates if the provided price matches the database price
-func (s *ServicePriceService) ValidatePrice(serviceType, itemName string, providedPrice float64) (bool, float64, error) {
-	servicePrice, err := s.servicePriceRepo.GetServicePriceByTypeAndItem(serviceType, itemName)
+func (s *ServicePriceService) ValidatePrice(ctx context.Context, serviceType, itemName string, providedPrice float64) (bool, float64, error) {
+	servicePrice, err := s.servicePriceRepo.GetServicePriceByTypeAndItem(ctx, serviceType, itemName)
 	if err != nil {
 		return false, 0, fmt.Errorf("failed to retrieve service price: %w", err)
 	}
 	if servicePrice == nil {
-		return false, 0, fmt.Errorf("service price not found for %s - %s", serviceType, itemName)
+		return false, 0, utils.NewError(utils.ErrNotFound, "service price not found for %s - %s", serviceType, itemName)
 	}
 
 	// Check if provided price matches database price
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// ShiftFilter narrows a shift listing
+type ShiftFilter = repositories.ShiftFilter
+
+// ShiftReport is the cash reconciliation of a shift. For an open shift the expected cash is
+// computed live; once closed, the stored closing figures are reported and never change.
+type ShiftReport struct {
+	Shift        *models.Shift              `json:"shift"`
+	Admin        string                     `json:"admin"`
+	Locked       bool                       `json:"locked"`
+	Totals       []repositories.MethodTotal `json:"totals"`
+	ExpectedCash float64                    `json:"expected_cash"`
+	CountedCash  *float64                   `json:"counted_cash"`
+	Variance     *float64                   `json:"variance"`
+	Payments     []models.Payment           `json:"payments"`
+}
+
+// ShiftService handles cashier shifts and their closing
+type ShiftService struct {
+	shiftRepo  *repositories.ShiftRepository
+	transactor *repositories.Transactor
+	audit      *AuditService
+}
+
+// NewShiftService creates a new shift service
+func NewShiftService(shiftRepo *repositories.ShiftRepository, transactor *repositories.Transactor, audit *AuditService) *ShiftService {
+	return &ShiftService{shiftRepo: shiftRepo, transactor: transactor, audit: audit}
+}
+
+// OpenShift starts a shift for an admin with the cash already in the drawer
+func (s *ShiftService) OpenShift(ctx context.Context, adminID uint, openingCash float64, notes string) (*models.Shift, error) {
+	open, err := s.shiftRepo.GetOpenShift(ctx, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve open shift: %w", err)
+	}
+	if open != nil {
+		return nil, utils.NewError(utils.ErrConflict, "shift #%d is still open, close it first", open.ID)
+	}
+
+	shift := &models.Shift{
+		AdminID:      adminID,
+		OpenedAt:     time.Now(),
+		OpeningCash:  openingCash,
+		OpeningNotes: notes,
+	}
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.shiftRepo.CreateShift(ctx, shift); err != nil {
+			if repositories.IsDuplicateKey(err) {
+				return utils.NewError(utils.ErrConflict, "a shift is already open, close it first")
+			}
+			return fmt.Errorf("failed to open shift: %w", err)
+		}
+		return s.audit.Record(ctx, AuditEntry{
+			Action: models.AuditCreate, EntityType: models.AuditEntityShift, EntityID: shift.ID, Label: shiftLabel(shift), After: shift,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return shift, nil
+}
+
+// GetCurrentShift returns the report of the admin's open shift
+func (s *ShiftService) GetCurrentShift(ctx context.Context, adminID uint) (*ShiftReport, error) {
+	shift, err := s.shiftRepo.GetOpenShift(ctx, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve open shift: %w", err)
+	}
+	if shift == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "no open shift")
+	}
+	return s.buildReport(ctx, shift)
+}
+
+// CloseCurrentShift closes the admin's open shift with the cash counted in the drawer and
+// returns its locked closing report
+func (s *ShiftService) CloseCurrentShift(ctx context.Context, adminID uint, countedCash float64, notes string) (*ShiftReport, error) {
+	open, err := s.shiftRepo.GetOpenShift(ctx, adminID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve open shift: %w", err)
+	}
+	if open == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "no open shift")
+	}
+
+	var shift *models.Shift
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		var err error
+		shift, err = s.shiftRepo.CloseShift(ctx, open.ID, countedCash, notes, time.Now())
+		switch {
+		case errors.Is(err, repositories.ErrShiftClosed):
+			return utils.NewError(utils.ErrConflict, "shift #%d is already closed", open.ID)
+		case err != nil:
+			return fmt.Errorf("failed to close shift: %w", err)
+		case shift == nil:
+			return utils.NewError(utils.ErrNotFound, "shift not found")
+		}
+		return s.audit.Record(ctx, AuditEntry{
+			Action: models.AuditClose, EntityType: models.AuditEntityShift, EntityID: shift.ID, Label: shiftLabel(open),
+			Before: open, After: shift,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	shift.Admin = open.Admin
+	return s.buildReport(ctx, shift)
+}
+
+// GetShiftReport returns the report of any shift
+func (s *ShiftService) GetShiftReport(ctx context.Context, id uint) (*ShiftReport, error) {
+	shift, err := s.shiftRepo.GetShiftByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shift: %w", err)
+	}
+	if shift == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "shift not found")
+	}
+	return s.buildReport(ctx, shift)
+}
+
+// ListShifts returns the shifts matching the filter, latest first, and their total count
+func (s *ShiftService) ListShifts(ctx context.Context, filter ShiftFilter) ([]models.Shift, int64, error) {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return nil, 0, utils.NewValidationError("to", "must be after from")
+	}
+
+	shifts, total, err := s.shiftRepo.ListShifts(ctx, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve shifts: %w", err)
+	}
+	return shifts, total, nil
+}
+
+// buildReport gathers the payments of a shift and reconciles its cash
+func (s *ShiftService) buildReport(ctx context.Context, shift *models.Shift) (*ShiftReport, error) {
+	totals, err := s.shiftRepo.GetPaymentTotals(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sum shift payments: %w", err)
+	}
+	payments, err := s.shiftRepo.GetShiftPayments(ctx, shift.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shift payments: %w", err)
+	}
+
+	report := &ShiftReport{
+		Shift:       shift,
+		Locked:      shift.IsClosed(),
+		Totals:      totals,
+		CountedCash: shift.CountedCash,
+		Variance:    shift.Variance,
+		Payments:    payments,
+	}
+	if shift.Admin != nil {
+		report.Admin = shift.Admin.Username
+	}
+
+	if shift.ExpectedCash != nil {
+		report.ExpectedCash = *shift.ExpectedCash
+	} else {
+		report.ExpectedCash = shift.OpeningCash
+		for _, total := range totals {
+			if total.Method == models.PaymentCash {
+				report.ExpectedCash += total.Amount
+			}
+		}
+	}
+	return report, nil
+}
+
+// shiftLabel names a shift in the audit log
+func shiftLabel(shift *models.Shift) string {
+	return fmt.Sprintf("shift #%d", shift.ID)
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+const (
+	// streamBatch is how many outbox events the stream tailer reads at once
+	streamBatch = 500
+	// streamReplayLimit is how many missed events a reconnecting client is sent at most
+	streamReplayLimit = 500
+	// streamBuffer is how many events a client may lag behind before its stream is ended
+	streamBuffer = 64
+)
+
+// boardEvents are the events sent to the order board
+var boardEvents = []string{events.NameTransactionCreated, events.NameStatusChanged}
+
+// StreamSettings configures the live event streams
+type StreamSettings struct {
+	PollInterval time.Duration
+	MaxClients   int
+	// GapTimeout is how long the tailer waits for an outbox ID that was skipped, e.g. by a
+	// transaction still committing, before assuming it was rolled back. Events after the gap
+	// are delayed by up to this long.
+	GapTimeout time.Duration
+}
+
+// StreamEvent is an event sent to a live stream. Its ID is the outbox ID, which increases with
+// every event, so a client reconnecting with the last ID it saw gets only what it missed.
+type StreamEvent struct {
+	ID              uint
+	Name            string
+	TransactionCode string
+	Board           any // data for the order board
+	Tracking        any // data for the public tracking page, nil when the event is not public
+}
+
+// BoardStatusChange is the order board data of transaction.status_changed
+type BoardStatusChange struct {
+	Transaction    models.Transaction       `json:"transaction"`
+	PreviousStatus models.TransactionStatus `json:"previous_status"`
+	ChangedBy      string                   `json:"changed_by"`
+	Reason         string                   `json:"reason"`
+	ChangedAt      time.Time                `json:"changed_at"`
+}
+
+// TrackingStatusChange is the public tracking data of transaction.status_changed
+type TrackingStatusChange struct {
+	TransactionCode string                   `json:"transaction_code"`
+	PreviousStatus  models.TransactionStatus `json:"previous_status"`
+	Status          models.TransactionStatus `json:"status"`
+	ChangedAt       time.Time                `json:"changed_at"`
+}
+
+// StreamSubscription receives the live events of one stream
+type StreamSubscription struct {
+	// Events delivers the events in order. It is closed when the client fell too far behind or
+	// the service is shutting down; the client then reconnects and catches up through Replay.
+	Events <-chan StreamEvent
+
+	events chan StreamEvent
+	code   string // public tracking stream of this transaction, or the board when empty
+}
+
+// StreamService feeds the order board and the tracking pages live. It tails the outbox, so
+// clients see events published by every server instance, not only their own.
+type StreamService struct {
+	outboxRepo *repositories.OutboxRepository
+	settings   StreamSettings
+	wake       chan struct{}
+
+	mu       sync.Mutex
+	subs     map[*StreamSubscription]struct{}
+	closed   bool
+	position uint // every event up to this ID has been broadcast, 0 until the tailer started
+}
+
+// NewStreamService creates a new stream service
+func NewStreamService(outboxRepo *repositories.OutboxRepository, settings StreamSettings) *StreamService {
+	return &StreamService{
+		outboxRepo: outboxRepo,
+		settings:   settings,
+		wake:       make(chan struct{}, 1),
+		subs:       make(map[*StreamSubscription]struct{}),
+	}
+}
+
+// Subscribe wakes the tailer up when this instance dispatches a streamed event, so local
+// changes reach the streams without waiting for the next poll
+func (s *StreamService) Subscribe(bus *events.Bus) {
+	wake := func(context.Context, events.Envelope) error {
+		s.signal()
+		return nil
+	}
+	for _, name := range boardEvents {
+		bus.Subscribe("streams", name, wake)
+	}
+}
+
+// signal wakes the tailer up without waiting for the next poll
+func (s *StreamService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Open subscribes a client to the order board, or to the public tracking stream of the
+// transaction with the given code. Close the subscription when the client leaves.
+func (s *StreamService) Open(code string) (*StreamSubscription, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.closed {
+		return nil, utils.NewError(utils.ErrUnavailable, "the server is shutting down")
+	}
+	if len(s.subs) >= s.settings.MaxClients {
+		return nil, utils.NewError(utils.ErrUnavailable, "too many open streams, try again later")
+	}
+
+	ch := make(chan StreamEvent, streamBuffer)
+	sub := &StreamSubscription{Events: ch, events: ch, code: code}
+	s.subs[sub] = struct{}{}
+	return sub, nil
+}
+
+// Close unsubscribes a client
+func (s *StreamService) Close(sub *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.subs[sub]; ok {
+		delete(s.subs, sub)
+		close(sub.events)
+	}
+}
+
+// Shutdown ends every open stream and refuses new ones, so the HTTP server can stop
+func (s *StreamService) Shutdown() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subs {
+		delete(s.subs, sub)
+		close(sub.events)
+	}
+}
+
+// Clients returns the number of open streams
+func (s *StreamService) Clients() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.subs)
+}
+
+// Replay returns the events of a stream after the given ID, oldest first. truncated reports
+// that more were missed than can be replayed; nothing is returned then and the client should
+// reload instead. Only events the tailer has broadcast are replayed, so the later ones arrive
+// live and in ID order after the replay.
+func (s *StreamService) Replay(ctx context.Context, code string, afterID uint) (replayed []StreamEvent, truncated bool, err error) {
+	s.mu.Lock()
+	position := s.position
+	s.mu.Unlock()
+
+	filter := repositories.OutboxFilter{AfterID: afterID, UpToID: position, Names: boardEvents, Limit: streamReplayLimit + 1}
+	if code != "" {
+		filter.Names = []string{events.NameStatusChanged}
+		filter.TransactionCode = code
+	}
+	rows, err := s.outboxRepo.ListEvents(ctx, filter)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to retrieve missed events: %w", err)
+	}
+	if len(rows) > streamReplayLimit {
+		return nil, true, nil
+	}
+
+	for _, row := range rows {
+		if event, ok := streamEvent(row); ok && (code == "" || event.Tracking != nil) {
+			replayed = append(replayed, event)
+		}
+	}
+	return replayed, truncated, nil
+}
+
+// Run tails the outbox and broadcasts new events to the open streams until ctx is cancelled
+func (s *StreamService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.settings.PollInterval)
+	defer ticker.Stop()
+
+	// Events older than the start are only sent as replays
+	var cursor uint
+	started := false
+	for {
+		if !started {
+			id, err := s.outboxRepo.LatestID(ctx)
+			if err == nil {
+				cursor, started = id, true
+				s.setPosition(cursor)
+			} else if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to start the event stream tailer", slog.String("error", err.Error()))
+			}
+		} else {
+			cursor = s.poll(ctx, cursor)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// poll broadcasts the outbox events after cursor in ID order and returns the new cursor
+func (s *StreamService) poll(ctx context.Context, cursor uint) uint {
+	rows, err := s.outboxRepo.ListEvents(ctx, repositories.OutboxFilter{AfterID: cursor, Limit: streamBatch})
+	if err != nil {
+		if ctx.Err() == nil {
+			slog.ErrorContext(ctx, "failed to read the event outbox", slog.String("error", err.Error()))
+		}
+		return cursor
+	}
+
+	ready, cursor := streamReady(cursor, rows, time.Now(), s.settings.GapTimeout)
+	for _, row := range ready {
+		if event, ok := streamEvent(row); ok {
+			s.broadcast(event)
+		}
+	}
+	s.setPosition(cursor)
+	return cursor
+}
+
+// streamReady returns the rows after cursor that can be broadcast, in ID order, and the new
+// cursor. Outbox IDs are allocated before commit, so an ID may appear after a higher one: rows
+// after a missing ID are held back until it appears, or until they are older than gapTimeout
+// and the missing ID is assumed rolled back. Clients never see IDs out of order.
+func streamReady(cursor uint, rows []models.OutboxEvent, now time.Time, gapTimeout time.Duration) ([]models.OutboxEvent, uint) {
+	var ready []models.OutboxEvent
+	for _, row := range rows {
+		if row.ID != cursor+1 && now.Sub(row.CreatedAt) < gapTimeout {
+			break
+		}
+		ready = append(ready, row)
+		cursor = row.ID
+	}
+	return ready, cursor
+}
+
+// setPosition records that every event up to id has been broadcast
+func (s *StreamService) setPosition(id uint) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.position = id
+}
+
+// broadcast sends an event to the streams it belongs to. The stream of a client too far behind
+// to take it is ended, so one slow client never holds up the others.
+func (s *StreamService) broadcast(event StreamEvent) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for sub := range s.subs {
+		if sub.code != "" && (sub.code != event.TransactionCode || event.Tracking == nil) {
+			continue
+		}
+		select {
+		case sub.events <- event:
+		default:
+			delete(s.subs, sub)
+			close(sub.events)
+		}
+	}
+}
+
+// streamEvent turns an outbox event into a stream event, reporting false for events that are not streamed
+func streamEvent(row models.OutboxEvent) (StreamEvent, bool) {
+	if row.Name != events.NameTransactionCreated && row.Name != events.NameStatusChanged {
+		return StreamEvent{}, false
+	}
+	decoded, err := events.Decode(row.Name, row.Payload)
+	if err != nil {
+		slog.Error("failed to decode streamed event",
+			slog.String("event_id", row.EventID),
+			slog.String("error", err.Error()),
+		)
+		return StreamEvent{}, false
+	}
+
+	switch e := decoded.(type) {
+	case events.TransactionCreated:
+		return StreamEvent{ID: row.ID, Name: row.Name, TransactionCode: e.Transaction.TransactionCode, Board: e.Transaction}, true
+	case events.StatusChanged:
+		return StreamEvent{
+			ID:              row.ID,
+			Name:            row.Name,
+			TransactionCode: e.Transaction.TransactionCode,
+			Board: BoardStatusChange{
+				Transaction:    e.Transaction,
+				PreviousStatus: e.PreviousStatus,
+				ChangedBy:      e.ChangedBy,
+				Reason:         e.Reason,
+				ChangedAt:      row.OccurredAt,
+			},
+			Tracking: TrackingStatusChange{
+				TransactionCode: e.Transaction.TransactionCode,
+				PreviousStatus:  e.PreviousStatus,
+				Status:          e.Transaction.Status,
+				ChangedAt:       row.OccurredAt,
+			},
+		}, true
+	}
+	return StreamEvent{}, false
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+)
+
+// testGapTimeout is the gap timeout the streamReady tests run with
+const testGapTimeout = 10 * time.Second
+
+// outboxRows returns outbox rows with the given IDs, created at createdAt
+func outboxRows(createdAt time.Time, ids ...uint) []models.OutboxEvent {
+	rows := make([]models.OutboxEvent, len(ids))
+	for i, id := range ids {
+		rows[i] = models.OutboxEvent{ID: id, CreatedAt: createdAt}
+	}
+	return rows
+}
+
+// rowIDs returns the IDs of rows
+func rowIDs(rows []models.OutboxEvent) []uint {
+	var ids []uint
+	for _, row := range rows {
+		ids = append(ids, row.ID)
+	}
+	return ids
+}
+
+// TestStreamReadyHoldsBackAfterGap feeds ID 11 while 10 is still committing, then 10: both
+// must be broadcast, 10 before 11
+func TestStreamReadyHoldsBackAfterGap(t *testing.T) {
+	now := time.Now()
+
+	ready, cursor := streamReady(9, outboxRows(now, 11), now, testGapTimeout)
+	if len(ready) != 0 || cursor != 9 {
+		t.Fatalf("with 10 missing got ready %v and cursor %d, want nothing and 9", rowIDs(ready), cursor)
+	}
+
+	ready, cursor = streamReady(cursor, outboxRows(now, 10, 11), now.Add(time.Second), testGapTimeout)
+	if want := []uint{10, 11}; !slices.Equal(rowIDs(ready), want) || cursor != 11 {
+		t.Fatalf("after 10 committed got ready %v and cursor %d, want %v and 11", rowIDs(ready), cursor, want)
+	}
+}
+
+func TestStreamReady(t *testing.T) {
+	now := time.Now()
+	old := now.Add(-testGapTimeout - time.Second)
+
+	tests := []struct {
+		name       string
+		cursor     uint
+		rows       []models.OutboxEvent
+		gapTimeout time.Duration
+		wantReady  []uint
+		wantCursor uint
+	}{
+		{"nothing new", 5, nil, testGapTimeout, nil, 5},
+		{"contiguous", 5, outboxRows(now, 6, 7, 8), testGapTimeout, []uint{6, 7, 8}, 8},
+		{"stops at a recent gap", 5, outboxRows(now, 6, 8, 9), testGapTimeout, []uint{6}, 6},
+		{"skips a gap after the timeout", 5, outboxRows(old, 7, 8), testGapTimeout, []uint{7, 8}, 8},
+		{"holds back recent rows behind an old gap", 5, append(outboxRows(old, 7), outboxRows(now, 9)...), testGapTimeout, []uint{7}, 7},
+		{"shorter timeout", 5, outboxRows(now.Add(-2*time.Second), 7, 8), time.Second, []uint{7, 8}, 8},
+		{"no timeout", 5, outboxRows(now, 7, 9), 0, []uint{7, 9}, 9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ready, cursor := streamReady(tt.cursor, tt.rows, now, tt.gapTimeout)
+			if !slices.Equal(rowIDs(ready), tt.wantReady) || cursor != tt.wantCursor {
+				t.Errorf("got ready %v and cursor %d, want %v and %d", rowIDs(ready), cursor, tt.wantReady, tt.wantCursor)
+			}
+		})
+	}
+}
@@ -0,0 +1,67 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/export"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+)
+
+// exportBatchSize is the number of transactions read per query while exporting
+const exportBatchSize = 500
+
+// transactionExportHeader lists the export columns. Each item gets its own row, so the
+// transaction columns repeat per item: sum subtotal, not total_price, for item totals.
+var transactionExportHeader = []any{
+	"transaction_code", "created_at", "customer_name", "customer_phone", "customer_address",
+	"status", "is_paid", "total_price", "pickup_date", "completed_at", "admin",
+	"service_type", "item_name", "quantity", "unit_price", "subtotal",
+}
+
+// ExportTransactions writes the transactions matching the filter to w, one row per item
+// (one row for a transaction without items), reading them in batches
+func (s *TransactionService) ExportTransactions(ctx context.Context, filter TransactionFilter, w export.Writer) error {
+	if err := validateTransactionFilter(filter); err != nil {
+		return err
+	}
+	if err := w.WriteRow(transactionExportHeader...); err != nil {
+		return err
+	}
+
+	err := s.transactionRepo.StreamTransactions(ctx, filter, exportBatchSize, func(batch []models.Transaction) error {
+		for _, transaction := range batch {
+			if err := writeTransactionRows(w, transaction); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to export transactions: %w", err)
+	}
+	return w.Close()
+}
+
+// writeTransactionRows writes the rows of one transaction
+func writeTransactionRows(w export.Writer, transaction models.Transaction) error {
+	admin := ""
+	if transaction.Admin != nil {
+		admin = transaction.Admin.Username
+	}
+	row := []any{
+		transaction.TransactionCode, transaction.CreatedAt, transaction.CustomerName, transaction.CustomerPhone,
+		transaction.CustomerAddress, string(transaction.Status), transaction.IsPaid, transaction.TotalPrice,
+		transaction.PickupDate, transaction.CompletedAt, admin,
+	}
+
+	if len(transaction.Items) == 0 {
+		return w.WriteRow(row...)
+	}
+	for _, item := range transaction.Items {
+		if err := w.WriteRow(append(row, item.ServiceType, item.ItemName, item.Quantity, item.UnitPrice, item.Subtotal)...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
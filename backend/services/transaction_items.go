@@ -0,0 +1,166 @@
+package services
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// AddTransactionItem adds an item to a transaction at its catalog price and recomputes the total
+func (s *TransactionService) AddTransactionItem(ctx context.Context, transactionID uint, serviceType, itemName string, quantity int) (*models.Transaction, error) {
+	if quantity <= 0 {
+		return nil, utils.NewValidationError("quantity", "must be greater than 0")
+	}
+	return s.changeItems(ctx, transactionID, func(ctx context.Context, transaction *models.Transaction) (AuditEntry, error) {
+		unitPrice, err := s.catalogPrice(ctx, serviceType, itemName)
+		if err != nil {
+			return AuditEntry{}, err
+		}
+		item := &models.TransactionItem{
+			TransactionID: transaction.ID,
+			ServiceType:   serviceType,
+			ItemName:      itemName,
+			Quantity:      quantity,
+			UnitPrice:     unitPrice,
+			Subtotal:      float64(quantity) * unitPrice,
+		}
+		if err := s.transactionRepo.CreateTransactionItem(ctx, item); err != nil {
+			return AuditEntry{}, fmt.Errorf("failed to add item: %w", err)
+		}
+		return itemAudit(models.AuditCreate, transaction, nil, item), nil
+	})
+}
+
+// UpdateTransactionItem changes the quantity of an item, repricing it from the catalog, and
+// recomputes the total
+func (s *TransactionService) UpdateTransactionItem(ctx context.Context, transactionID, itemID uint, quantity int) (*models.Transaction, error) {
+	if quantity <= 0 {
+		return nil, utils.NewValidationError("quantity", "must be greater than 0")
+	}
+	return s.changeItems(ctx, transactionID, func(ctx context.Context, transaction *models.Transaction) (AuditEntry, error) {
+		existing, err := s.transactionItem(ctx, transactionID, itemID)
+		if err != nil {
+			return AuditEntry{}, err
+		}
+		unitPrice, err := s.catalogPrice(ctx, existing.ServiceType, existing.ItemName)
+		if err != nil {
+			return AuditEntry{}, err
+		}
+
+		item := *existing
+		item.Quantity = quantity
+		item.UnitPrice = unitPrice
+		item.Subtotal = float64(quantity) * unitPrice
+		if err := s.transactionRepo.UpdateTransactionItem(ctx, &item); err != nil {
+			return AuditEntry{}, fmt.Errorf("failed to update item: %w", err)
+		}
+		return itemAudit(models.AuditUpdate, transaction, existing, &item), nil
+	})
+}
+
+// RemoveTransactionItem removes an item from a transaction and recomputes the total. The last
+// item cannot be removed; delete the transaction instead.
+func (s *TransactionService) RemoveTransactionItem(ctx context.Context, transactionID, itemID uint) (*models.Transaction, error) {
+	return s.changeItems(ctx, transactionID, func(ctx context.Context, transaction *models.Transaction) (AuditEntry, error) {
+		existing, err := s.transactionItem(ctx, transactionID, itemID)
+		if err != nil {
+			return AuditEntry{}, err
+		}
+		if len(transaction.Items) <= 1 {
+			return AuditEntry{}, utils.NewError(utils.ErrConflict, "a transaction needs at least one item, delete the transaction instead")
+		}
+		if err := s.transactionRepo.DeleteTransactionItem(ctx, itemID); err != nil {
+			return AuditEntry{}, fmt.Errorf("failed to remove item: %w", err)
+		}
+		return itemAudit(models.AuditDelete, transaction, existing, nil), nil
+	})
+}
+
+// changeItems applies a change to the items of a transaction, recomputes its total and records
+// both in the audit log, in one database transaction. Paid and completed transactions are final;
+// the transaction stays locked throughout so it cannot be paid or completed meanwhile.
+func (s *TransactionService) changeItems(ctx context.Context, transactionID uint, change func(context.Context, *models.Transaction) (AuditEntry, error)) (*models.Transaction, error) {
+	var updated *models.Transaction
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.transactionRepo.GetTransactionByIDForUpdate(ctx, transactionID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve transaction: %w", err)
+		}
+		if existing == nil {
+			return utils.NewError(utils.ErrNotFound, "transaction not found")
+		}
+		if existing.IsPaid {
+			return utils.NewError(utils.ErrConflict, "the transaction is paid, mark it unpaid before changing its items")
+		}
+		if existing.Status == models.StatusCompleted {
+			return utils.NewError(utils.ErrConflict, "the items of a completed transaction cannot be changed")
+		}
+
+		entry, err := change(ctx, existing)
+		if err != nil {
+			return err
+		}
+		if err := s.transactionRepo.RecalculateTotalPrice(ctx, transactionID); err != nil {
+			return fmt.Errorf("failed to recompute total price: %w", err)
+		}
+		if updated, err = s.transactionRepo.GetTransactionByID(ctx, transactionID); err != nil {
+			return fmt.Errorf("failed to retrieve transaction: %w", err)
+		}
+
+		total := AuditEntry{
+			Action: models.AuditUpdate, EntityType: models.AuditEntityTransaction, EntityID: transactionID,
+			Label: existing.TransactionCode, Changes: models.AuditChanges{},
+		}
+		if updated.TotalPrice != existing.TotalPrice {
+			total.Changes["total_price"] = models.FieldChange{From: existing.TotalPrice, To: updated.TotalPrice}
+		}
+		if err := s.audit.Record(ctx, entry, total); err != nil {
+			return err
+		}
+		return s.publish(ctx, events.TransactionUpdated{Transaction: *updated})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return updated, nil
+}
+
+// transactionItem retrieves an item of a transaction, failing when there is none
+func (s *TransactionService) transactionItem(ctx context.Context, transactionID, itemID uint) (*models.TransactionItem, error) {
+	item, err := s.transactionRepo.GetTransactionItem(ctx, transactionID, itemID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve item: %w", err)
+	}
+	if item == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "item not found")
+	}
+	return item, nil
+}
+
+// catalogPrice returns the active catalog price of an item
+func (s *TransactionService) catalogPrice(ctx context.Context, serviceType, itemName string) (float64, error) {
+	price, err := s.priceRepo.GetServicePriceByTypeAndItem(ctx, serviceType, itemName)
+	if err != nil {
+		return 0, fmt.Errorf("failed to retrieve service price: %w", err)
+	}
+	if price == nil {
+		return 0, utils.NewValidationError("item_name", fmt.Sprintf("no active price for %s - %s", serviceType, itemName))
+	}
+	return price.Price, nil
+}
+
+// itemAudit describes a change of a transaction item for the audit log, labelled with the
+// transaction code; before or after is nil when the item was added or removed
+func itemAudit(action models.AuditAction, transaction *models.Transaction, before, after *models.TransactionItem) AuditEntry {
+	current := after
+	if current == nil {
+		current = before
+	}
+	return AuditEntry{
+		Action: action, EntityType: models.AuditEntityItem, EntityID: current.ID,
+		Label: transaction.TransactionCode, Before: before, After: after,
+	}
+}
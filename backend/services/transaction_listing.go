@@ -0,0 +1,169 @@
+package services
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// TransactionFilter narrows, orders and pages a transaction listing
+type TransactionFilter = repositories.TransactionFilter
+
+// TransactionPage is one page of a transaction listing
+type TransactionPage struct {
+	Transactions []models.Transaction
+	Total        *int64 // set for offset pagination only, counting is skipped for cursors
+	NextCursor   string // set for keyset pagination when more rows follow
+}
+
+// transactionCursor is the decoded form of an opaque listing cursor
+type transactionCursor struct {
+	SortField string          `json:"s"`
+	SortDesc  bool            `json:"d"`
+	Value     json.RawMessage `json:"v"`
+	ID        uint            `json:"id"`
+}
+
+// ListTransactions returns one page of the transactions matching the filter.
+// With keyset set, paging follows cursor (empty for the first page) instead of filter.Offset,
+// and the page carries the cursor of the next one rather than a total count.
+func (s *TransactionService) ListTransactions(ctx context.Context, filter TransactionFilter, keyset bool, cursor string) (*TransactionPage, error) {
+	if err := validateTransactionFilter(filter); err != nil {
+		return nil, err
+	}
+
+	if !keyset {
+		total, err := s.transactionRepo.CountTransactions(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to count transactions: %w", err)
+		}
+		transactions, err := s.transactionRepo.ListTransactions(ctx, filter)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+		}
+		return &TransactionPage{Transactions: transactions, Total: &total}, nil
+	}
+
+	if !filter.SupportsKeyset() {
+		return nil, utils.NewValidationError("sort", "cursor pagination is not available when sorting by a date that may be empty, use page instead")
+	}
+	if cursor != "" {
+		after, err := decodeTransactionCursor(cursor, filter)
+		if err != nil {
+			return nil, err
+		}
+		filter.After = after
+	}
+
+	// Fetch one extra row to learn whether another page follows
+	pageSize := filter.Limit
+	filter.Limit = pageSize + 1
+	transactions, err := s.transactionRepo.ListTransactions(ctx, filter)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+
+	page := &TransactionPage{Transactions: transactions}
+	if len(transactions) > pageSize {
+		page.Transactions = transactions[:pageSize]
+		page.NextCursor, err = encodeTransactionCursor(filter, filter.KeyOf(page.Transactions[pageSize-1]))
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode cursor: %w", err)
+		}
+	}
+	return page, nil
+}
+
+// validateTransactionFilter reports every invalid filter field at once
+func validateTransactionFilter(filter TransactionFilter) error {
+	var fields []utils.FieldError
+	invalid := func(field, message string) {
+		fields = append(fields, utils.FieldError{Field: field, Message: message})
+	}
+
+	for _, status := range filter.Statuses {
+		if _, known := validTransitions[status]; !known {
+			invalid("status", fmt.Sprintf("unknown status %q", status))
+		}
+	}
+	if filter.DateField != "" && !slices.Contains(repositories.TransactionDateFields, filter.DateField) {
+		invalid("date_field", "must be one of "+strings.Join(repositories.TransactionDateFields, ", "))
+	}
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		invalid("to", "must be after from")
+	}
+	if filter.MinPrice != nil && filter.MaxPrice != nil && *filter.MinPrice > *filter.MaxPrice {
+		invalid("max_price", "must not be below min_price")
+	}
+	if filter.SortField != "" && !slices.Contains(repositories.TransactionSortFields, filter.SortField) {
+		invalid("sort", "must be one of "+strings.Join(repositories.TransactionSortFields, ", "))
+	}
+
+	if len(fields) > 0 {
+		return &utils.ValidationError{Fields: fields}
+	}
+	return nil
+}
+
+// encodeTransactionCursor packs a keyset position and the sort it belongs to into an opaque string
+func encodeTransactionCursor(filter TransactionFilter, key repositories.TransactionKey) (string, error) {
+	value, err := json.Marshal(key.Value)
+	if err != nil {
+		return "", err
+	}
+	payload, err := json.Marshal(transactionCursor{
+		SortField: filter.SortField,
+		SortDesc:  filter.SortDesc,
+		Value:     value,
+		ID:        key.ID,
+	})
+	if err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(payload), nil
+}
+
+// decodeTransactionCursor unpacks a cursor, rejecting cursors issued for a different sort
+func decodeTransactionCursor(cursor string, filter TransactionFilter) (*repositories.TransactionKey, error) {
+	invalid := utils.NewValidationError("cursor", "invalid cursor")
+
+	payload, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, invalid
+	}
+	var decoded transactionCursor
+	if err := json.Unmarshal(payload, &decoded); err != nil {
+		return nil, invalid
+	}
+	if decoded.SortField != filter.SortField || decoded.SortDesc != filter.SortDesc {
+		return nil, utils.NewValidationError("cursor", "cursor was issued for a different sort order")
+	}
+
+	key := &repositories.TransactionKey{ID: decoded.ID}
+	switch filter.SortField {
+	case repositories.TransactionFieldTotalPrice:
+		var value float64
+		err = json.Unmarshal(decoded.Value, &value)
+		key.Value = value
+	case repositories.TransactionFieldCustomerName:
+		var value string
+		err = json.Unmarshal(decoded.Value, &value)
+		key.Value = value
+	default:
+		var value time.Time
+		err = json.Unmarshal(decoded.Value, &value)
+		key.Value = value
+	}
+	if err != nil {
+		return nil, invalid
+	}
+	return key, nil
+}
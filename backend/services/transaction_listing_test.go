@@ -0,0 +1,81 @@
+package services
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+func TestTransactionCursorRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 3, 14, 9, 26, 53, 589_000_000, time.FixedZone("WIB", 7*60*60))
+	tests := []struct {
+		name   string
+		filter TransactionFilter
+		value  any
+	}{
+		{"default sort", TransactionFilter{}, createdAt},
+		{"created_at descending", TransactionFilter{SortField: repositories.TransactionFieldCreatedAt, SortDesc: true}, createdAt},
+		{"total_price", TransactionFilter{SortField: repositories.TransactionFieldTotalPrice}, 37500.5},
+		{"customer_name descending", TransactionFilter{SortField: repositories.TransactionFieldCustomerName, SortDesc: true}, "Ridwan \"Ramdhani\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cursor, err := encodeTransactionCursor(tt.filter, repositories.TransactionKey{Value: tt.value, ID: 42})
+			if err != nil {
+				t.Fatal(err)
+			}
+			key, err := decodeTransactionCursor(cursor, tt.filter)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if key.ID != 42 {
+				t.Errorf("ID = %d, want 42", key.ID)
+			}
+			if want, ok := tt.value.(time.Time); ok {
+				if got, ok := key.Value.(time.Time); !ok || !got.Equal(want) {
+					t.Errorf("Value = %v, want %v", key.Value, want)
+				}
+			} else if key.Value != tt.value {
+				t.Errorf("Value = %#v, want %#v", key.Value, tt.value)
+			}
+		})
+	}
+}
+
+func TestTransactionCursorRejectsOtherSort(t *testing.T) {
+	issued := TransactionFilter{SortField: repositories.TransactionFieldTotalPrice}
+	cursor, err := encodeTransactionCursor(issued, repositories.TransactionKey{Value: 10000.0, ID: 7})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, filter := range []TransactionFilter{
+		{SortField: repositories.TransactionFieldTotalPrice, SortDesc: true},
+		{SortField: repositories.TransactionFieldCustomerName},
+		{},
+	} {
+		_, err := decodeTransactionCursor(cursor, filter)
+		var validation *utils.ValidationError
+		if !errors.As(err, &validation) || validation.Fields[0].Message != "cursor was issued for a different sort order" {
+			t.Errorf("decoding for %+v: err = %v, want a different sort order error", filter, err)
+		}
+	}
+}
+
+func TestTransactionCursorRejectsGarbage(t *testing.T) {
+	filter := TransactionFilter{SortField: repositories.TransactionFieldTotalPrice}
+	for _, cursor := range []string{
+		"not base64!",
+		"bm90IGpzb24", // not JSON
+		"eyJzIjoidG90YWxfcHJpY2UiLCJkIjpmYWxzZSwidiI6ImEiLCJpZCI6MX0", // a price that is a string
+	} {
+		_, err := decodeTransactionCursor(cursor, filter)
+		var validation *utils.ValidationError
+		if !errors.As(err, &validation) || validation.Fields[0].Message != "invalid cursor" {
+			t.Errorf("decoding %q: err = %v, want an invalid cursor error", cursor, err)
+		}
+	}
+}
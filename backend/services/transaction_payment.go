@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// paymentClockSkew is how far in the future a payment time may lie, to allow for client clocks
+const paymentClockSkew = time.Minute
+
+// RecordPayment records the full payment of a transaction by an admin and marks it as paid.
+// paidAt defaults to now and may lie in the past, but not inside one of the admin's closed
+// shifts: their closing figures are final. A payment inside the admin's open shift joins it.
+func (s *TransactionService) RecordPayment(ctx context.Context, transactionID, adminID uint, method models.PaymentMethod, paidAt *time.Time) (*models.Payment, error) {
+	switch method {
+	case models.PaymentCash, models.PaymentTransfer, models.PaymentQRIS:
+	default:
+		return nil, utils.NewValidationError("method", "must be one of cash, transfer, qris")
+	}
+	now := time.Now()
+	at := now
+	if paidAt != nil {
+		if paidAt.After(now.Add(paymentClockSkew)) {
+			return nil, utils.NewValidationError("paid_at", "must not be in the future")
+		}
+		at = *paidAt
+	}
+
+	var payment *models.Payment
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) (err error) {
+		payment, err = s.recordPayment(ctx, transactionID, adminID, method, at)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	metrics.RevenueRecordedTotal.Add(payment.Amount)
+	return payment, nil
+}
+
+// recordPayment records the payment of the transaction in the admin's shift at the given time
+// and publishes it, in the database transaction in ctx. The amount is the total of the locked
+// transaction row, so an item change cannot slip in between.
+func (s *TransactionService) recordPayment(ctx context.Context, transactionID, adminID uint, method models.PaymentMethod, at time.Time) (*models.Payment, error) {
+	transaction, err := s.transactionRepo.GetTransactionByIDForUpdate(ctx, transactionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transaction: %w", err)
+	}
+	if transaction == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "transaction not found")
+	}
+	if transaction.IsPaid {
+		return nil, utils.NewError(utils.ErrConflict, "transaction is already paid")
+	}
+
+	shift, err := s.shiftRepo.GetShiftAt(ctx, adminID, at)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve shift: %w", err)
+	}
+	if shift != nil && shift.IsClosed() {
+		return nil, utils.NewError(utils.ErrConflict, "payments cannot be back-dated into shift #%d, which closed at %s",
+			shift.ID, shift.ClosedAt.Format(time.RFC3339))
+	}
+
+	payment := &models.Payment{
+		TransactionID: transaction.ID,
+		AdminID:       adminID,
+		Amount:        transaction.TotalPrice,
+		Method:        method,
+		PaidAt:        at,
+	}
+	if shift != nil {
+		payment.ShiftID = &shift.ID
+	}
+
+	err = s.paymentRepo.RecordPayment(ctx, payment)
+	switch {
+	case errors.Is(err, repositories.ErrAlreadyPaid):
+		return nil, utils.NewError(utils.ErrConflict, "transaction is already paid")
+	case errors.Is(err, repositories.ErrShiftClosed):
+		return nil, utils.NewError(utils.ErrConflict, "shift #%d was closed while the payment was being recorded", shift.ID)
+	case err != nil:
+		return nil, fmt.Errorf("failed to record payment: %w", err)
+	}
+	err = s.audit.Record(ctx, AuditEntry{
+		Action: models.AuditCreate, EntityType: models.AuditEntityPayment, EntityID: payment.ID,
+		Label: transaction.TransactionCode, After: payment,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if err := s.publish(ctx, events.PaymentRecorded{TransactionCode: transaction.TransactionCode, Payment: *payment}); err != nil {
+		return nil, err
+	}
+	return payment, nil
+}
+
+// voidPayments removes the payments of a transaction being marked unpaid
+func (s *TransactionService) voidPayments(ctx context.Context, transaction *models.Transaction) error {
+	err := s.paymentRepo.VoidPayments(ctx, transaction.ID)
+	if errors.Is(err, repositories.ErrShiftClosed) {
+		return utils.NewError(utils.ErrConflict, "the payment belongs to a closed shift and cannot be undone")
+	}
+	if err != nil {
+		return fmt.Errorf("failed to void payments: %w", err)
+	}
+
+	entries := make([]AuditEntry, len(transaction.Payments))
+	for i := range transaction.Payments {
+		payment := &transaction.Payments[i]
+		entries[i] = AuditEntry{
+			Action: models.AuditVoid, EntityType: models.AuditEntityPayment, EntityID: payment.ID,
+			Label: transaction.TransactionCode, Before: payment,
+		}
+	}
+	return s.audit.Record(ctx, entries...)
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"gorm.io/gorm"
+)
+
+// paymentDB answers the queries of recording and voiding payments. shifts are returned one
+// after the other, so a shift can be seen open first and closed when it is locked.
+type paymentDB struct {
+	dryRunDB
+	transaction models.Transaction
+	shifts      []*models.Shift
+}
+
+func (f *paymentDB) open(t *testing.T) *gorm.DB {
+	f.query = func(tx *gorm.DB) {
+		switch dest := tx.Statement.Dest.(type) {
+		case *models.Transaction:
+			*dest = f.transaction
+		case *models.Shift:
+			if len(f.shifts) == 0 || f.shifts[0] == nil {
+				tx.AddError(gorm.ErrRecordNotFound)
+			} else {
+				*dest = *f.shifts[0]
+			}
+			if len(f.shifts) > 1 {
+				f.shifts = f.shifts[1:]
+			}
+		case *[]models.Shift:
+			for _, shift := range f.shifts {
+				*dest = append(*dest, *shift)
+			}
+		}
+	}
+	return f.dryRunDB.open(t)
+}
+
+func newPaymentService(t *testing.T, f *paymentDB) *TransactionService {
+	db := f.open(t)
+	return NewTransactionService(repositories.NewTransactionRepository(db), nil, nil, repositories.NewPaymentRepository(db),
+		repositories.NewShiftRepository(db), nil, nil, repositories.NewTransactor(db), nil,
+		NewAuditService(repositories.NewAuditRepository(db)))
+}
+
+func TestRecordPaymentShifts(t *testing.T) {
+	now := time.Now()
+	hourAgo := now.Add(-time.Hour)
+	open := &models.Shift{ID: 4, AdminID: 1, OpenedAt: now.Add(-2 * time.Hour)}
+	closed := &models.Shift{ID: 3, AdminID: 1, OpenedAt: now.Add(-5 * time.Hour), ClosedAt: &hourAgo}
+	future := now.Add(2 * paymentClockSkew)
+	past := now.Add(-90 * time.Minute)
+
+	tests := []struct {
+		name      string
+		paidAt    *time.Time
+		shifts    []*models.Shift // the shift at the payment time, then the shift as locked
+		wantErr   error
+		wantMsg   string
+		wantShift *uint
+	}{
+		{name: "now, without a shift", shifts: nil},
+		{name: "now, in the open shift", shifts: []*models.Shift{open}, wantShift: &open.ID},
+		{name: "back-dated into the open shift", paidAt: &past, shifts: []*models.Shift{open}, wantShift: &open.ID},
+		{name: "back-dated into a closed shift", paidAt: &past, shifts: []*models.Shift{closed}, wantErr: utils.ErrConflict, wantMsg: "back-dated"},
+		{name: "shift closed while recording", shifts: []*models.Shift{open, closed}, wantErr: utils.ErrConflict, wantMsg: "closed while"},
+		{name: "in the future", paidAt: &future, wantErr: utils.ErrValidation},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &paymentDB{transaction: models.Transaction{ID: 7, TransactionCode: "CHRN-1", TotalPrice: 42000}, shifts: tt.shifts}
+			payment, err := newPaymentService(t, f).RecordPayment(context.Background(), 7, 1, models.PaymentCash, tt.paidAt)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !strings.Contains(err.Error(), tt.wantMsg) {
+					t.Fatalf("got error %v, want %v (%s)", err, tt.wantErr, tt.wantMsg)
+				}
+				if f.wrote("INSERT INTO `payments`") {
+					t.Errorf("payment stored despite the error: %q", f.writes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			switch {
+			case tt.wantShift == nil && payment.ShiftID != nil:
+				t.Errorf("payment joined shift #%d, want none", *payment.ShiftID)
+			case tt.wantShift != nil && (payment.ShiftID == nil || *payment.ShiftID != *tt.wantShift):
+				t.Errorf("payment joined shift %v, want #%d", payment.ShiftID, *tt.wantShift)
+			}
+			if payment.Amount != 42000 {
+				t.Errorf("got amount %v, want the total of the locked transaction, 42000", payment.Amount)
+			}
+			if tt.paidAt != nil && !payment.PaidAt.Equal(*tt.paidAt) {
+				t.Errorf("got paid at %s, want %s", payment.PaidAt, *tt.paidAt)
+			}
+		})
+	}
+}
+
+// TestRecordPaymentAlreadyPaid has the transaction paid concurrently: the conditional update
+// of the paid flag affects no row and nothing is stored
+func TestRecordPaymentAlreadyPaid(t *testing.T) {
+	f := &paymentDB{dryRunDB: dryRunDB{refuse: []string{"is_paid = ?"}}, transaction: models.Transaction{ID: 7, TotalPrice: 42000}}
+	if _, err := newPaymentService(t, f).RecordPayment(context.Background(), 7, 1, models.PaymentCash, nil); !errors.Is(err, utils.ErrConflict) {
+		t.Fatalf("got error %v, want conflict", err)
+	}
+	if f.wrote("INSERT INTO `payments`") {
+		t.Errorf("payment stored for a paid transaction: %q", f.writes)
+	}
+}
+
+func TestVoidPaymentsClosedShift(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+	tests := []struct {
+		name    string
+		shifts  []*models.Shift
+		wantErr bool
+	}{
+		{"open shift", []*models.Shift{{ID: 4}}, false},
+		{"closed shift", []*models.Shift{{ID: 4}, {ID: 3, ClosedAt: &hourAgo}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &paymentDB{shifts: tt.shifts}
+			transaction := &models.Transaction{ID: 7, Payments: []models.Payment{{ID: 9, TransactionID: 7}}}
+			err := newPaymentService(t, f).voidPayments(context.Background(), transaction)
+			if tt.wantErr {
+				if !errors.Is(err, utils.ErrConflict) {
+					t.Fatalf("got error %v, want conflict", err)
+				}
+				if f.wrote("DELETE FROM `payments`") {
+					t.Errorf("payments of a closed shift deleted: %q", f.writes)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !f.wrote("DELETE FROM `payments`") {
+				t.Errorf("payments not deleted: %q", f.writes)
+			}
+		})
+	}
+}
+
+// TestCloseShiftAlreadyClosed closes a shift that another request closed first: the locked
+// row is seen closed and nothing is written
+func TestCloseShiftAlreadyClosed(t *testing.T) {
+	hourAgo := time.Now().Add(-time.Hour)
+	f := &paymentDB{shifts: []*models.Shift{{ID: 4, AdminID: 1}, {ID: 4, AdminID: 1, ClosedAt: &hourAgo}}}
+	db := f.open(t)
+	service := NewShiftService(repositories.NewShiftRepository(db), repositories.NewTransactor(db),
+		NewAuditService(repositories.NewAuditRepository(db)))
+
+	if _, err := service.CloseCurrentShift(context.Background(), 1, 100000, ""); !errors.Is(err, utils.ErrConflict) {
+		t.Fatalf("got error %v, want conflict", err)
+	}
+	for _, sql := range f.writes {
+		if strings.HasPrefix(sql, "UPDATE `shifts`") || strings.HasPrefix(sql, "INSERT INTO `shifts`") {
+			t.Errorf("closed shift written again: %s", sql)
+		}
+	}
+}
@@ -0,0 +1,252 @@
+package services
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"html"
+	"slices"
+	"strconv"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/search"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+const (
+	// maxSearchQueryTerms bounds the words of a search query
+	maxSearchQueryTerms = 8
+	// maxSearchCandidates bounds the indexed terms considered per query word
+	maxSearchCandidates = 2000
+	// reindexBatchSize is the number of transactions indexed per batch by ReindexTransactions
+	reindexBatchSize = 200
+)
+
+// searchFieldWeights ranks a match by the field it was found in
+var searchFieldWeights = map[string]float64{
+	models.SearchFieldTransactionCode: 3,
+	models.SearchFieldCustomerPhone:   3,
+	models.SearchFieldCustomerName:    2,
+	models.SearchFieldItemName:        1,
+	models.SearchFieldCustomerAddress: 1,
+	models.SearchFieldNotes:           0.5,
+}
+
+// SearchHit is a transaction matching a search query
+type SearchHit struct {
+	Transaction models.Transaction `json:"transaction"`
+	Score       float64            `json:"score"`
+	Highlights  []SearchHighlight  `json:"highlights"`
+}
+
+// SearchHighlight is a matched field with the matching words wrapped in <mark> tags.
+// The text is HTML-escaped.
+type SearchHighlight struct {
+	Field string `json:"field"`
+	Text  string `json:"text"`
+}
+
+// SearchTransactions returns up to limit transactions matching every word of query,
+// best match first. Words match exactly, as a prefix, or with a few typos.
+func (s *TransactionService) SearchTransactions(ctx context.Context, query string, limit int) ([]SearchHit, error) {
+	queryTerms := searchQueryTerms(query)
+	if len(queryTerms) == 0 {
+		return nil, utils.NewValidationError("q", "must contain at least one letter or digit")
+	}
+	if len(queryTerms) > maxSearchQueryTerms {
+		return nil, utils.NewValidationError("q", fmt.Sprintf("must not contain more than %d words", maxSearchQueryTerms))
+	}
+
+	// best[transactionID][queryTerm] is the highest weighted match of the query term
+	best := make(map[uint]map[string]float64)
+	for _, queryTerm := range queryTerms {
+		candidates, err := s.searchRepo.FindCandidates(ctx, queryTerm, search.FuzzyPrefixLength, search.MaxEdits(queryTerm), maxSearchCandidates)
+		if err != nil {
+			return nil, fmt.Errorf("failed to search transactions: %w", err)
+		}
+		for _, candidate := range candidates {
+			score := search.MatchTerm(queryTerm, candidate.Term).Score() * searchFieldWeights[candidate.Field]
+			if score <= 0 {
+				continue
+			}
+			if best[candidate.TransactionID] == nil {
+				best[candidate.TransactionID] = make(map[string]float64)
+			}
+			best[candidate.TransactionID][queryTerm] = max(best[candidate.TransactionID][queryTerm], score)
+		}
+	}
+
+	// Keep transactions matching every query term
+	type ranked struct {
+		id    uint
+		score float64
+	}
+	var matches []ranked
+	for id, scores := range best {
+		if len(scores) < len(queryTerms) {
+			continue
+		}
+		total := 0.0
+		for _, score := range scores {
+			total += score
+		}
+		matches = append(matches, ranked{id: id, score: total})
+	}
+	slices.SortFunc(matches, func(a, b ranked) int {
+		return cmp.Or(cmp.Compare(b.score, a.score), cmp.Compare(b.id, a.id))
+	})
+	if len(matches) > limit {
+		matches = matches[:limit]
+	}
+
+	ids := make([]uint, len(matches))
+	for i, match := range matches {
+		ids[i] = match.id
+	}
+	transactions, err := s.transactionRepo.GetTransactionsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve transactions: %w", err)
+	}
+	byID := make(map[uint]models.Transaction, len(transactions))
+	for _, transaction := range transactions {
+		byID[transaction.ID] = transaction
+	}
+
+	hits := make([]SearchHit, 0, len(matches))
+	for _, match := range matches {
+		transaction, ok := byID[match.id]
+		if !ok {
+			continue // deleted since the candidates were read
+		}
+		hits = append(hits, SearchHit{
+			Transaction: transaction,
+			Score:       match.score,
+			Highlights:  highlightTransaction(transaction, queryTerms),
+		})
+	}
+	return hits, nil
+}
+
+// IndexTransaction replaces the search terms of a transaction with those of its current fields
+func (s *TransactionService) IndexTransaction(ctx context.Context, transaction *models.Transaction) error {
+	var terms []models.TransactionSearchTerm
+	for _, field := range searchableFields(transaction) {
+		fieldTerms := search.Terms(field.text)
+		if field.name == models.SearchFieldCustomerPhone {
+			fieldTerms = search.PhoneTerms(field.text)
+		}
+		for _, term := range fieldTerms {
+			terms = append(terms, models.TransactionSearchTerm{
+				TransactionID: transaction.ID,
+				Field:         field.name,
+				Term:          term,
+			})
+		}
+	}
+	if err := s.searchRepo.ReplaceTerms(ctx, transaction.ID, terms); err != nil {
+		return fmt.Errorf("failed to index transaction: %w", err)
+	}
+	return nil
+}
+
+// ReindexTransactions rebuilds the search terms of every transaction and returns how many were indexed
+func (s *TransactionService) ReindexTransactions(ctx context.Context) (int, error) {
+	indexed := 0
+	err := s.transactionRepo.FindTransactionsInBatches(ctx, reindexBatchSize, func(batch []models.Transaction) error {
+		for i := range batch {
+			if err := s.IndexTransaction(ctx, &batch[i]); err != nil {
+				return err
+			}
+		}
+		indexed += len(batch)
+		return nil
+	})
+	if err != nil {
+		return indexed, fmt.Errorf("failed to reindex transactions: %w", err)
+	}
+	return indexed, nil
+}
+
+// reindexTransaction indexes the stored state of a transaction after a write. Indexing what is
+// stored rather than what an event carried keeps the index right when events arrive out of order.
+func (s *TransactionService) reindexTransaction(ctx context.Context, id uint) error {
+	transaction, err := s.transactionRepo.GetTransactionByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve transaction: %w", err)
+	}
+	if transaction == nil {
+		return nil // deleted meanwhile
+	}
+	return s.IndexTransaction(ctx, transaction)
+}
+
+// searchableField is the text of one searchable field
+type searchableField struct {
+	name string // one of the models.SearchField constants
+	path string // location of the text in the transaction JSON
+	text string
+}
+
+// searchableFields lists the searchable texts of a transaction
+func searchableFields(transaction *models.Transaction) []searchableField {
+	fields := []searchableField{
+		{models.SearchFieldTransactionCode, "transaction_code", transaction.TransactionCode},
+		{models.SearchFieldCustomerName, "customer_name", transaction.CustomerName},
+		{models.SearchFieldCustomerPhone, "customer_phone", transaction.CustomerPhone},
+		{models.SearchFieldCustomerAddress, "customer_address", transaction.CustomerAddress},
+		{models.SearchFieldNotes, "notes", transaction.Notes},
+	}
+	for i, item := range transaction.Items {
+		fields = append(fields, searchableField{models.SearchFieldItemName, "items[" + strconv.Itoa(i) + "].item_name", item.ItemName})
+	}
+	return fields
+}
+
+// searchQueryTerms splits a query into distinct normalized terms.
+// A query that reads as a phone number is kept whole, in its local digit form.
+func searchQueryTerms(query string) []string {
+	if digits := search.PhoneDigits(query); len(digits) >= 4 {
+		terms := search.PhoneTerms(digits)
+		return terms[len(terms)-1:]
+	}
+	return search.Terms(query)
+}
+
+// highlightTransaction marks the words of each field matching a query term
+func highlightTransaction(transaction models.Transaction, queryTerms []string) []SearchHighlight {
+	var highlights []SearchHighlight
+	for _, field := range searchableFields(&transaction) {
+		var text string
+		if field.name == models.SearchFieldCustomerPhone {
+			text = highlightPhone(field.text, queryTerms)
+		} else {
+			text = search.Highlight(field.text, func(term string) bool {
+				return matchesAnyTerm(queryTerms, term)
+			})
+		}
+		if text != "" {
+			highlights = append(highlights, SearchHighlight{Field: field.path, Text: text})
+		}
+	}
+	return highlights
+}
+
+// highlightPhone marks a whole phone number when one of its indexed forms matches
+func highlightPhone(phone string, queryTerms []string) string {
+	for _, term := range search.PhoneTerms(phone) {
+		if matchesAnyTerm(queryTerms, term) {
+			return "<mark>" + html.EscapeString(phone) + "</mark>"
+		}
+	}
+	return ""
+}
+
+// matchesAnyTerm reports whether an indexed term matches one of the query terms
+func matchesAnyTerm(queryTerms []string, term string) bool {
+	for _, queryTerm := range queryTerms {
+		if search.MatchTerm(queryTerm, term).Kind != search.NoMatch {
+			return true
+		}
+	}
+	return false
+}
@@ -1,9 +1,16 @@
 package services
 
 import (
+	"context"
+	"crypto/subtle"
 	"fmt"
+	"log/slog"
+	"time"
 
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/notification"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
 	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
 )
@@ -12,143 +19,328 @@ import (
 type TransactionService struct {
 	transactionRepo *repositories.TransactionRepository
 	historyRepo     *repositories.TransactionHistoryRepository
+	searchRepo      *repositories.TransactionSearchRepository
+	paymentRepo     *repositories.PaymentRepository
+	shiftRepo       *repositories.ShiftRepository
+	priceRepo       *repositories.ServicePriceRepository
+	customerRepo    *repositories.CustomerRepository
+	transactor      *repositories.Transactor
+	events          *EventService // nil only for commands that never change transactions
+	audit           *AuditService
 }
 
 // NewTransactionService creates a new transaction service
 func NewTransactionService(
 	transactionRepo *repositories.TransactionRepository,
 	historyRepo *repositories.TransactionHistoryRepository,
+	searchRepo *repositories.TransactionSearchRepository,
+	paymentRepo *repositories.PaymentRepository,
+	shiftRepo *repositories.ShiftRepository,
+	priceRepo *repositories.ServicePriceRepository,
+	customerRepo *repositories.CustomerRepository,
+	transactor *repositories.Transactor,
+	events *EventService,
+	audit *AuditService,
 ) *TransactionService {
 	return &TransactionService{
 		transactionRepo: transactionRepo,
 		historyRepo:     historyRepo,
+		searchRepo:      searchRepo,
+		paymentRepo:     paymentRepo,
+		shiftRepo:       shiftRepo,
+		priceRepo:       priceRepo,
+		customerRepo:    customerRepo,
+		transactor:      transactor,
+		events:          events,
+		audit:           audit,
 	}
 }
 
 // CreateTransaction creates a new transaction
-func (s *TransactionService) CreateTransaction(transaction *models.Transaction) error {
+func (s *TransactionService) CreateTransaction(ctx context.Context, transaction *models.Transaction) error {
 	// Generate unique transaction code
 	transaction.TransactionCode = utils.GenerateTransactionCode()
 
 	// Set initial status to Antrian
 	transaction.Status = models.StatusQueued
 
-	// Create transaction
-	err := s.transactionRepo.CreateTransaction(transaction)
-	if err != nil {
-		return fmt.Errorf("failed to create transaction: %w", err)
+	// Customers who saved an address in the portal get it on orders taken without one
+	if transaction.CustomerAddress == "" {
+		if phone := notification.InternationalPhone(transaction.CustomerPhone); phone != "" {
+			customer, err := s.customerRepo.GetCustomerByPhone(ctx, phone)
+			if err != nil {
+				return fmt.Errorf("failed to retrieve customer: %w", err)
+			}
+			if customer != nil {
+				transaction.CustomerAddress = customer.Address
+			}
+		}
 	}
 
-	// Record initial status in history
-	history := &models.TransactionHistory{
-		TransactionID:  transaction.ID,
-		PreviousStatus: "",
-		NewStatus:      models.StatusQueued,
-		ChangedBy:      "system",
-		Reason:         "Transaction created",
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.transactionRepo.CreateTransaction(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to create transaction: %w", err)
+		}
+		if err := s.audit.Record(ctx, transactionAudit(models.AuditCreate, nil, transaction)); err != nil {
+			return err
+		}
+		return s.publish(ctx, events.TransactionCreated{Transaction: *transaction})
+	})
+	if err != nil {
+		return err
 	}
-	_ = s.historyRepo.CreateHistory(history)
-
+	metrics.TransactionsCreatedTotal.Inc()
 	return nil
 }
 
 // GetTransaction retrieves a transaction by ID
-func (s *TransactionService) GetTransaction(id uint) (*models.Transaction, error) {
-	transaction, err := s.transactionRepo.GetTransactionByID(id)
+func (s *TransactionService) GetTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	transaction, err := s.transactionRepo.GetTransactionByID(ctx, id)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve transaction: %w", err)
 	}
 	if transaction == nil {
-		return nil, fmt.Errorf("transaction not found")
+		return nil, utils.NewError(utils.ErrNotFound, "transaction not found")
 	}
 	return transaction, nil
 }
 
 // GetTransactionByCode retrieves a transaction by code (for tracking)
-func (s *TransactionService) GetTransactionByCode(code string) (*models.Transaction, error) {
+func (s *TransactionService) GetTransactionByCode(ctx context.Context, code string) (*models.Transaction, error) {
 	if !utils.IsValidTransactionCode(code) {
-		return nil, fmt.Errorf("invalid transaction code format")
+		return nil, utils.NewValidationError("code", "invalid transaction code format")
 	}
 
-	transaction, err := s.transactionRepo.GetTransactionByCode(code)
+	transaction, err := s.transactionRepo.GetTransactionByCode(ctx, code)
 	if err != nil {
 		return nil, fmt.Errorf("failed to retrieve transaction: %w", err)
 	}
 	if transaction == nil {
-		return nil, fmt.Errorf("transaction not found")
+		return nil, utils.NewError(utils.ErrNotFound, "transaction not found")
 	}
 	return transaction, nil
 }
 
-// UpdateTransaction updates a transaction
-func (s *TransactionService) UpdateTransaction(transaction *models.Transaction) error {
-	err := s.transactionRepo.UpdateTransaction(transaction)
+// TrackTransaction retrieves a transaction for its public tracking page. verified reports that
+// phoneLast4 matched the last four digits of the customer's phone; only then may more than the
+// status be shown. An empty phoneLast4 skips the check.
+func (s *TransactionService) TrackTransaction(ctx context.Context, code, phoneLast4 string) (transaction *models.Transaction, verified bool, err error) {
+	if phoneLast4 != "" && len(utils.PhoneLastDigits(phoneLast4, 4)) != len(phoneLast4) {
+		return nil, false, utils.NewValidationError("phone_last4", "must be the last 4 digits of the phone number")
+	}
+
+	transaction, err = s.GetTransactionByCode(ctx, code)
+	if err != nil {
+		return nil, false, err
+	}
+	if phoneLast4 == "" {
+		return transaction, false, nil
+	}
+
+	expected := utils.PhoneLastDigits(transaction.CustomerPhone, 4)
+	if expected == "" || subtle.ConstantTimeCompare([]byte(expected), []byte(phoneLast4)) != 1 {
+		return nil, false, utils.NewValidationError("phone_last4", "does not match the phone number of this order")
+	}
+	return transaction, true, nil
+}
+
+// UpdateTransaction updates a transaction on behalf of an admin. Marking it as paid records
+// a cash payment by that admin; marking it unpaid voids its payments. The total price follows
+// the items and is never taken from transaction.
+func (s *TransactionService) UpdateTransaction(ctx context.Context, transaction *models.Transaction, adminID uint) error {
+	var payment *models.Payment
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		// Load the stored version to detect changes of the paid flag
+		existing, err := s.transactionRepo.GetTransactionByIDForUpdate(ctx, transaction.ID)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve transaction: %w", err)
+		}
+		if existing == nil {
+			return utils.NewError(utils.ErrNotFound, "transaction not found")
+		}
+
+		transaction.TotalPrice = existing.TotalPrice
+
+		// The paid flag only changes together with the payments behind it
+		markPaid := !existing.IsPaid && transaction.IsPaid
+		if existing.IsPaid && !transaction.IsPaid {
+			if err := s.voidPayments(ctx, existing); err != nil {
+				return err
+			}
+			transaction.Payments = nil
+		} else {
+			transaction.IsPaid = existing.IsPaid
+		}
+
+		if err := s.transactionRepo.UpdateTransaction(ctx, transaction); err != nil {
+			return fmt.Errorf("failed to update transaction: %w", err)
+		}
+		if markPaid {
+			if payment, err = s.recordPayment(ctx, transaction.ID, adminID, models.PaymentCash, time.Now()); err != nil {
+				return err
+			}
+			transaction.IsPaid = true
+			transaction.Payments = append(transaction.Payments, *payment)
+		}
+		if err := s.audit.Record(ctx, transactionAudit(models.AuditUpdate, existing, transaction)); err != nil {
+			return err
+		}
+		return s.publish(ctx, events.TransactionUpdated{Transaction: *transaction})
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update transaction: %w", err)
+		return err
+	}
+	if payment != nil {
+		metrics.RevenueRecordedTotal.Add(payment.Amount)
 	}
 	return nil
 }
 
 // UpdateTransactionStatus updates transaction status with workflow validation
-func (s *TransactionService) UpdateTransactionStatus(id uint, newStatus models.TransactionStatus, adminUsername string, reason string) error {
+func (s *TransactionService) UpdateTransactionStatus(ctx context.Context, id uint, newStatus models.TransactionStatus, adminUsername string, reason string) error {
+	if _, known := validTransitions[newStatus]; !known {
+		return utils.NewValidationError("new_status", fmt.Sprintf("unknown status %q", newStatus))
+	}
+
 	// Get current transaction
-	transaction, err := s.GetTransaction(id)
+	transaction, err := s.GetTransaction(ctx, id)
 	if err != nil {
 		return err
 	}
 
 	// Validate status transition
-	if !isValidStatusTransition(transaction.Status, newStatus) {
-		return fmt.Errorf("invalid status transition from %s to %s", transaction.Status, newStatus)
+	previousStatus := transaction.Status
+	if !isValidStatusTransition(previousStatus, newStatus) {
+		return utils.NewError(utils.ErrInvalidTransition, "invalid status transition from %s to %s", previousStatus, newStatus)
 	}
 
-	// Update status
-	err = s.transactionRepo.UpdateTransactionStatus(id, newStatus)
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		changedAt := time.Now()
+		updated, err := s.transactionRepo.UpdateTransactionStatus(ctx, id, previousStatus, newStatus, changedAt)
+		if err != nil {
+			return fmt.Errorf("failed to update status: %w", err)
+		}
+		if !updated {
+			return utils.NewError(utils.ErrConflict, "the transaction is no longer %s, reload it and try again", previousStatus)
+		}
+		transaction.Status = newStatus
+		if newStatus == models.StatusCompleted {
+			transaction.CompletedAt = &changedAt
+		}
+
+		entry := AuditEntry{
+			Action: models.AuditStatusChange, EntityType: models.AuditEntityTransaction, EntityID: id,
+			Label: transaction.TransactionCode, Changes: models.AuditChanges{"status": {From: previousStatus, To: newStatus}},
+		}
+		if reason != "" {
+			entry.Changes["reason"] = models.FieldChange{To: reason}
+		}
+		if err := s.audit.Record(ctx, entry); err != nil {
+			return err
+		}
+		return s.publish(ctx, events.StatusChanged{
+			Transaction:    *transaction,
+			PreviousStatus: previousStatus,
+			ChangedBy:      adminUsername,
+			Reason:         reason,
+		})
+	})
 	if err != nil {
-		return fmt.Errorf("failed to update status: %w", err)
+		return err
+	}
+	metrics.StatusTransitionsTotal.Inc(string(previousStatus), string(newStatus))
+	return nil
+}
+
+// publish stores events in the outbox with the change being made in ctx
+func (s *TransactionService) publish(ctx context.Context, evs ...events.Event) error {
+	if s.events == nil {
+		return nil
 	}
+	return s.events.Publish(ctx, evs...)
+}
+
+// Subscribe registers the status history and the search index as subscribers of transaction events
+func (s *TransactionService) Subscribe(bus *events.Bus) {
+	events.On(bus, "history", func(ctx context.Context, envelope events.Envelope, e events.TransactionCreated) error {
+		return s.recordHistory(ctx, envelope, e.Transaction.ID, "", models.StatusQueued, "system", "Transaction created")
+	})
+	events.On(bus, "history", func(ctx context.Context, envelope events.Envelope, e events.StatusChanged) error {
+		return s.recordHistory(ctx, envelope, e.Transaction.ID, e.PreviousStatus, e.Transaction.Status, e.ChangedBy, e.Reason)
+	})
+	events.On(bus, "search", func(ctx context.Context, _ events.Envelope, e events.TransactionCreated) error {
+		return s.reindexTransaction(ctx, e.Transaction.ID)
+	})
+	events.On(bus, "search", func(ctx context.Context, _ events.Envelope, e events.TransactionUpdated) error {
+		return s.reindexTransaction(ctx, e.Transaction.ID)
+	})
+}
 
-	// Record in history
+// recordHistory records a status change published by an event, once per event
+func (s *TransactionService) recordHistory(ctx context.Context, envelope events.Envelope, transactionID uint, previous, next models.TransactionStatus, changedBy, reason string) error {
 	history := &models.TransactionHistory{
-		TransactionID:  id,
-		PreviousStatus: transaction.Status,
-		NewStatus:      newStatus,
-		ChangedBy:      adminUsername,
+		TransactionID:  transactionID,
+		PreviousStatus: previous,
+		NewStatus:      next,
+		ChangedBy:      changedBy,
 		Reason:         reason,
+		EventID:        &envelope.ID,
+		CreatedAt:      envelope.OccurredAt,
+	}
+	if err := s.historyRepo.CreateHistory(ctx, history); err != nil {
+		return fmt.Errorf("failed to record status history: %w", err)
 	}
-	_ = s.historyRepo.CreateHistory(history)
-
 	return nil
 }
 
-// DeleteTransaction deletes a transaction
-func (s *TransactionService) DeleteTransaction(id uint) error {
-	err := s.transactionRepo.DeleteTransaction(id)
+// DeleteTransaction moves a transaction to the trash with the reason it was deleted. It can be
+// restored until it is purged.
+func (s *TransactionService) DeleteTransaction(ctx context.Context, id uint, reason string) error {
+	reason, err := deleteReason(reason)
 	if err != nil {
-		return fmt.Errorf("failed to delete transaction: %w", err)
+		return err
 	}
-	return nil
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		existing, err := s.transactionRepo.GetTransactionByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve transaction: %w", err)
+		}
+		if existing == nil {
+			return utils.NewError(utils.ErrNotFound, "transaction not found")
+		}
+		if err := s.transactionRepo.DeleteTransaction(ctx, id, deletedBy(ctx), reason); err != nil {
+			return fmt.Errorf("failed to delete transaction: %w", err)
+		}
+		entry := transactionAudit(models.AuditDelete, existing, nil)
+		entry.Changes = models.AuditChanges{"delete_reason": {To: reason}}
+		return s.audit.Record(ctx, entry)
+	})
 }
 
-// GetAllTransactions retrieves all transactions with pagination
-func (s *TransactionService) GetAllTransactions(limit, offset int, status string) ([]models.Transaction, int64, error) {
-	transactions, total, err := s.transactionRepo.GetAllTransactions(limit, offset, status)
-	if err != nil {
-		return nil, 0, fmt.Errorf("failed to retrieve transactions: %w", err)
+// transactionAudit describes a change of a transaction for the audit log; before or after is nil
+// when the transaction was created or deleted
+func transactionAudit(action models.AuditAction, before, after *models.Transaction) AuditEntry {
+	current := after
+	if current == nil {
+		current = before
+	}
+	return AuditEntry{
+		Action: action, EntityType: models.AuditEntityTransaction, EntityID: current.ID,
+		Label: current.TransactionCode, Before: before, After: after,
 	}
-	return transactions, total, nil
+}
+
+// validTransitions lists the statuses each status may move to
+var validTransitions = map[models.TransactionStatus][]models.TransactionStatus{
+	models.StatusQueued:        {models.StatusWashing, models.StatusCompleted},       // Antrian -> Mencuci or Selesai (cancel)
+	models.StatusWashing:       {models.StatusIroning, models.StatusCompleted},       // Mencuci -> Menyetrika or Selesai (cancel)
+	models.StatusIroning:       {models.StatusReadytoPickup, models.StatusCompleted}, // Menyetrika -> SiapDiambil or Selesai (cancel)
+	models.StatusReadytoPickup: {models.StatusCompleted},                             // SiapDiambil -> Selesai
+	models.StatusCompleted:     {},                                                   // Selesai is final state
 }
 
 // isValidStatusTransition checks if a status transition is valid
 func isValidStatusTransition(currentStatus, newStatus models.TransactionStatus) bool {
-	validTransitions := map[models.TransactionStatus][]models.TransactionStatus{
-		models.StatusQueued:        {models.StatusWashing, models.StatusCompleted},       // Antrian -> Mencuci or Selesai (cancel)
-		models.StatusWashing:       {models.StatusIroning, models.StatusCompleted},       // Mencuci -> Menyetrika or Selesai (cancel)
-		models.StatusIroning:       {models.StatusReadytoPickup, models.StatusCompleted}, // Menyetrika -> SiapDiambil or Selesai (cancel)
-		models.StatusReadytoPickup: {models.StatusCompleted},                             // SiapDiambil -> Selesai
-		models.StatusCompleted:     {},                                                   // Selesai is final state
-	}
-
 	transitions, exists := validTransitions[currentStatus]
 	if !exists {
 		return false
@@ -162,22 +354,40 @@ func isValidStatusTransition(currentStatus, newStatus models.TransactionStatus)
 	return false
 }
 
+// DashboardStats counts transactions per status
+type DashboardStats struct {
+	Queued        int64 `json:"antrian"`
+	Washing       int64 `json:"mencuci"`
+	Ironing       int64 `json:"menyetrika"`
+	ReadyToPickup int64 `json:"siap_diambil"`
+	Completed     int64 `json:"selesai"`
+	Total         int64 `json:"total"`
+}
+
 // GetDashboardStats returns dashboard statistics
-func (s *TransactionService) GetDashboardStats() (map[string]interface{}, error) {
-	stats := make(map[string]interface{})
-
-	antrian, _ := s.transactionRepo.CountTransactionsByStatus(models.StatusQueued)
-	mencuci, _ := s.transactionRepo.CountTransactionsByStatus(models.StatusWashing)
-	menyetrika, _ := s.transactionRepo.CountTransactionsByStatus(models.StatusIroning)
-	siapDiambil, _ := s.transactionRepo.CountTransactionsByStatus(models.StatusReadytoPickup)
-	selesai, _ := s.transactionRepo.CountTransactionsByStatus(models.StatusCompleted)
-
-	stats["antrian"] = antrian
-	stats["mencuci"] = mencuci
-	stats["menyetrika"] = menyetrika
-	stats["siap_diambil"] = siapDiambil
-	stats["selesai"] = selesai
-	stats["total"] = antrian + mencuci + menyetrika + siapDiambil + selesai
+func (s *TransactionService) GetDashboardStats(ctx context.Context) (*DashboardStats, error) {
+	counts := make(map[models.TransactionStatus]int64)
+	for _, status := range []models.TransactionStatus{
+		models.StatusQueued, models.StatusWashing, models.StatusIroning, models.StatusReadytoPickup, models.StatusCompleted,
+	} {
+		count, err := s.transactionRepo.CountTransactionsByStatus(ctx, status)
+		if err != nil {
+			slog.ErrorContext(ctx, "failed to count transactions by status",
+				slog.String("status", string(status)),
+				slog.String("error", err.Error()),
+			)
+			return nil, fmt.Errorf("failed to retrieve dashboard statistics: %w", err)
+		}
+		counts[status] = count
+	}
+	stats := &DashboardStats{
+		Queued:        counts[models.StatusQueued],
+		Washing:       counts[models.StatusWashing],
+		Ironing:       counts[models.StatusIroning],
+		ReadyToPickup: counts[models.StatusReadytoPickup],
+		Completed:     counts[models.StatusCompleted],
+	}
+	stats.Total = stats.Queued + stats.Washing + stats.Ironing + stats.ReadyToPickup + stats.Completed
 
 	return stats, nil
 }
@@ -0,0 +1,332 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+)
+
+// trashBatch is how many expired records the purger removes at once
+const trashBatch = 100
+
+// maxDeleteReason is the longest reason a deletion may be given, in characters
+const maxDeleteReason = 255
+
+// TrashSettings configures the trash bin
+type TrashSettings struct {
+	Retention     time.Duration // records are purged this long after their deletion, never when 0
+	PurgeInterval time.Duration
+}
+
+// TrashInfo tells when, by whom and why a record was moved to the trash
+type TrashInfo struct {
+	DeletedAt    time.Time  `json:"deleted_at"`
+	DeletedBy    string     `json:"deleted_by"`
+	DeleteReason string     `json:"delete_reason"`
+	PurgeAt      *time.Time `json:"purge_at"` // nil when the record is never purged automatically
+}
+
+// TrashedTransaction is a transaction in the trash
+type TrashedTransaction struct {
+	models.Transaction
+	TrashInfo
+}
+
+// TrashedServicePrice is a service price in the trash
+type TrashedServicePrice struct {
+	models.ServicePrice
+	TrashInfo
+}
+
+// TrashService lists, restores and purges deleted transactions and service prices. Records
+// past the retention are purged in the background.
+type TrashService struct {
+	transactionRepo  *repositories.TransactionRepository
+	servicePriceRepo *repositories.ServicePriceRepository
+	transactor       *repositories.Transactor
+	events           *EventService // optional, restored prices are not published when nil
+	audit            *AuditService
+	settings         TrashSettings
+}
+
+// NewTrashService creates a new trash service
+func NewTrashService(transactionRepo *repositories.TransactionRepository, servicePriceRepo *repositories.ServicePriceRepository, transactor *repositories.Transactor, events *EventService, audit *AuditService, settings TrashSettings) *TrashService {
+	return &TrashService{
+		transactionRepo:  transactionRepo,
+		servicePriceRepo: servicePriceRepo,
+		transactor:       transactor,
+		events:           events,
+		audit:            audit,
+		settings:         settings,
+	}
+}
+
+// ListTransactions returns a page of the transactions in the trash, most recently deleted first, and their total count
+func (s *TrashService) ListTransactions(ctx context.Context, limit, offset int) ([]TrashedTransaction, int64, error) {
+	transactions, total, err := s.transactionRepo.ListDeletedTransactions(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve deleted transactions: %w", err)
+	}
+	trashed := make([]TrashedTransaction, len(transactions))
+	for i := range transactions {
+		trashed[i] = s.trashedTransaction(&transactions[i])
+	}
+	return trashed, total, nil
+}
+
+// RestoreTransaction takes a transaction out of the trash with the items and history deleted along with it
+func (s *TrashService) RestoreTransaction(ctx context.Context, id uint) (*models.Transaction, error) {
+	var restored *models.Transaction
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		trashed, err := s.transactionRepo.GetDeletedTransaction(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve deleted transaction: %w", err)
+		}
+		if trashed == nil {
+			return utils.NewError(utils.ErrNotFound, "transaction not found in the trash")
+		}
+		if err := s.transactionRepo.RestoreTransaction(ctx, trashed); err != nil {
+			return fmt.Errorf("failed to restore transaction: %w", err)
+		}
+
+		restored, err = s.transactionRepo.GetTransactionByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve transaction: %w", err)
+		}
+		entry := transactionAudit(models.AuditRestore, nil, restored)
+		entry.Changes = models.AuditChanges{"delete_reason": {From: trashed.DeleteReason}}
+		return s.audit.Record(ctx, entry)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// PurgeTransaction permanently deletes a transaction in the trash. Transactions with payments
+// are kept for the cash book.
+func (s *TrashService) PurgeTransaction(ctx context.Context, id uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		trashed, err := s.transactionRepo.GetDeletedTransaction(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve deleted transaction: %w", err)
+		}
+		if trashed == nil {
+			return utils.NewError(utils.ErrNotFound, "transaction not found in the trash")
+		}
+		if len(trashed.Payments) > 0 {
+			return utils.NewError(utils.ErrConflict, "the transaction has payments and is kept for the cash book")
+		}
+		return s.purgeTransaction(ctx, trashed)
+	})
+}
+
+// purgeTransaction permanently deletes a transaction in the trash and audits it
+func (s *TrashService) purgeTransaction(ctx context.Context, trashed *models.Transaction) error {
+	if err := s.transactionRepo.PurgeTransaction(ctx, trashed.ID); err != nil {
+		return fmt.Errorf("failed to purge transaction: %w", err)
+	}
+	return s.audit.Record(ctx, transactionAudit(models.AuditPurge, trashed, nil))
+}
+
+// ListServicePrices returns a page of the service prices in the trash, most recently deleted first, and their total count
+func (s *TrashService) ListServicePrices(ctx context.Context, limit, offset int) ([]TrashedServicePrice, int64, error) {
+	servicePrices, total, err := s.servicePriceRepo.ListDeletedServicePrices(ctx, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve deleted service prices: %w", err)
+	}
+	trashed := make([]TrashedServicePrice, len(servicePrices))
+	for i := range servicePrices {
+		price := &servicePrices[i]
+		trashed[i] = TrashedServicePrice{ServicePrice: *price, TrashInfo: s.trashInfo(price.DeletedAt.Time, price.DeletedBy, price.DeleteReason, true)}
+	}
+	return trashed, total, nil
+}
+
+// RestoreServicePrice takes a service price out of the trash. An active price cannot be restored
+// while another active price exists for the same service type and item.
+func (s *TrashService) RestoreServicePrice(ctx context.Context, id uint) (*models.ServicePrice, error) {
+	var restored *models.ServicePrice
+	err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		trashed, err := s.servicePriceRepo.GetDeletedServicePrice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve deleted service price: %w", err)
+		}
+		if trashed == nil {
+			return utils.NewError(utils.ErrNotFound, "service price not found in the trash")
+		}
+		if trashed.IsActive {
+			existing, err := s.servicePriceRepo.GetServicePriceByTypeAndItem(ctx, trashed.ServiceType, trashed.ItemName)
+			if err != nil {
+				return fmt.Errorf("failed to check existing service price: %w", err)
+			}
+			if existing != nil {
+				return utils.NewError(utils.ErrConflict, "service price for %s - %s already exists", trashed.ServiceType, trashed.ItemName)
+			}
+		}
+		if err := s.servicePriceRepo.RestoreServicePrice(ctx, id); err != nil {
+			return fmt.Errorf("failed to restore service price: %w", err)
+		}
+
+		restored, err = s.servicePriceRepo.GetServicePriceByID(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve service price: %w", err)
+		}
+		entry := priceAudit(models.AuditRestore, nil, restored)
+		entry.Changes = models.AuditChanges{"delete_reason": {From: trashed.DeleteReason}}
+		if err := s.audit.Record(ctx, entry); err != nil {
+			return err
+		}
+		if s.events == nil {
+			return nil
+		}
+		return s.events.Publish(ctx, events.PriceChanged{Change: events.PriceRestored, ServicePrice: *restored})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return restored, nil
+}
+
+// PurgeServicePrice permanently deletes a service price in the trash
+func (s *TrashService) PurgeServicePrice(ctx context.Context, id uint) error {
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		trashed, err := s.servicePriceRepo.GetDeletedServicePrice(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to retrieve deleted service price: %w", err)
+		}
+		if trashed == nil {
+			return utils.NewError(utils.ErrNotFound, "service price not found in the trash")
+		}
+		return s.purgeServicePrice(ctx, trashed)
+	})
+}
+
+// purgeServicePrice permanently deletes a service price in the trash and audits it
+func (s *TrashService) purgeServicePrice(ctx context.Context, trashed *models.ServicePrice) error {
+	if err := s.servicePriceRepo.PurgeServicePrice(ctx, trashed.ID); err != nil {
+		return fmt.Errorf("failed to purge service price: %w", err)
+	}
+	return s.audit.Record(ctx, priceAudit(models.AuditPurge, trashed, nil))
+}
+
+// Run purges the records past the retention every purge interval until ctx is cancelled
+func (s *TrashService) Run(ctx context.Context) {
+	if s.settings.Retention == 0 {
+		return
+	}
+
+	ticker := time.NewTicker(s.settings.PurgeInterval)
+	defer ticker.Stop()
+	for {
+		s.purgeExpired(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// purgeExpired purges the records deleted longer than the retention ago, batch by batch
+func (s *TrashService) purgeExpired(ctx context.Context) {
+	cutoff := time.Now().Add(-s.settings.Retention)
+	transactions := s.purgeBatches(ctx, "transactions", func() ([]uint, error) {
+		return s.transactionRepo.ExpiredTransactionIDs(ctx, cutoff, trashBatch)
+	}, func(ctx context.Context, id uint) error {
+		trashed, err := s.transactionRepo.GetDeletedTransaction(ctx, id)
+		if err != nil || trashed == nil {
+			return err
+		}
+		return s.purgeTransaction(ctx, trashed)
+	})
+	prices := s.purgeBatches(ctx, "service prices", func() ([]uint, error) {
+		return s.servicePriceRepo.ExpiredServicePriceIDs(ctx, cutoff, trashBatch)
+	}, func(ctx context.Context, id uint) error {
+		trashed, err := s.servicePriceRepo.GetDeletedServicePrice(ctx, id)
+		if err != nil || trashed == nil {
+			return err
+		}
+		return s.purgeServicePrice(ctx, trashed)
+	})
+
+	if transactions > 0 || prices > 0 {
+		slog.InfoContext(ctx, "purged expired records from the trash",
+			slog.Int("transactions", transactions),
+			slog.Int("service_prices", prices),
+		)
+	}
+}
+
+// purgeBatches purges the records listed by expired, each in its own database transaction,
+// until none is left or one fails, and returns how many were purged
+func (s *TrashService) purgeBatches(ctx context.Context, kind string, expired func() ([]uint, error), purge func(context.Context, uint) error) int {
+	purged := 0
+	for ctx.Err() == nil {
+		ids, err := expired()
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to find expired "+kind, slog.String("error", err.Error()))
+			}
+			return purged
+		}
+		for _, id := range ids {
+			err := s.transactor.WithinTransaction(ctx, func(ctx context.Context) error { return purge(ctx, id) })
+			if err != nil {
+				if ctx.Err() == nil {
+					slog.ErrorContext(ctx, "failed to purge expired "+kind, slog.Uint64("id", uint64(id)), slog.String("error", err.Error()))
+				}
+				return purged
+			}
+			purged++
+		}
+		if len(ids) < trashBatch {
+			return purged
+		}
+	}
+	return purged
+}
+
+// trashedTransaction adds the trash details to a deleted transaction
+func (s *TrashService) trashedTransaction(transaction *models.Transaction) TrashedTransaction {
+	info := s.trashInfo(transaction.DeletedAt.Time, transaction.DeletedBy, transaction.DeleteReason, len(transaction.Payments) == 0)
+	return TrashedTransaction{Transaction: *transaction, TrashInfo: info}
+}
+
+// trashInfo describes a deletion; purgeable is false for records kept however old they are
+func (s *TrashService) trashInfo(deletedAt time.Time, deletedBy, reason string, purgeable bool) TrashInfo {
+	info := TrashInfo{DeletedAt: deletedAt, DeletedBy: deletedBy, DeleteReason: reason}
+	if purgeable && s.settings.Retention > 0 {
+		purgeAt := deletedAt.Add(s.settings.Retention)
+		info.PurgeAt = &purgeAt
+	}
+	return info
+}
+
+// deleteReason validates the reason given for a deletion
+func deleteReason(reason string) (string, error) {
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return "", utils.NewValidationError("reason", "is required")
+	}
+	if utf8.RuneCountInString(reason) > maxDeleteReason {
+		return "", utils.NewValidationError("reason", fmt.Sprintf("must be at most %d characters", maxDeleteReason))
+	}
+	return reason, nil
+}
+
+// deletedBy names the admin deleting a record, or what deleted it when no admin is signed in
+func deletedBy(ctx context.Context) string {
+	if actor, ok := utils.ActorFromContext(ctx); ok {
+		return actor.Username
+	}
+	return "system"
+}
@@ -0,0 +1,428 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/RidwanRamdhani/chronos-laundry/backend/events"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/metrics"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/models"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/repositories"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/utils"
+	"github.com/RidwanRamdhani/chronos-laundry/backend/webhook"
+)
+
+// WebhookDeliveryFilter narrows a listing of an endpoint's deliveries
+type WebhookDeliveryFilter = repositories.WebhookDeliveryFilter
+
+// webhookBatch is how many due deliveries the worker claims at once
+const webhookBatch = 50
+
+// StatusChangedEvent is the data of transaction.status_changed
+type StatusChangedEvent struct {
+	TransactionID   uint                     `json:"transaction_id"`
+	TransactionCode string                   `json:"transaction_code"`
+	PreviousStatus  models.TransactionStatus `json:"previous_status"`
+	NewStatus       models.TransactionStatus `json:"new_status"`
+	ChangedBy       string                   `json:"changed_by"`
+	Reason          string                   `json:"reason"`
+	ChangedAt       time.Time                `json:"changed_at"`
+}
+
+// PaymentRecordedEvent is the data of payment.recorded
+type PaymentRecordedEvent struct {
+	TransactionCode string          `json:"transaction_code"`
+	Payment         *models.Payment `json:"payment"`
+}
+
+// PriceUpdatedEvent is the data of price.updated
+type PriceUpdatedEvent struct {
+	Change       string               `json:"change"` // one of the events.Price constants
+	ServicePrice *models.ServicePrice `json:"service_price"`
+	Previous     *models.ServicePrice `json:"previous,omitempty"` // before an update
+}
+
+// WebhookSettings configures webhook delivery
+type WebhookSettings struct {
+	PollInterval time.Duration
+	MaxAttempts  int
+	RetryBackoff time.Duration
+	Timeout      time.Duration
+}
+
+// WebhookService manages partner webhook endpoints and delivers events to them in the
+// background, so a slow or failing endpoint never holds up the change that emitted the event
+type WebhookService struct {
+	webhookRepo *repositories.WebhookRepository
+	transactor  *repositories.Transactor
+	audit       *AuditService
+	sender      *webhook.Sender
+	settings    WebhookSettings
+	wake        chan struct{}
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(webhookRepo *repositories.WebhookRepository, transactor *repositories.Transactor, audit *AuditService, settings WebhookSettings) *WebhookService {
+	return &WebhookService{
+		webhookRepo: webhookRepo,
+		transactor:  transactor,
+		audit:       audit,
+		sender:      webhook.NewSender(settings.Timeout),
+		settings:    settings,
+		wake:        make(chan struct{}, 1),
+	}
+}
+
+// CreateEndpoint registers an endpoint with a new signing secret
+func (s *WebhookService) CreateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	if err := validateEndpoint(endpoint); err != nil {
+		return err
+	}
+	endpoint.Secret = webhook.NewSecret()
+
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.CreateEndpoint(ctx, endpoint); err != nil {
+			return fmt.Errorf("failed to create webhook endpoint: %w", err)
+		}
+		return s.audit.Record(ctx, webhookAudit(models.AuditCreate, nil, endpoint))
+	})
+}
+
+// GetEndpoint retrieves a webhook endpoint by ID
+func (s *WebhookService) GetEndpoint(ctx context.Context, id uint) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.webhookRepo.GetEndpointByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook endpoint: %w", err)
+	}
+	if endpoint == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "webhook endpoint not found")
+	}
+	return endpoint, nil
+}
+
+// ListEndpoints retrieves every webhook endpoint
+func (s *WebhookService) ListEndpoints(ctx context.Context) ([]models.WebhookEndpoint, error) {
+	endpoints, err := s.webhookRepo.GetEndpoints(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook endpoints: %w", err)
+	}
+	return endpoints, nil
+}
+
+// UpdateEndpoint saves changes to an endpoint's URL, subscriptions, description or state
+func (s *WebhookService) UpdateEndpoint(ctx context.Context, endpoint *models.WebhookEndpoint) error {
+	if err := validateEndpoint(endpoint); err != nil {
+		return err
+	}
+	existing, err := s.GetEndpoint(ctx, endpoint.ID)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+			return fmt.Errorf("failed to update webhook endpoint: %w", err)
+		}
+		return s.audit.Record(ctx, webhookAudit(models.AuditUpdate, existing, endpoint))
+	})
+}
+
+// RotateSecret replaces the signing secret of an endpoint. Deliveries sign with the new
+// secret from their next attempt.
+func (s *WebhookService) RotateSecret(ctx context.Context, id uint) (*models.WebhookEndpoint, error) {
+	endpoint, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	endpoint.Secret = webhook.NewSecret()
+	err = s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.UpdateEndpoint(ctx, endpoint); err != nil {
+			return fmt.Errorf("failed to rotate webhook secret: %w", err)
+		}
+		// The secret is never recorded, only that it changed
+		return s.audit.Record(ctx, AuditEntry{
+			Action: models.AuditRotateSecret, EntityType: models.AuditEntityWebhook, EntityID: endpoint.ID, Label: endpoint.URL,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return endpoint, nil
+}
+
+// DeleteEndpoint deletes an endpoint and its delivery log
+func (s *WebhookService) DeleteEndpoint(ctx context.Context, id uint) error {
+	existing, err := s.GetEndpoint(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.transactor.WithinTransaction(ctx, func(ctx context.Context) error {
+		if err := s.webhookRepo.DeleteEndpoint(ctx, id); err != nil {
+			return fmt.Errorf("failed to delete webhook endpoint: %w", err)
+		}
+		return s.audit.Record(ctx, webhookAudit(models.AuditDelete, existing, nil))
+	})
+}
+
+// webhookAudit describes a change of a webhook endpoint for the audit log; before or after is
+// nil when the endpoint was created or deleted
+func webhookAudit(action models.AuditAction, before, after *models.WebhookEndpoint) AuditEntry {
+	current := after
+	if current == nil {
+		current = before
+	}
+	return AuditEntry{
+		Action: action, EntityType: models.AuditEntityWebhook, EntityID: current.ID,
+		Label: current.URL, Before: before, After: after,
+	}
+}
+
+// validateEndpoint checks the URL and the subscribed events of an endpoint
+func validateEndpoint(endpoint *models.WebhookEndpoint) error {
+	var fields []utils.FieldError
+	parsed, err := url.Parse(endpoint.URL)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+		fields = append(fields, utils.FieldError{Field: "url", Message: "must be an absolute http or https URL"})
+	}
+
+	if len(endpoint.Events) == 0 {
+		fields = append(fields, utils.FieldError{Field: "events", Message: "must subscribe to at least one event"})
+	}
+	for i, event := range endpoint.Events {
+		if !webhook.IsEvent(event) {
+			fields = append(fields, utils.FieldError{Field: fmt.Sprintf("events[%d]", i), Message: fmt.Sprintf("unknown event %q", event)})
+		}
+	}
+	if len(fields) > 0 {
+		return &utils.ValidationError{Fields: fields}
+	}
+
+	slices.Sort(endpoint.Events)
+	endpoint.Events = slices.Compact(endpoint.Events)
+	return nil
+}
+
+// Subscribe turns the domain events partners can subscribe to into webhook deliveries
+func (s *WebhookService) Subscribe(bus *events.Bus) {
+	events.On(bus, "webhooks", func(ctx context.Context, envelope events.Envelope, e events.TransactionCreated) error {
+		return s.queue(ctx, envelope, webhook.EventTransactionCreated, e.Transaction)
+	})
+	events.On(bus, "webhooks", func(ctx context.Context, envelope events.Envelope, e events.StatusChanged) error {
+		return s.queue(ctx, envelope, webhook.EventTransactionStatusChanged, StatusChangedEvent{
+			TransactionID:   e.Transaction.ID,
+			TransactionCode: e.Transaction.TransactionCode,
+			PreviousStatus:  e.PreviousStatus,
+			NewStatus:       e.Transaction.Status,
+			ChangedBy:       e.ChangedBy,
+			Reason:          e.Reason,
+			ChangedAt:       envelope.OccurredAt,
+		})
+	})
+	events.On(bus, "webhooks", func(ctx context.Context, envelope events.Envelope, e events.PaymentRecorded) error {
+		return s.queue(ctx, envelope, webhook.EventPaymentRecorded, PaymentRecordedEvent{TransactionCode: e.TransactionCode, Payment: &e.Payment})
+	})
+	events.On(bus, "webhooks", func(ctx context.Context, envelope events.Envelope, e events.PriceChanged) error {
+		return s.queue(ctx, envelope, webhook.EventPriceUpdated, PriceUpdatedEvent{Change: e.Change, ServicePrice: &e.ServicePrice, Previous: e.Previous})
+	})
+}
+
+// queue queues a delivery of the event to every active endpoint subscribed to it.
+// The webhook event ID is the domain event ID, so partners can deduplicate redispatches.
+func (s *WebhookService) queue(ctx context.Context, envelope events.Envelope, event string, data any) error {
+	endpoints, err := s.webhookRepo.GetSubscribedEndpoints(ctx, event)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve subscribed webhook endpoints: %w", err)
+	}
+	if len(endpoints) == 0 {
+		return nil
+	}
+
+	payload, err := json.Marshal(webhook.Envelope{ID: envelope.ID, Type: event, CreatedAt: envelope.OccurredAt, Data: data})
+	if err != nil {
+		return fmt.Errorf("failed to encode webhook payload: %w", err)
+	}
+
+	now := time.Now()
+	deliveries := make([]models.WebhookDelivery, len(endpoints))
+	for i, endpoint := range endpoints {
+		deliveries[i] = models.WebhookDelivery{
+			EndpointID:    endpoint.ID,
+			EventID:       envelope.ID,
+			Event:         event,
+			Payload:       payload,
+			Status:        models.DeliveryPending,
+			NextAttemptAt: now,
+		}
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("failed to queue webhook deliveries: %w", err)
+	}
+	s.signal()
+	return nil
+}
+
+// signal wakes the worker up without waiting for the next poll
+func (s *WebhookService) signal() {
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// ListDeliveries returns the deliveries of an endpoint matching the filter, latest first, and their total count
+func (s *WebhookService) ListDeliveries(ctx context.Context, endpointID uint, filter WebhookDeliveryFilter) ([]models.WebhookDelivery, int64, error) {
+	if _, err := s.GetEndpoint(ctx, endpointID); err != nil {
+		return nil, 0, err
+	}
+	deliveries, total, err := s.webhookRepo.ListDeliveries(ctx, endpointID, filter)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve webhook deliveries: %w", err)
+	}
+	return deliveries, total, nil
+}
+
+// GetDelivery retrieves a delivery of an endpoint
+func (s *WebhookService) GetDelivery(ctx context.Context, endpointID, id uint) (*models.WebhookDelivery, error) {
+	delivery, err := s.webhookRepo.GetDelivery(ctx, endpointID, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve webhook delivery: %w", err)
+	}
+	if delivery == nil {
+		return nil, utils.NewError(utils.ErrNotFound, "webhook delivery not found")
+	}
+	return delivery, nil
+}
+
+// Redeliver queues a new delivery of the same event and payload to the endpoint
+func (s *WebhookService) Redeliver(ctx context.Context, endpointID, id uint) (*models.WebhookDelivery, error) {
+	endpoint, err := s.GetEndpoint(ctx, endpointID)
+	if err != nil {
+		return nil, err
+	}
+	if !endpoint.IsActive {
+		return nil, utils.NewError(utils.ErrConflict, "webhook endpoint is disabled")
+	}
+	original, err := s.GetDelivery(ctx, endpointID, id)
+	if err != nil {
+		return nil, err
+	}
+
+	redelivery := models.WebhookDelivery{
+		EndpointID:    original.EndpointID,
+		EventID:       original.EventID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        models.DeliveryPending,
+		NextAttemptAt: time.Now(),
+		RedeliveryOf:  &original.ID,
+	}
+	if err := s.webhookRepo.CreateDeliveries(ctx, []models.WebhookDelivery{redelivery}); err != nil {
+		return nil, fmt.Errorf("failed to queue webhook redelivery: %w", err)
+	}
+	s.signal()
+
+	created, err := s.webhookRepo.GetDelivery(ctx, endpointID, redelivery.ID)
+	if err != nil || created == nil {
+		return &redelivery, nil
+	}
+	return created, nil
+}
+
+// Run delivers due webhooks until ctx is cancelled. Deliveries are at least once:
+// receivers should deduplicate by event ID.
+func (s *WebhookService) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.settings.PollInterval)
+	defer ticker.Stop()
+	for {
+		s.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliverDue delivers due webhooks batch by batch until none is left
+func (s *WebhookService) deliverDue(ctx context.Context) {
+	// Claims outlive a whole batch of slow attempts before other workers may retry them
+	lease := webhookBatch*s.settings.Timeout + time.Minute
+	for ctx.Err() == nil {
+		claimed, err := s.webhookRepo.ClaimDue(ctx, time.Now(), lease, webhookBatch)
+		if err != nil {
+			if ctx.Err() == nil {
+				slog.ErrorContext(ctx, "failed to claim due webhook deliveries", slog.String("error", err.Error()))
+			}
+			return
+		}
+		for i := range claimed {
+			s.deliver(ctx, &claimed[i])
+		}
+		if len(claimed) < webhookBatch {
+			return
+		}
+	}
+}
+
+// deliver attempts one delivery and records the outcome
+func (s *WebhookService) deliver(ctx context.Context, d *models.WebhookDelivery) {
+	var result webhook.Result
+	var err error
+	switch {
+	case d.Endpoint == nil:
+		err = fmt.Errorf("webhook endpoint was deleted")
+	case !d.Endpoint.IsActive:
+		err = fmt.Errorf("webhook endpoint is disabled")
+	default:
+		result, err = s.sender.Send(ctx, webhook.Request{
+			URL:        d.Endpoint.URL,
+			Secret:     d.Endpoint.Secret,
+			Event:      d.Event,
+			EventID:    d.EventID,
+			DeliveryID: d.ID,
+			Body:       d.Payload,
+		})
+	}
+
+	d.ResponseStatus = nil
+	if result.StatusCode > 0 {
+		d.ResponseStatus = &result.StatusCode
+	}
+	d.ResponseBody = result.Body
+	d.ResponseTimeMs = result.Duration.Milliseconds()
+
+	logAttrs := []any{
+		slog.Uint64("delivery_id", uint64(d.ID)),
+		slog.Uint64("endpoint_id", uint64(d.EndpointID)),
+		slog.String("event", d.Event),
+		slog.Int("attempt", d.Attempts),
+	}
+	now := time.Now()
+	switch {
+	case err == nil:
+		metrics.WebhookAttemptsTotal.Inc(d.Event, "delivered")
+		d.Status = models.DeliveryDelivered
+		d.DeliveredAt = &now
+		d.LastError = ""
+	case d.Endpoint == nil || !d.Endpoint.IsActive || d.Attempts >= s.settings.MaxAttempts:
+		metrics.WebhookAttemptsTotal.Inc(d.Event, "failed")
+		slog.ErrorContext(ctx, "webhook delivery failed", append(logAttrs, slog.String("error", err.Error()))...)
+		d.Status = models.DeliveryFailed
+		d.LastError = err.Error()
+	default:
+		metrics.WebhookAttemptsTotal.Inc(d.Event, "retry")
+		slog.WarnContext(ctx, "webhook delivery attempt failed, retrying", append(logAttrs, slog.String("error", err.Error()))...)
+		d.NextAttemptAt = now.Add(retryDelay(s.settings.RetryBackoff, d.Attempts))
+		d.LastError = err.Error()
+	}
+
+	// Record the outcome even when shutting down
+	if err := s.webhookRepo.RecordAttempt(context.WithoutCancel(ctx), d); err != nil {
+		slog.ErrorContext(ctx, "failed to record webhook attempt", append(logAttrs, slog.String("error", err.Error()))...)
+	}
+}
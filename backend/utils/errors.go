@@ -0,0 +1,125 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// Sentinel errors classify domain failures. Check them with errors.Is.
+var (
+	ErrNotFound          = errors.New("not found")
+	ErrInvalidTransition = errors.New("invalid status transition")
+	ErrConflict          = errors.New("conflict")
+	ErrValidation        = errors.New("validation failed")
+	ErrUnauthorized      = errors.New("unauthorized")
+	ErrUnavailable       = errors.New("service unavailable")
+	ErrRateLimited       = errors.New("too many requests")
+)
+
+// DomainError is an error whose message is safe to show to API clients
+type DomainError struct {
+	Kind    error
+	Message string
+}
+
+// NewError creates a domain error of the given kind with a client-facing message
+func NewError(kind error, format string, args ...any) *DomainError {
+	return &DomainError{Kind: kind, Message: fmt.Sprintf(format, args...)}
+}
+
+func (e *DomainError) Error() string { return e.Message }
+
+// Unwrap lets errors.Is match the sentinel kind
+func (e *DomainError) Unwrap() error { return e.Kind }
+
+// FieldError describes why a single input field was rejected
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError reports invalid input, field by field
+type ValidationError struct {
+	Fields []FieldError
+}
+
+// NewValidationError creates a validation error for one field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		if field.Field == "" {
+			messages[i] = field.Message
+			continue
+		}
+		messages[i] = field.Field + ": " + field.Message
+	}
+	return strings.Join(messages, "; ")
+}
+
+// Unwrap lets errors.Is match ErrValidation
+func (e *ValidationError) Unwrap() error { return ErrValidation }
+
+// BindingError converts a gin binding error into a validation error.
+// Validator failures become one field error each; malformed bodies become a single error.
+func BindingError(err error) *ValidationError {
+	var validationErrors validator.ValidationErrors
+	if !errors.As(err, &validationErrors) {
+		return NewValidationError("body", "malformed request body")
+	}
+
+	fields := make([]FieldError, len(validationErrors))
+	for i, fieldErr := range validationErrors {
+		fields[i] = FieldError{Field: bindingFieldName(fieldErr), Message: bindingMessage(fieldErr)}
+	}
+	return &ValidationError{Fields: fields}
+}
+
+// bindingFieldName turns a validator namespace such as CreateTransactionRequest.Items[0].Quantity
+// into the JSON-style path items[0].quantity
+func bindingFieldName(fieldErr validator.FieldError) string {
+	namespace := fieldErr.Namespace()
+	if i := strings.Index(namespace, "."); i >= 0 {
+		namespace = namespace[i+1:]
+	}
+	return toSnakeCase(namespace)
+}
+
+// bindingMessage describes a failed validation tag in plain words
+func bindingMessage(fieldErr validator.FieldError) string {
+	switch fieldErr.Tag() {
+	case "required":
+		return "is required"
+	case "min":
+		return "must have at least " + fieldErr.Param() + " entries"
+	case "gt":
+		return "must be greater than " + fieldErr.Param()
+	case "gte":
+		return "must be at least " + fieldErr.Param()
+	case "oneof":
+		return "must be one of " + fieldErr.Param()
+	default:
+		return "failed the " + fieldErr.Tag() + " check"
+	}
+}
+
+// toSnakeCase converts Go field names to snake_case, leaving separators and indexes untouched
+func toSnakeCase(name string) string {
+	var b strings.Builder
+	for i, r := range name {
+		if r >= 'A' && r <= 'Z' {
+			if i > 0 && name[i-1] >= 'a' && name[i-1] <= 'z' {
+				b.WriteByte('_')
+			}
+			r += 'a' - 'A'
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
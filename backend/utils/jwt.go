@@ -2,56 +2,74 @@ package utils
 
 import (
 	"fmt"
-	"os"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
 )
 
+// Token audiences keep admin and customer tokens from being accepted in place of each other
+const (
+	AudienceAdmin    = "admin"
+	AudienceCustomer = "customer"
+)
+
 // TokenClaims represents JWT token claims
 type TokenClaims struct {
 	AdminID   uint   `json:"admin_id"`
 	Username  string `json:"username"`
 	Email     string `json:"email"`
 	FullName  string `json:"full_name"`
+	Role      string `json:"role"` // empty in tokens issued before roles, which count as staff
 	ExpiresAt int64  `json:"exp"`
 	jwt.RegisteredClaims
 }
 
+// JWTManager signs and verifies admin tokens with a configured secret and lifetime
+type JWTManager struct {
+	secret   []byte
+	tokenTTL time.Duration
+}
+
+// NewJWTManager creates a JWT manager
+func NewJWTManager(secret string, tokenTTL time.Duration) *JWTManager {
+	return &JWTManager{secret: []byte(secret), tokenTTL: tokenTTL}
+}
+
 // GenerateToken generates a JWT token for an admin
-func GenerateToken(adminID uint, username, email, fullName string, expiryHours int) (string, error) {
-	jwtSecret, err := getSecretKey()
-	if err != nil {
-		return "", err
+func (m *JWTManager) GenerateToken(adminID uint, username, email, fullName, role string) (string, error) {
+	if len(m.secret) == 0 {
+		return "", fmt.Errorf("JWT secret is not configured")
 	}
 
-	expirationTime := time.Now().Add(time.Duration(expiryHours) * time.Hour)
+	expirationTime := time.Now().Add(m.tokenTTL)
 	claims := &TokenClaims{
 		AdminID:   adminID,
 		Username:  username,
 		Email:     email,
 		FullName:  fullName,
+		Role:      role,
 		ExpiresAt: expirationTime.Unix(),
 		RegisteredClaims: jwt.RegisteredClaims{
+			Audience:  jwt.ClaimStrings{AudienceAdmin},
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
 		},
 	}
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	return token.SignedString(jwtSecret)
+	return token.SignedString(m.secret)
 }
 
 // VerifyToken verifies a JWT token and returns the claims
-func VerifyToken(tokenString string) (*TokenClaims, error) {
-	jwtSecret, err := getSecretKey()
-	if err != nil {
-		return nil, err
+func (m *JWTManager) VerifyToken(tokenString string) (*TokenClaims, error) {
+	if len(m.secret) == 0 {
+		return nil, fmt.Errorf("JWT secret is not configured")
 	}
 
 	claims := &TokenClaims{}
 	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
-		return jwtSecret, nil
-	})
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}))
 
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse token: %w", err)
@@ -61,14 +79,60 @@ func VerifyToken(tokenString string) (*TokenClaims, error) {
 		return nil, fmt.Errorf("invalid token")
 	}
 
+	// Admin tokens issued before audiences have none and stay valid until they expire
+	if len(claims.Audience) > 0 && !slices.Contains(claims.Audience, AudienceAdmin) {
+		return nil, fmt.Errorf("token is not meant for admins")
+	}
+
 	return claims, nil
 }
 
-// getSecretKey retrieves JWT secret from environment or uses default
-func getSecretKey() ([]byte, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return nil, fmt.Errorf("JWT_SECRET environment variable is not set")
+// CustomerClaims are the claims of a customer portal token; the subject is the customer's
+// phone number in international form
+type CustomerClaims struct {
+	jwt.RegisteredClaims
+}
+
+// GenerateCustomerToken generates a customer portal token for a phone number, valid for ttl
+func (m *JWTManager) GenerateCustomerToken(phone string, ttl time.Duration) (string, time.Time, error) {
+	if len(m.secret) == 0 {
+		return "", time.Time{}, fmt.Errorf("JWT secret is not configured")
+	}
+
+	expirationTime := time.Now().Add(ttl)
+	claims := &CustomerClaims{
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   phone,
+			Audience:  jwt.ClaimStrings{AudienceCustomer},
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+		},
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	signed, err := token.SignedString(m.secret)
+	return signed, expirationTime, err
+}
+
+// VerifyCustomerToken verifies a customer portal token and returns its claims. Admin tokens
+// are rejected.
+func (m *JWTManager) VerifyCustomerToken(tokenString string) (*CustomerClaims, error) {
+	if len(m.secret) == 0 {
+		return nil, fmt.Errorf("JWT secret is not configured")
 	}
-	return []byte(secret), nil
+
+	claims := &CustomerClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		return m.secret, nil
+	}, jwt.WithValidMethods([]string{jwt.SigningMethodHS256.Alg()}), jwt.WithAudience(AudienceCustomer), jwt.WithExpirationRequired())
+
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse token: %w", err)
+	}
+
+	if !token.Valid || claims.Subject == "" {
+		return nil, fmt.Errorf("invalid token")
+	}
+
+	return claims, nil
 }
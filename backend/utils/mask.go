@@ -0,0 +1,37 @@
+package utils
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaskName hides all but the first letter of every word of a name, so "Ridwan Ramdhani" becomes
+// "R***** R*******". It is shown where anyone holding a transaction code can read it.
+func MaskName(name string) string {
+	words := strings.Fields(name)
+	for i, word := range words {
+		first, size := utf8.DecodeRuneInString(word)
+		if !unicode.IsLetter(first) && !unicode.IsDigit(first) {
+			words[i] = strings.Repeat("*", utf8.RuneCountInString(word))
+			continue
+		}
+		words[i] = string(first) + strings.Repeat("*", utf8.RuneCountInString(word[size:]))
+	}
+	return strings.Join(words, " ")
+}
+
+// PhoneLastDigits returns the last n digits of a phone number, ignoring separators, or an empty
+// string when it has fewer
+func PhoneLastDigits(phone string, n int) string {
+	digits := make([]rune, 0, len(phone))
+	for _, r := range phone {
+		if r >= '0' && r <= '9' {
+			digits = append(digits, r)
+		}
+	}
+	if len(digits) < n {
+		return ""
+	}
+	return string(digits[len(digits)-n:])
+}
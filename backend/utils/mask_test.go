@@ -0,0 +1,42 @@
+package utils
+
+import "testing"
+
+func TestMaskName(t *testing.T) {
+	tests := []struct {
+		name string
+		want string
+	}{
+		{"Ridwan Ramdhani", "R***** R*******"},
+		{"  Budi   Santoso ", "B*** S******"},
+		{"A", "A"},
+		{"Ängel Ñoño", "Ä**** Ñ***"},
+		{"(Pak) Budi", "***** B***"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := MaskName(tt.name); got != tt.want {
+			t.Errorf("MaskName(%q) = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestPhoneLastDigits(t *testing.T) {
+	tests := []struct {
+		phone string
+		n     int
+		want  string
+	}{
+		{"08123456789", 4, "6789"},
+		{"+62 812-3456-789", 4, "6789"},
+		{"(0812) 3456 7089", 4, "7089"},
+		{"123", 4, ""},
+		{"12-34", 4, "1234"},
+		{"", 4, ""},
+	}
+	for _, tt := range tests {
+		if got := PhoneLastDigits(tt.phone, tt.n); got != tt.want {
+			t.Errorf("PhoneLastDigits(%q, %d) = %q, want %q", tt.phone, tt.n, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,45 @@
+package utils
+
+import "context"
+
+// contextKey is the type of values stored in a request context by this package
+type contextKey string
+
+const requestIDKey contextKey = "request_id"
+
+// WithRequestID returns a copy of ctx carrying the request ID
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or an empty string
+func RequestIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	requestID, _ := ctx.Value(requestIDKey).(string)
+	return requestID
+}
+
+const actorKey contextKey = "actor"
+
+// Actor is who makes a change and from where, recorded in the audit log
+type Actor struct {
+	AdminID  uint // 0 when not an admin, e.g. the command line or a customer
+	Username string
+	IP       string
+}
+
+// WithActor returns a copy of ctx carrying the actor making changes
+func WithActor(ctx context.Context, actor Actor) context.Context {
+	return context.WithValue(ctx, actorKey, actor)
+}
+
+// ActorFromContext returns the actor carried by ctx, reporting false when there is none
+func ActorFromContext(ctx context.Context) (Actor, bool) {
+	if ctx == nil {
+		return Actor{}, false
+	}
+	actor, ok := ctx.Value(actorKey).(Actor)
+	return actor, ok
+}
@@ -12,26 +12,81 @@ type Response struct {
 	Message string      `json:"message"`
 	Data    interface{} `json:"data,omitempty"`
 	Error   string      `json:"error,omitempty"`
+
+	// Code is a stable machine-readable error code, Details carries per-field validation errors
+	Code    string       `json:"code,omitempty"`
+	Details []FieldError `json:"details,omitempty"`
+
+	// RequestID identifies the request in server logs, quote it in support tickets
+	RequestID string `json:"request_id,omitempty"`
 }
 
+// Stable error codes returned in Response.Code
+const (
+	CodeBadRequest        = "bad_request"
+	CodeValidation        = "validation_failed"
+	CodeUnauthorized      = "unauthorized"
+	CodeForbidden         = "forbidden"
+	CodeNotFound          = "not_found"
+	CodeConflict          = "conflict"
+	CodeInvalidTransition = "invalid_transition"
+	CodeTimeout           = "timeout"
+	CodeUnavailable       = "service_unavailable"
+	CodeRateLimited       = "rate_limited"
+	CodeInternal          = "internal_error"
+)
+
 // SuccessResponse sends a successful response
 func SuccessResponse(c *gin.Context, statusCode int, message string, data interface{}) {
 	c.JSON(statusCode, Response{
-		Success: true,
-		Message: message,
-		Data:    data,
+		Success:   true,
+		Message:   message,
+		Data:      data,
+		RequestID: c.GetString("request_id"),
 	})
 }
 
-// ErrorResponse sends an error response
+// ErrorResponse sends an error response with the default code for its status
 func ErrorResponse(c *gin.Context, statusCode int, message string, err string) {
+	ErrorResponseWithCode(c, statusCode, codeForStatus(statusCode), message, err, nil)
+}
+
+// ErrorResponseWithCode sends an error response with an explicit code and optional field details
+func ErrorResponseWithCode(c *gin.Context, statusCode int, code, message, err string, details []FieldError) {
 	c.JSON(statusCode, Response{
-		Success: false,
-		Message: message,
-		Error:   err,
+		Success:   false,
+		Message:   message,
+		Error:     err,
+		Code:      code,
+		Details:   details,
+		RequestID: c.GetString("request_id"),
 	})
 }
 
+// codeForStatus maps an HTTP status to its default error code
+func codeForStatus(statusCode int) string {
+	switch statusCode {
+	case http.StatusBadRequest:
+		return CodeBadRequest
+	case http.StatusUnauthorized:
+		return CodeUnauthorized
+	case http.StatusForbidden:
+		return CodeForbidden
+	case http.StatusNotFound:
+		return CodeNotFound
+	case http.StatusConflict:
+		return CodeConflict
+	case http.StatusGatewayTimeout:
+		return CodeTimeout
+	case http.StatusServiceUnavailable:
+		return CodeUnavailable
+	case http.StatusTooManyRequests:
+		return CodeRateLimited
+	default:
+		return CodeInternal
+	}
+}
+
 // BadRequest sends a 400 error
 func BadRequest(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusBadRequest, "Bad Request", message)
@@ -61,3 +116,8 @@ func InternalServerError(c *gin.Context, message string) {
 func Conflict(c *gin.Context, message string) {
 	ErrorResponse(c, http.StatusConflict, "Conflict", message)
 }
+
+// GatewayTimeout sends a 504 error
+func GatewayTimeout(c *gin.Context, message string) {
+	ErrorResponse(c, http.StatusGatewayTimeout, "Gateway Timeout", message)
+}
@@ -8,11 +8,18 @@ import (
 	"time"
 )
 
+// Lengths of the random part of transaction codes. Codes used to carry 5 random characters,
+// few enough to enumerate the orders of a day; new codes carry 10 and old ones stay valid.
+const (
+	transactionCodeRandomLength       = 10
+	legacyTransactionCodeRandomLength = 5
+)
+
 // GenerateTransactionCode generates a unique transaction code
-// Format: CHRN-YYYYMMDD-XXXXX (where XXXXX is random alphanumeric)
+// Format: CHRN-YYYYMMDD-XXXXXXXXXX (where XXXXXXXXXX is random alphanumeric)
 func GenerateTransactionCode() string {
 	timestamp := time.Now().Format("20060102")
-	randomPart := generateRandomString(5)
+	randomPart := generateRandomString(transactionCodeRandomLength)
 	return fmt.Sprintf("CHRN-%s-%s", timestamp, randomPart)
 }
 
@@ -32,8 +39,12 @@ func generateRandomString(length int) string {
 	return string(result)
 }
 
-// IsValidTransactionCode validates if a transaction code format is correct
+// IsValidTransactionCode validates if a transaction code format is correct, accepting the
+// shorter codes issued before transactionCodeRandomLength was raised
 func IsValidTransactionCode(code string) bool {
 	parts := strings.Split(code, "-")
-	return len(parts) == 3 && parts[0] == "CHRN" && len(parts[1]) == 8 && len(parts[2]) == 5
+	if len(parts) != 3 || parts[0] != "CHRN" || len(parts[1]) != 8 {
+		return false
+	}
+	return len(parts[2]) == transactionCodeRandomLength || len(parts[2]) == legacyTransactionCodeRandomLength
 }
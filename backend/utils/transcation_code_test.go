@@ -0,0 +1,33 @@
+package utils
+
+import "testing"
+
+func TestIsValidTransactionCode(t *testing.T) {
+	tests := []struct {
+		code string
+		want bool
+	}{
+		{"CHRN-20250101-7K2QX9M4PA", true},
+		{"CHRN-20250101-7K2QX", true}, // issued before codes were lengthened
+		{"CHRN-20250101-7K2QX9", false},
+		{"CHRN-20250101-7K2QX9M4PA1", false},
+		{"CHRN-2025011-7K2QX9M4PA", false},
+		{"ABCD-20250101-7K2QX9M4PA", false},
+		{"CHRN-20250101", false},
+		{"CHRN-20250101-7K2QX-9M4PA", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := IsValidTransactionCode(tt.code); got != tt.want {
+			t.Errorf("IsValidTransactionCode(%q) = %v, want %v", tt.code, got, tt.want)
+		}
+	}
+}
+
+func TestGeneratedTransactionCodeIsValid(t *testing.T) {
+	for range 100 {
+		if code := GenerateTransactionCode(); !IsValidTransactionCode(code) {
+			t.Fatalf("generated code %q is not valid", code)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// maxResponseBody bounds how much of an endpoint's response is kept in the delivery log
+const maxResponseBody = 2048
+
+// Request is one signed delivery attempt
+type Request struct {
+	URL        string
+	Secret     string
+	Event      string
+	EventID    string
+	DeliveryID uint
+	Body       []byte
+}
+
+// Result is what the endpoint answered
+type Result struct {
+	StatusCode int // 0 when no response was received
+	Body       string
+	Duration   time.Duration
+}
+
+// Sender posts signed deliveries. Redirects are not followed: endpoints must answer 2xx themselves.
+type Sender struct {
+	client *http.Client
+}
+
+// NewSender creates a sender whose attempts time out after timeout
+func NewSender(timeout time.Duration) *Sender {
+	return &Sender{client: &http.Client{
+		Timeout: timeout,
+		CheckRedirect: func(*http.Request, []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}}
+}
+
+// Send posts the request body and fails unless the endpoint answers 2xx
+func (s *Sender) Send(ctx context.Context, r Request) (Result, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, r.URL, bytes.NewReader(r.Body))
+	if err != nil {
+		return Result{}, fmt.Errorf("invalid endpoint: %w", err)
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "Chronos-Laundry-Webhooks/1.0")
+	req.Header.Set(HeaderEvent, r.Event)
+	req.Header.Set(HeaderEventID, r.EventID)
+	req.Header.Set(HeaderDelivery, strconv.FormatUint(uint64(r.DeliveryID), 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(r.Secret, timestamp, r.Body))
+
+	start := time.Now()
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return Result{Duration: time.Since(start)}, err
+	}
+	defer resp.Body.Close()
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, maxResponseBody))
+	io.Copy(io.Discard, resp.Body)
+	result := Result{StatusCode: resp.StatusCode, Body: string(bytes.ToValidUTF8(body, nil)), Duration: time.Since(start)}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return result, errors.New("endpoint answered " + resp.Status)
+	}
+	return result, nil
+}
@@ -0,0 +1,77 @@
+// Package webhook signs and delivers event payloads to partner endpoints
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// Event types partners can subscribe to
+const (
+	EventTransactionCreated       = "transaction.created"
+	EventTransactionStatusChanged = "transaction.status_changed"
+	EventPaymentRecorded          = "payment.recorded"
+	EventPriceUpdated             = "price.updated"
+)
+
+// Events lists every event type
+var Events = []string{
+	EventTransactionCreated,
+	EventTransactionStatusChanged,
+	EventPaymentRecorded,
+	EventPriceUpdated,
+}
+
+// Request headers sent with every delivery
+const (
+	HeaderEvent     = "X-Chronos-Event"
+	HeaderEventID   = "X-Chronos-Event-Id"
+	HeaderDelivery  = "X-Chronos-Delivery"
+	HeaderTimestamp = "X-Chronos-Timestamp"
+	HeaderSignature = "X-Chronos-Signature"
+)
+
+// Envelope is the JSON body of every delivery
+type Envelope struct {
+	ID        string    `json:"id"` // the same for every delivery of the event, for deduplication
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// IsEvent reports whether name is a known event type
+func IsEvent(name string) bool {
+	return slices.Contains(Events, name)
+}
+
+// NewSecret returns a random signing secret
+func NewSecret() string {
+	return "whsec_" + randomHex(32)
+}
+
+// Sign returns the signature header value for a body sent at timestamp (Unix seconds):
+// "sha256=" followed by the hex HMAC-SHA256 of "<timestamp>.<body>" keyed with the secret
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks a signature header in constant time, as a receiver would
+func Verify(secret string, timestamp int64, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, timestamp, body)), []byte(signature))
+}
+
+// randomHex returns n random bytes, hex encoded
+func randomHex(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
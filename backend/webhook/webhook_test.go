@@ -0,0 +1,74 @@
+package webhook
+
+import (
+	"strings"
+	"testing"
+)
+
+// Signatures computed independently with
+// printf '%s' '<timestamp>.<body>' | openssl dgst -sha256 -hmac '<secret>' -hex
+func TestSign(t *testing.T) {
+	tests := []struct {
+		secret    string
+		timestamp int64
+		body      string
+		want      string
+	}{
+		{
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			body:      `{"id":"evt_1","type":"transaction.created"}`,
+			want:      "sha256=42594fbeb16efefcc98c03fa4640817567b5d1f55fd0e47efa5452c7fd05bc1a",
+		},
+		{
+			secret:    "whsec_test",
+			timestamp: 1700000000,
+			body:      "",
+			want:      "sha256=5967f3c560522fa40cf2876ebc3c3a08551dd6959aaade3b413460591895bdcc",
+		},
+	}
+	for _, tt := range tests {
+		if got := Sign(tt.secret, tt.timestamp, []byte(tt.body)); got != tt.want {
+			t.Errorf("Sign(%q, %d, %q) = %s, want %s", tt.secret, tt.timestamp, tt.body, got, tt.want)
+		}
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "whsec_test"
+	body := []byte(`{"id":"evt_1","type":"transaction.created"}`)
+	signature := "sha256=42594fbeb16efefcc98c03fa4640817567b5d1f55fd0e47efa5452c7fd05bc1a"
+
+	if !Verify(secret, 1700000000, body, signature) {
+		t.Error("Verify rejected a valid signature")
+	}
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp int64
+		body      []byte
+		signature string
+	}{
+		{"other secret", "whsec_other", 1700000000, body, signature},
+		{"other timestamp", secret, 1700000001, body, signature},
+		{"tampered body", secret, 1700000000, []byte(`{"id":"evt_2","type":"transaction.created"}`), signature},
+		{"uppercase hex", secret, 1700000000, body, strings.ToUpper(signature)},
+		{"missing prefix", secret, 1700000000, body, strings.TrimPrefix(signature, "sha256=")},
+		{"empty", secret, 1700000000, body, ""},
+	}
+	for _, tt := range tests {
+		if Verify(tt.secret, tt.timestamp, tt.body, tt.signature) {
+			t.Errorf("%s: Verify accepted an invalid signature", tt.name)
+		}
+	}
+}
+
+func TestNewSecret(t *testing.T) {
+	a, b := NewSecret(), NewSecret()
+	if !strings.HasPrefix(a, "whsec_") || len(a) != len("whsec_")+64 {
+		t.Errorf("NewSecret = %q, want whsec_ and 64 hex characters", a)
+	}
+	if a == b {
+		t.Error("NewSecret returned the same secret twice")
+	}
+}
@@ -1,4 +1,4 @@
-const API_BASE = "http://localhost:8080/api/auth/login";
+const API_BASE = "http://localhost:8080/api/v1/auth/login";
 
 const loginForm = document.getElementById("loginForm");
 const loginBtn = document.getElementById("loginBtn");
@@ -81,12 +81,17 @@ function renderPrices(items) {
 // Hapus harga layanan
 // ================================
 async function deletePrice(id) {
-    if (!confirm("Yakin ingin menghapus harga ini?")) return;
+    const reason = prompt("Alasan menghapus harga ini:");
+    if (!reason || !reason.trim()) return;
 
     try {
         const res = await fetch(`${API_BASE}/service-prices/${id}`, {
             method: "DELETE",
-            headers: { "Authorization": "Bearer " + getToken() }
+            headers: {
+                "Content-Type": "application/json",
+                "Authorization": "Bearer " + getToken()
+            },
+            body: JSON.stringify({ reason: reason.trim() })
         });
 
         const result = await res.json();
@@ -1,9 +1,10 @@
-const API_BASE = "http://localhost:8080/api";
+const API_BASE = "http://localhost:8080/api/v1";
 
 document.getElementById("btnTrack").addEventListener("click", fetchTracking);
 
 async function fetchTracking() {
     const code = document.getElementById("trackCode").value.trim();
+    const phoneLast4 = document.getElementById("trackPhone").value.trim();
     const errorBox = document.getElementById("trackError");
     const btnTrack = document.getElementById("btnTrack");
 
@@ -15,12 +16,30 @@ async function fetchTracking() {
         return;
     }
 
+    if (phoneLast4 && !/^\d{4}$/.test(phoneLast4)) {
+        showError("Masukkan tepat 4 digit terakhir nomor HP.");
+        return;
+    }
+
     // Show loading state
     btnTrack.disabled = true;
     btnTrack.innerHTML = '<span class="loading-spinner"></span> Mencari...';
 
     try {
-        const res = await fetch(`${API_BASE}/track/${code}`);
+        const query = phoneLast4 ? `?phone_last4=${encodeURIComponent(phoneLast4)}` : "";
+        const res = await fetch(`${API_BASE}/track/${encodeURIComponent(code)}${query}`);
+
+        if (res.status === 429) {
+            const retryAfter = res.headers.get("Retry-After");
+            showError(`Terlalu banyak percobaan. Coba lagi dalam ${retryAfter || "beberapa"} detik.`);
+            return;
+        }
+
+        if (res.status === 400) {
+            const json = await res.json().catch(() => ({}));
+            showError(validationMessage(json));
+            return;
+        }
 
         if (!res.ok) {
             showError("Kode transaksi tidak ditemukan. Periksa kembali kode Anda.");
@@ -40,6 +59,18 @@ async function fetchTracking() {
     }
 }
 
+// validationMessage explains a 400 response by the field it rejected
+function validationMessage(json) {
+    const fields = (json.details || []).map((d) => d.field);
+    if (fields.includes("phone_last4")) {
+        return "Nomor HP tidak cocok dengan transaksi ini.";
+    }
+    if (fields.includes("code")) {
+        return "Format kode transaksi tidak valid. Periksa kembali kode Anda.";
+    }
+    return "Permintaan tidak valid. Periksa kembali kode dan nomor HP Anda.";
+}
+
 function showError(message) {
     const errorBox = document.getElementById("trackError");
     errorBox.innerHTML = `
@@ -60,15 +91,26 @@ function renderTracking(data) {
     // Basic info
     document.getElementById("invoiceDisplay").textContent = data.transaction_code;
     document.getElementById("customerName").textContent = data.customer_name;
-    document.getElementById("itemsCount").textContent = data.items_count + " item";
-    document.getElementById("totalPrice").textContent = "Rp " + data.total_price.toLocaleString("id-ID");
-    
-    // Payment status with icon
+
+    // Order details are only returned when the phone digits were verified
+    const itemsCountEl = document.getElementById("itemsCount");
+    const totalPriceEl = document.getElementById("totalPrice");
     const isPaidEl = document.getElementById("isPaid");
-    if (data.is_paid) {
-        isPaidEl.innerHTML = '<i class="fas fa-check-circle" style="color: #28a745;"></i> Lunas';
+    if (!data.verified) {
+        const hidden = '<span style="color: #999;">Masukkan 4 digit nomor HP</span>';
+        itemsCountEl.innerHTML = hidden;
+        totalPriceEl.innerHTML = hidden;
+        isPaidEl.innerHTML = hidden;
     } else {
-        isPaidEl.innerHTML = '<i class="fas fa-times-circle" style="color: #dc3545;"></i> Belum Lunas';
+        itemsCountEl.textContent = data.items_count + " item";
+        totalPriceEl.textContent = "Rp " + data.total_price.toLocaleString("id-ID");
+
+        // Payment status with icon
+        if (data.is_paid) {
+            isPaidEl.innerHTML = '<i class="fas fa-check-circle" style="color: #28a745;"></i> Lunas';
+        } else {
+            isPaidEl.innerHTML = '<i class="fas fa-times-circle" style="color: #dc3545;"></i> Belum Lunas';
+        }
     }
     
     // Format pickup date to be more readable
@@ -92,7 +134,7 @@ function renderTracking(data) {
     highlightSteps(data.status);
 
     // Timeline
-    renderTimeline(data.status_history);
+    renderTimeline(data.verified ? data.status_history : null, data.verified);
 
     // Scroll to result
     setTimeout(() => {
@@ -221,7 +263,7 @@ function highlightSteps(currentStatus) {
     });
 }
 
-function renderTimeline(history) {
+function renderTimeline(history, verified) {
     const container = document.getElementById("statusHistory");
     container.innerHTML = "";
 
@@ -229,7 +271,7 @@ function renderTimeline(history) {
         container.innerHTML = `
             <div style="text-align: center; padding: 40px; color: #999;">
                 <i class="fas fa-inbox" style="font-size: 3rem; margin-bottom: 15px; opacity: 0.5;"></i>
-                <p style="margin: 0;">Belum ada riwayat perubahan status.</p>
+                <p style="margin: 0;">${verified ? "Belum ada riwayat perubahan status." : "Masukkan 4 digit terakhir nomor HP untuk melihat riwayat status."}</p>
             </div>
         `;
         return;
@@ -264,10 +306,6 @@ function renderTimeline(history) {
                         <i class="fas fa-calendar-alt"></i>
                         ${time}
                     </span>
-                    <span>
-                        <i class="fas fa-user"></i>
-                        ${item.changed_by}
-                    </span>
                 </div>
                 ${item.reason ? `
                     <div class="timeline-reason">
@@ -117,12 +117,17 @@ function renderItems(items) {
 // Delete Transaction
 // ===============================
 deleteBtn.addEventListener("click", async () => {
-    if (!confirm("Yakin ingin menghapus transaksi ini?")) return;
+    const reason = prompt("Alasan menghapus transaksi ini:");
+    if (!reason || !reason.trim()) return;
 
     try {
         const response = await fetch(`${API_BASE}/transactions/${trxId}`, {
             method: "DELETE",
-            headers: { "Authorization": "Bearer " + getToken() }
+            headers: {
+                "Content-Type": "application/json",
+                "Authorization": "Bearer " + getToken()
+            },
+            body: JSON.stringify({ reason: reason.trim() })
         });
 
         if (!response.ok) {
@@ -15,6 +15,10 @@ let serviceTypes = [];
 let servicePrices = {};
 let itemIndex = 0;
 
+// Items and paid flag as loaded, to work out which item endpoints to call on submit
+let originalItems = [];
+let originalPaid = false;
+
 // ==============================
 // Load service types & prices
 // ==============================
@@ -61,6 +65,9 @@ async function loadTransaction() {
         document.getElementById("pickupDate").value = trx.pickup_date;
         document.getElementById("paymentStatus").value = trx.is_paid ? "true" : "false";
 
+        originalItems = trx.items;
+        originalPaid = trx.is_paid;
+
         itemTable.innerHTML = "";
         trx.items.forEach(addItemRowFromData);
 
@@ -78,7 +85,7 @@ await loadTransaction();
 // Add row with existing data
 // ==============================
 function addItemRowFromData(item) {
-    addItemRow(item.service_type, item.item_name, item.quantity, item.unit_price);
+    addItemRow(item.service_type, item.item_name, item.quantity, item.unit_price, item.id);
 }
 
 // ==============================
@@ -89,11 +96,12 @@ addItemBtn.addEventListener("click", () => addItemRow());
 // ==============================
 // Add item row (reusable)
 // ==============================
-function addItemRow(type = "", itemName = "", qty = 1, price = 0) {
+function addItemRow(type = "", itemName = "", qty = 1, price = 0, itemId = "") {
     const rowId = "row-" + itemIndex++;
 
     const row = document.createElement("tr");
     row.setAttribute("data-row", rowId);
+    row.dataset.itemId = itemId;
 
     row.innerHTML = `
         <td>
@@ -199,27 +207,84 @@ function refreshTotal() {
 }
 
 // ==============================
-// Submit update
+// Item changes
 // ==============================
-document.getElementById("updateForm").addEventListener("submit", async (e) => {
-    e.preventDefault();
-
-    const items = [];
+// The server prices items from the catalog and only takes them through the item endpoints:
+// a row keeps its item when only the quantity changed, otherwise the old item is removed
+// and the new one added. Additions go first so the transaction never runs out of items.
+function itemRequests() {
+    const adds = [];
+    const updates = [];
+    const kept = new Set();
 
     document.querySelectorAll("tr[data-row]").forEach((row) => {
-        const type = row.querySelector(".service-type").value;
-        const name = row.querySelector(".item-name").value;
+        const item = {
+            service_type: row.querySelector(".service-type").value,
+            item_name: row.querySelector(".item-name").value,
+            quantity: parseInt(row.querySelector(".quantity").value),
+        };
+        const original = originalItems.find((i) => String(i.id) === row.dataset.itemId);
+
+        if (original && original.service_type === item.service_type && original.item_name === item.item_name) {
+            kept.add(original.id);
+            if (original.quantity !== item.quantity) {
+                updates.push({ method: "PUT", path: `/items/${original.id}`, body: { quantity: item.quantity } });
+            }
+            return;
+        }
+        adds.push({ method: "POST", path: "/items", body: item });
+    });
+
+    const removes = originalItems
+        .filter((i) => !kept.has(i.id))
+        .map((i) => ({ method: "DELETE", path: `/items/${i.id}` }));
+
+    return [...adds, ...updates, ...removes];
+}
+
+function validateItems() {
+    const rows = document.querySelectorAll("tr[data-row]");
+    if (rows.length === 0) {
+        return "Transaksi harus memiliki minimal satu item";
+    }
+    for (const row of rows) {
         const qty = parseInt(row.querySelector(".quantity").value);
-        const price = parseInt(row.querySelector(".price").value);
+        if (!row.querySelector(".service-type").value || !row.querySelector(".item-name").value || !(qty > 0)) {
+            return "Lengkapi jenis layanan, item dan jumlah setiap baris";
+        }
+    }
+    return "";
+}
 
-        items.push({
-            service_type: type,
-            item_name: name,
-            quantity: qty,
-            unit_price: price,
-        });
+async function send(method, path, body) {
+    const response = await fetch(`${API_BASE}/transactions/${trxId}${path}`, {
+        method,
+        headers: {
+            "Authorization": "Bearer " + getToken(),
+            "Content-Type": "application/json"
+        },
+        body: body ? JSON.stringify(body) : undefined
     });
 
+    const result = await response.json();
+    if (!response.ok) {
+        throw new Error(result.message || "Gagal update transaksi");
+    }
+    return result;
+}
+
+// ==============================
+// Submit update
+// ==============================
+document.getElementById("updateForm").addEventListener("submit", async (e) => {
+    e.preventDefault();
+
+    const invalid = validateItems();
+    if (invalid) {
+        alert(invalid);
+        return;
+    }
+
     const payload = {
         customer_name: document.getElementById("customerName").value.trim(),
         customer_phone: document.getElementById("customerPhone").value.trim(),
@@ -227,24 +292,21 @@ document.getElementById("updateForm").addEventListener("submit", async (e) => {
         notes: document.getElementById("notes").value.trim(),
         is_paid: document.getElementById("paymentStatus").value === "true",
         pickup_date: document.getElementById("pickupDate").value,
-        items: items,
     };
 
-    try {
-        const response = await fetch(`${API_BASE}/transactions/${trxId}`, {
-            method: "PUT",
-            headers: {
-                "Authorization": "Bearer " + getToken(),
-                "Content-Type": "application/json"
-            },
-            body: JSON.stringify(payload)
-        });
-
-        const result = await response.json();
+    // Items of a paid transaction are locked: unmark it before editing them, and mark it
+    // paid only once the items, and so the total, are final
+    const detailsFirst = originalPaid && !payload.is_paid;
 
-        if (!response.ok) {
-            alert(result.message || "Gagal update transaksi");
-            return;
+    try {
+        if (detailsFirst) {
+            await send("PUT", "", payload);
+        }
+        for (const request of itemRequests()) {
+            await send(request.method, request.path, request.body);
+        }
+        if (!detailsFirst) {
+            await send("PUT", "", payload);
         }
 
         alert("Transaksi berhasil diperbarui!");
@@ -252,6 +314,8 @@ document.getElementById("updateForm").addEventListener("submit", async (e) => {
 
     } catch (err) {
         console.error(err);
-        alert("Server error");
+        alert(err.message || "Server error");
+        // Some changes may have been saved before the failure
+        await loadTransaction();
     }
 });
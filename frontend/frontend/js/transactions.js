@@ -149,12 +149,17 @@ applyFilterBtn.addEventListener("click", () => {
 // Delete Transaction
 // =======================
 window.deleteTransaction = async function (id) {
-    if (!confirm("Yakin ingin menghapus transaksi?")) return;
+    const reason = prompt("Alasan menghapus transaksi:");
+    if (!reason || !reason.trim()) return;
 
     try {
         const response = await fetch(`${API_BASE}/transactions/${id}`, {
             method: "DELETE",
-            headers: { "Authorization": "Bearer " + getToken() }
+            headers: {
+                "Content-Type": "application/json",
+                "Authorization": "Bearer " + getToken()
+            },
+            body: JSON.stringify({ reason: reason.trim() })
         });
 
         if (response.ok) {
@@ -3,7 +3,7 @@ import 'bootstrap/dist/css/bootstrap.min.css';
 import 'bootstrap';
 
 // Base API
-export const API_BASE = "http://localhost:8080/api";
+export const API_BASE = "http://localhost:8080/api/v1";
 
 // Ambil token dari localStorage
 export function getToken() {